@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"pbi/internal/config/container"
+	"pbi/internal/pkg/entity"
+)
+
+const tanggalLayout = "2006-01-02"
+
+// replay mengembalikan event outbox ke pending supaya dikirim ulang oleh dispatcher
+func main() {
+	var filter entity.OutboxReplayFilter
+	var dari, sampai string
+
+	flag.Int64Var(&filter.DariID, "dari-id", 0, "id event awal")
+	flag.Int64Var(&filter.SampaiID, "sampai-id", 0, "id event akhir")
+	flag.StringVar(&filter.Jenis, "jenis", "", "jenis event, misal TransactionCreated")
+	flag.StringVar(&filter.Status, "status", "", "status event: pending, delivered atau failed")
+	flag.StringVar(&filter.Subscriber, "subscriber", "", "kirim ulang hanya ke subscriber ini")
+	flag.StringVar(&dari, "dari", "", "tanggal event awal (YYYY-MM-DD)")
+	flag.StringVar(&sampai, "sampai", "", "tanggal event akhir, inklusif (YYYY-MM-DD)")
+	flag.Parse()
+
+	if dari != "" {
+		t, err := time.ParseInLocation(tanggalLayout, dari, time.Local)
+		if err != nil {
+			log.Fatal("format -dari salah:", err)
+		}
+		filter.Dari = &t
+	}
+	if sampai != "" {
+		t, err := time.ParseInLocation(tanggalLayout, sampai, time.Local)
+		if err != nil {
+			log.Fatal("format -sampai salah:", err)
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.Sampai = &t
+	}
+
+	cont := container.InitContainer()
+
+	n, herr := cont.OutboxUsc.Replay(context.Background(), &filter)
+	if herr != nil {
+		log.Fatal("replay outbox failed:", herr.Err)
+	}
+
+	log.Printf("replay outbox SUCCESS: %d event", n)
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"pbi/docs" 
 	"pbi/internal/config/container"
+	"pbi/internal/helper"
 	"pbi/internal/server/http"
+	"pbi/internal/server/worker"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -30,6 +34,19 @@ import (
 func main() {
 	cont := container.InitContainer()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go worker.NewStockSweeper(cont.TrxUsc, cont.Config.Stock.SweepInterval).Run(ctx)
+	go worker.NewTrackingPoller(cont.KirimUsc, cont.Config.Tracking.PollInterval).Run(ctx)
+	// subscriber outbox didaftarkan di container, tanpa subscriber event menumpuk pending
+	if !cont.OutboxUsc.HasSubscribers() {
+		helper.LogError(errors.New("outbox: tidak ada subscriber terdaftar, event tidak akan dikirim"))
+	} else {
+		go worker.NewOutboxDispatcher(cont.OutboxUsc, cont.Config.Outbox.DispatchInterval).Run(ctx)
+	}
+	go worker.NewOutboxPurger(cont.OutboxUsc).Run(ctx)
+
 	docs.SwaggerInfo.Title = "PBI API"
 	docs.SwaggerInfo.Description = "PBI API Documentation"
 	docs.SwaggerInfo.Version = "1.0"
@@ -42,7 +59,7 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, Idempotency-Key",
 	}))
 
 	http.HttpRouteInit(app, cont)
@@ -11,14 +11,22 @@ import (
 
 
 type Config struct {
-	App AppConfig `mapstructure:",squash"`
-	DB  DBConfig  `mapstructure:",squash"`
-	JWT JWTConfig `mapstructure:",squash"`
+	App      AppConfig      `mapstructure:",squash"`
+	DB       DBConfig       `mapstructure:",squash"`
+	JWT      JWTConfig      `mapstructure:",squash"`
+	Payment  PaymentConfig  `mapstructure:",squash"`
+	Invoice  InvoiceConfig  `mapstructure:",squash"`
+	Stock    StockConfig    `mapstructure:",squash"`
+	Saldo    SaldoConfig    `mapstructure:",squash"`
+	Tracking TrackingConfig `mapstructure:",squash"`
+	Outbox   OutboxConfig   `mapstructure:",squash"`
 }
 
 type AppConfig struct {
 	Name string `mapstructure:"APP_NAME"` 
     Port int    `mapstructure:"APP_PORT"`
+	IdempotencyTTLMinutes int `mapstructure:"IDEMPOTENCY_TTL_MINUTES"`
+	IdempotencyTTL        time.Duration
 }
 
 type DBConfig struct {
@@ -41,6 +49,65 @@ type JWTConfig struct {
 	ExpireDur     time.Duration
 }
 
+// PaymentModeMock mengarahkan semua metode bayar ke provider lokal
+const PaymentModeMock = "mock"
+
+type PaymentConfig struct {
+	// Mode "mock" mengarahkan semua metode bayar ke provider lokal
+	Mode        string `mapstructure:"PAYMENT_MODE"`
+	PublicURL   string `mapstructure:"PAYMENT_PUBLIC_URL"`
+	MockSecret  string `mapstructure:"PAYMENT_MOCK_SECRET"`
+	OvoURL      string `mapstructure:"PAYMENT_OVO_URL"`
+	OvoSecret   string `mapstructure:"PAYMENT_OVO_SECRET"`
+	DanaURL     string `mapstructure:"PAYMENT_DANA_URL"`
+	DanaSecret  string `mapstructure:"PAYMENT_DANA_SECRET"`
+	GopayURL    string `mapstructure:"PAYMENT_GOPAY_URL"`
+	GopaySecret string `mapstructure:"PAYMENT_GOPAY_SECRET"`
+}
+
+type InvoiceConfig struct {
+	// Format nomor: <PREFIX>/<periode>/<kode>/<urutan>, contoh INV/20261018/PBI/000123
+	Prefix       string `mapstructure:"INVOICE_PREFIX"`
+	PlatformCode string `mapstructure:"INVOICE_PLATFORM_CODE"`
+	// Period "daily" atau "monthly", urutan dimulai ulang setiap periode
+	Period string `mapstructure:"INVOICE_PERIOD"`
+}
+
+type StockConfig struct {
+	// Lama stok ditahan untuk pesanan yang belum dibayar (menit)
+	HoldMinutes int `mapstructure:"STOCK_HOLD_MINUTES"`
+	// Jeda worker yang melepas reservasi kedaluwarsa (detik)
+	SweepSeconds  int `mapstructure:"STOCK_SWEEP_SECONDS"`
+	HoldTTL       time.Duration
+	SweepInterval time.Duration
+}
+
+type SaldoConfig struct {
+	// Potongan platform dari setiap baris penjualan yang selesai (persen)
+	BiayaPlatformPersen float64 `mapstructure:"SALDO_BIAYA_PLATFORM_PERSEN"`
+	// Nominal minimal sekali penarikan saldo (rupiah)
+	MinPenarikan int64 `mapstructure:"SALDO_MIN_PENARIKAN"`
+}
+
+type TrackingConfig struct {
+	// File JSON riwayat resi untuk provider pelacakan lokal
+	StubFile string `mapstructure:"TRACKING_STUB_FILE"`
+	// Jeda worker pelacakan, sekaligus jeda minimal melacak resi yang sama (detik)
+	PollSeconds  int `mapstructure:"TRACKING_POLL_SECONDS"`
+	PollInterval time.Duration
+}
+
+type OutboxConfig struct {
+	// Jeda worker yang mengirim event outbox ke subscriber (detik)
+	DispatchSeconds int `mapstructure:"OUTBOX_DISPATCH_SECONDS"`
+	// Batas percobaan sebelum event ditandai failed dan menunggu replay
+	MaxAttempts      int `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	DispatchInterval time.Duration
+	// Lama event delivered disimpan sebelum dihapus (hari)
+	RetentionDays int `mapstructure:"OUTBOX_RETENTION_DAYS"`
+	Retention     time.Duration
+}
+
 func Load() (*Config, error) {
 	// cwd, _ := os.Getwd()
 	// fmt.Println("WORKDIR:", cwd)
@@ -66,5 +133,56 @@ func Load() (*Config, error) {
 	cfg.DB.ConnMaxIdleTimeDur = time.Duration(cfg.DB.ConnMaxIdleTime) * time.Second
 	cfg.JWT.ExpireDur = time.Duration(cfg.JWT.ExpireMinutes) * time.Minute
 
+	if cfg.App.IdempotencyTTLMinutes <= 0 {
+		cfg.App.IdempotencyTTLMinutes = 1440
+	}
+	cfg.App.IdempotencyTTL = time.Duration(cfg.App.IdempotencyTTLMinutes) * time.Minute
+
+	if cfg.Invoice.Prefix == "" {
+		cfg.Invoice.Prefix = "INV"
+	}
+	if cfg.Invoice.PlatformCode == "" {
+		cfg.Invoice.PlatformCode = "PBI"
+	}
+	if cfg.Invoice.Period != "monthly" {
+		cfg.Invoice.Period = "daily"
+	}
+
+	if cfg.Stock.HoldMinutes <= 0 {
+		cfg.Stock.HoldMinutes = 60
+	}
+	cfg.Stock.HoldTTL = time.Duration(cfg.Stock.HoldMinutes) * time.Minute
+	if cfg.Stock.SweepSeconds <= 0 {
+		cfg.Stock.SweepSeconds = 60
+	}
+	cfg.Stock.SweepInterval = time.Duration(cfg.Stock.SweepSeconds) * time.Second
+
+	if cfg.Saldo.BiayaPlatformPersen < 0 || cfg.Saldo.BiayaPlatformPersen > 100 {
+		cfg.Saldo.BiayaPlatformPersen = 0
+	}
+	if cfg.Saldo.MinPenarikan <= 0 {
+		cfg.Saldo.MinPenarikan = 10000
+	}
+
+	if cfg.Tracking.StubFile == "" {
+		cfg.Tracking.StubFile = "tracking_stub.json"
+	}
+	if cfg.Tracking.PollSeconds <= 0 {
+		cfg.Tracking.PollSeconds = 300
+	}
+	cfg.Tracking.PollInterval = time.Duration(cfg.Tracking.PollSeconds) * time.Second
+
+	if cfg.Outbox.DispatchSeconds <= 0 {
+		cfg.Outbox.DispatchSeconds = 5
+	}
+	cfg.Outbox.DispatchInterval = time.Duration(cfg.Outbox.DispatchSeconds) * time.Second
+	if cfg.Outbox.MaxAttempts <= 0 {
+		cfg.Outbox.MaxAttempts = 10
+	}
+	if cfg.Outbox.RetentionDays <= 0 {
+		cfg.Outbox.RetentionDays = 7
+	}
+	cfg.Outbox.Retention = time.Duration(cfg.Outbox.RetentionDays) * 24 * time.Hour
+
 	return &cfg, nil
 }
@@ -21,6 +21,19 @@ type Container struct {
 	DestUsc usecase.DestinationUsecase
 	PUsc	usecase.ProductUsecase
 	TrxUsc	usecase.TransactionUsecase
+	PayUsc	usecase.PaymentUsecase
+	CartUsc	usecase.CartUsecase
+	VoucherUsc	usecase.VoucherUsecase
+	ShipUsc	usecase.ShippingUsecase
+	SellerUsc	usecase.SellerOrderUsecase
+	ReturUsc	usecase.ReturUsecase
+	AnalyticsUsc	usecase.AnalyticsUsecase
+	ReportUsc	usecase.AdminReportUsecase
+	KomisiUsc	usecase.KomisiUsecase
+	SaldoUsc	usecase.SaldoUsecase
+	KirimUsc	usecase.PengirimanUsecase
+	UlasanUsc	usecase.UlasanUsecase
+	OutboxUsc	usecase.OutboxUsecase
 }
 
 func InitContainer() *Container {
@@ -47,6 +60,38 @@ func InitContainer() *Container {
 	destinationRepo 	:= repository.NewDestinationRepo(database.Gorm)
 	productRepo			:= repository.NewProductRepository(database.Gorm)
 	transactionRepo		:= repository.NewTransactionRepo(database.Gorm)
+	paymentRepo			:= repository.NewPaymentRepo(database.Gorm)
+	idempotencyRepo		:= repository.NewIdempotencyRepo(database.Gorm)
+	cartRepo			:= repository.NewCartRepo(database.Gorm)
+	voucherRepo			:= repository.NewVoucherRepo(database.Gorm)
+	paketRepo			:= repository.NewPaketRepo(database.Gorm)
+	invoiceRepo			:= repository.NewInvoiceRepo(database.Gorm)
+	returRepo			:= repository.NewReturRepo(database.Gorm)
+	reservasiRepo		:= repository.NewReservasiRepo(database.Gorm)
+	analyticsRepo		:= repository.NewAnalyticsRepo(database.Gorm)
+	reportRepo			:= repository.NewAdminReportRepo(database.Gorm)
+	komisiRepo			:= repository.NewKomisiRepo(database.Gorm)
+	saldoRepo			:= repository.NewSaldoRepo(database.Gorm)
+	pengirimanRepo		:= repository.NewPengirimanRepo(database.Gorm)
+	ulasanRepo			:= repository.NewUlasanRepo(database.Gorm)
+	outboxRepo			:= repository.NewOutboxRepo(database.Gorm)
+
+	shippingProvider	:= usecase.NewTableShippingProvider()
+	trackingProvider	:= usecase.NewFileTrackingProvider(cfg.Tracking.StubFile)
+
+	statusMachine		:= usecase.NewTrxStatusMachine(usecase.TrxStatusDeps{
+		TrxRepo:       transactionRepo,
+		PaketRepo:     paketRepo,
+		ReservasiRepo: reservasiRepo,
+		AnalyticsRepo: analyticsRepo,
+		KomisiRepo:    komisiRepo,
+		SaldoRepo:     saldoRepo,
+		SaldoCfg:      cfg.Saldo,
+		OutboxRepo:    outboxRepo,
+		VoucherRepo:   voucherRepo,
+		ReturRepo:     returRepo,
+		PaymentRepo:   paymentRepo,
+	})
 
 	// usecases
 	addressUsc 			:= usecase.NewAddressUsecase(addressRepo)
@@ -55,8 +100,23 @@ func InitContainer() *Container {
 	categoryUsc 		:= usecase.NewCategoryUseCase(categoryRepo)
 	tokoUsc 			:= usecase.NewTokoUsecase(tokoRepo)
 	destUsc				:= usecase.NewDestinationUsecase(destinationRepo)
-	PUsc				:= usecase.NewProductUsecase(database.Gorm, productRepo)
-	TrxUsc				:= usecase.NewTransactionUsecase(database.Gorm, transactionRepo, destinationRepo)
+	PUsc				:= usecase.NewProductUsecase(database.Gorm, productRepo, outboxRepo)
+	TrxUsc				:= usecase.NewTransactionUsecase(database.Gorm, transactionRepo, destinationRepo, idempotencyRepo, cfg.App.IdempotencyTTL, voucherRepo, paketRepo, shippingProvider, invoiceRepo, cfg.Invoice, reservasiRepo, cfg.Stock.HoldTTL, statusMachine)
+	PayUsc				:= usecase.NewPaymentUsecase(database.Gorm, paymentRepo, transactionRepo, statusMachine, usecase.NewPaymentProviders(cfg.Payment))
+	CartUsc				:= usecase.NewCartUsecase(cartRepo, productRepo, TrxUsc)
+	VoucherUsc			:= usecase.NewVoucherUsecase(voucherRepo, tokoRepo)
+	ShipUsc				:= usecase.NewShippingUsecase(destinationRepo, productRepo, shippingProvider)
+	SellerUsc			:= usecase.NewSellerOrderUsecase(database.Gorm, transactionRepo, paketRepo, destinationRepo, pengirimanRepo, statusMachine)
+	ReturUsc			:= usecase.NewReturUsecase(database.Gorm, returRepo, transactionRepo, paketRepo, tokoRepo, statusMachine)
+	AnalyticsUsc		:= usecase.NewAnalyticsUsecase(analyticsRepo, tokoRepo)
+	ReportUsc			:= usecase.NewAdminReportUsecase(reportRepo)
+	KomisiUsc			:= usecase.NewKomisiUsecase(komisiRepo)
+	SaldoUsc			:= usecase.NewSaldoUsecase(database.Gorm, saldoRepo, tokoRepo, cfg.Saldo)
+	KirimUsc			:= usecase.NewPengirimanUsecase(database.Gorm, pengirimanRepo, transactionRepo, paketRepo, statusMachine, trackingProvider, cfg.Tracking.PollInterval)
+	UlasanUsc			:= usecase.NewUlasanUsecase(database.Gorm, ulasanRepo, tokoRepo)
+	OutboxUsc			:= usecase.NewOutboxUsecase(database.Gorm, outboxRepo, cfg.Outbox.MaxAttempts, cfg.Outbox.Retention)
+	// subscriber outbox di dalam proses, semua jenis event supaya tidak ada yang tertahan pending
+	OutboxUsc.Subscribe(usecase.EventLogSubscriber, usecase.LogEvent)
 
 
 	return &Container{
@@ -70,5 +130,18 @@ func InitContainer() *Container {
 		DestUsc: destUsc,
 		PUsc: PUsc,
 		TrxUsc: TrxUsc,
+		PayUsc: PayUsc,
+		CartUsc: CartUsc,
+		VoucherUsc: VoucherUsc,
+		ShipUsc: ShipUsc,
+		SellerUsc: SellerUsc,
+		ReturUsc: ReturUsc,
+		AnalyticsUsc: AnalyticsUsc,
+		ReportUsc: ReportUsc,
+		KomisiUsc: KomisiUsc,
+		SaldoUsc: SaldoUsc,
+		KirimUsc: KirimUsc,
+		UlasanUsc: UlasanUsc,
+		OutboxUsc: OutboxUsc,
 	}
 }
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS trx_status_history;
+
+ALTER TABLE trx
+DROP INDEX idx_trx_status,
+DROP COLUMN status;
@@ -0,0 +1,16 @@
+ALTER TABLE trx
+ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'pending_payment' AFTER metode_bayar,
+ADD INDEX idx_trx_status (status);
+
+CREATE TABLE trx_status_history (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_trx INT NOT NULL,
+    dari_status VARCHAR(32),
+    ke_status VARCHAR(32) NOT NULL,
+    diubah_oleh INT,
+    peran VARCHAR(32),
+    catatan VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    INDEX idx_trx_status_history_trx (id_trx)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE trx
+DROP COLUMN dibatalkan_pada,
+DROP COLUMN alasan_pembatalan,
+DROP COLUMN dibatalkan_oleh;
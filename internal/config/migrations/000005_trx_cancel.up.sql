@@ -0,0 +1,4 @@
+ALTER TABLE trx
+ADD COLUMN dibatalkan_oleh INT NULL AFTER status,
+ADD COLUMN alasan_pembatalan VARCHAR(255) NULL AFTER dibatalkan_oleh,
+ADD COLUMN dibatalkan_pada DATETIME NULL AFTER alasan_pembatalan;
@@ -0,0 +1,16 @@
+CREATE TABLE pembayaran (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_trx INT NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    referensi VARCHAR(64) NOT NULL,
+    jumlah DECIMAL(15,2) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    payment_url VARCHAR(255),
+    instruksi TEXT,
+    dibayar_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    UNIQUE INDEX ux_pembayaran_referensi (referensi),
+    INDEX idx_pembayaran_trx_status (id_trx, status)
+);
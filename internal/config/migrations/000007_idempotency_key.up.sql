@@ -0,0 +1,13 @@
+CREATE TABLE idempotency_key (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_user INT NOT NULL,
+    idem_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    id_trx INT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    UNIQUE INDEX ux_idempotency_user_key (id_user, idem_key),
+    INDEX idx_idempotency_expires (expires_at)
+);
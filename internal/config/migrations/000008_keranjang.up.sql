@@ -0,0 +1,12 @@
+CREATE TABLE keranjang (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_user INT NOT NULL,
+    id_produk INT NOT NULL,
+    kuantitas INT NOT NULL,
+    harga_saat_ditambah DECIMAL(15,2) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    FOREIGN KEY (id_produk) REFERENCES produk(id),
+    UNIQUE INDEX ux_keranjang_user_produk (id_user, id_produk)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE detail_trx
+DROP COLUMN diskon;
+
+ALTER TABLE trx
+DROP FOREIGN KEY fk_trx_voucher,
+DROP COLUMN diskon,
+DROP COLUMN id_voucher;
+
+DROP TABLE IF EXISTS voucher_pemakaian;
+DROP TABLE IF EXISTS voucher;
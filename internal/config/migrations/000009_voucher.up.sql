@@ -0,0 +1,45 @@
+CREATE TABLE voucher (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kode VARCHAR(64) NOT NULL,
+    tipe VARCHAR(16) NOT NULL,
+    nilai DECIMAL(15,2) NOT NULL,
+    min_belanja DECIMAL(15,2) NOT NULL DEFAULT 0,
+    maks_diskon DECIMAL(15,2) NOT NULL DEFAULT 0,
+    kuota INT NOT NULL,
+    terpakai INT NOT NULL DEFAULT 0,
+    limit_per_user INT NOT NULL DEFAULT 0,
+    cakupan VARCHAR(16) NOT NULL,
+    id_toko INT NULL,
+    id_category INT NULL,
+    mulai DATETIME NOT NULL,
+    berakhir DATETIME NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    dibuat_oleh INT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    FOREIGN KEY (id_category) REFERENCES category(id),
+    FOREIGN KEY (dibuat_oleh) REFERENCES user(id),
+    UNIQUE INDEX ux_voucher_kode (kode)
+);
+
+CREATE TABLE voucher_pemakaian (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_voucher INT NOT NULL,
+    id_user INT NOT NULL,
+    id_trx INT NOT NULL,
+    diskon DECIMAL(15,2) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_voucher) REFERENCES voucher(id),
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    INDEX idx_voucher_pemakaian_user (id_voucher, id_user)
+);
+
+ALTER TABLE trx
+ADD COLUMN id_voucher INT NULL AFTER harga_total,
+ADD COLUMN diskon DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER id_voucher,
+ADD CONSTRAINT fk_trx_voucher FOREIGN KEY (id_voucher) REFERENCES voucher(id);
+
+ALTER TABLE detail_trx
+ADD COLUMN diskon DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER harga_total;
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS paket_toko;
+
+ALTER TABLE trx
+DROP COLUMN ongkos_kirim;
+
+ALTER TABLE alamat
+DROP COLUMN id_kota;
+
+ALTER TABLE toko
+DROP COLUMN id_kota;
+
+ALTER TABLE produk
+DROP COLUMN berat;
@@ -0,0 +1,27 @@
+ALTER TABLE produk
+ADD COLUMN berat INT NOT NULL DEFAULT 1000 AFTER stok;
+
+ALTER TABLE toko
+ADD COLUMN id_kota VARCHAR(255) NULL AFTER url_foto;
+
+ALTER TABLE alamat
+ADD COLUMN id_kota VARCHAR(255) NULL AFTER detail_alamat;
+
+ALTER TABLE trx
+ADD COLUMN ongkos_kirim DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER diskon;
+
+CREATE TABLE paket_toko (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_trx INT NOT NULL,
+    id_toko INT NOT NULL,
+    kurir VARCHAR(32) NOT NULL,
+    layanan VARCHAR(32) NOT NULL,
+    berat INT NOT NULL,
+    ongkir DECIMAL(15,2) NOT NULL,
+    estimasi VARCHAR(32),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    UNIQUE INDEX ux_paket_toko_trx_toko (id_trx, id_toko)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE paket_toko
+DROP INDEX idx_paket_toko_inbox,
+DROP COLUMN dikirim_pada,
+DROP COLUMN diterima_pada,
+DROP COLUMN alasan_tolak,
+DROP COLUMN status;
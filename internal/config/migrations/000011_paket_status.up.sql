@@ -0,0 +1,10 @@
+ALTER TABLE paket_toko
+ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'pending_payment' AFTER estimasi,
+ADD COLUMN alasan_tolak VARCHAR(255) NULL AFTER status,
+ADD COLUMN diterima_pada DATETIME NULL AFTER alasan_tolak,
+ADD COLUMN dikirim_pada DATETIME NULL AFTER diterima_pada,
+ADD INDEX idx_paket_toko_inbox (id_toko, status, created_at);
+
+UPDATE paket_toko
+JOIN trx ON trx.id = paket_toko.id_trx
+SET paket_toko.status = trx.status;
@@ -0,0 +1,12 @@
+ALTER TABLE toko
+DROP COLUMN prefix_invoice;
+
+ALTER TABLE paket_toko
+DROP INDEX uq_paket_toko_kode_invoice,
+DROP COLUMN kode_invoice;
+
+ALTER TABLE trx
+DROP INDEX uq_trx_kode_invoice,
+MODIFY kode_invoice VARCHAR(255) NULL;
+
+DROP TABLE IF EXISTS invoice_sequence;
@@ -0,0 +1,29 @@
+CREATE TABLE invoice_sequence (
+    scope VARCHAR(64) NOT NULL PRIMARY KEY,
+    nomor INT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- invoice lama (INV-<unix>) bisa kembar, beri akhiran id sebelum dipasang unique index
+UPDATE trx
+JOIN (
+    SELECT kode_invoice
+    FROM trx
+    WHERE kode_invoice IS NOT NULL
+    GROUP BY kode_invoice
+    HAVING COUNT(*) > 1
+) dup ON dup.kode_invoice = trx.kode_invoice
+SET trx.kode_invoice = CONCAT(trx.kode_invoice, '-', trx.id);
+
+UPDATE trx SET kode_invoice = NULL WHERE kode_invoice = '';
+
+ALTER TABLE trx
+MODIFY kode_invoice VARCHAR(64) NULL,
+ADD UNIQUE INDEX uq_trx_kode_invoice (kode_invoice);
+
+ALTER TABLE paket_toko
+ADD COLUMN kode_invoice VARCHAR(64) NULL AFTER id_toko,
+ADD UNIQUE INDEX uq_paket_toko_kode_invoice (kode_invoice);
+
+ALTER TABLE toko
+ADD COLUMN prefix_invoice VARCHAR(16) NULL AFTER nama_toko;
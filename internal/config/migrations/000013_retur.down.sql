@@ -0,0 +1,7 @@
+ALTER TABLE paket_toko
+DROP COLUMN total_refund;
+
+DROP TABLE IF EXISTS refund;
+DROP TABLE IF EXISTS retur_foto;
+DROP TABLE IF EXISTS retur_item;
+DROP TABLE IF EXISTS retur;
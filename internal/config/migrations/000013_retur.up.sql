@@ -0,0 +1,61 @@
+CREATE TABLE retur (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_trx INT NOT NULL,
+    id_toko INT NOT NULL,
+    id_user INT NOT NULL,
+    alasan VARCHAR(32) NOT NULL,
+    keterangan TEXT,
+    status VARCHAR(32) NOT NULL DEFAULT 'requested',
+    restock TINYINT(1) NOT NULL DEFAULT 0,
+    catatan_penjual VARCHAR(255),
+    catatan_banding VARCHAR(255),
+    catatan_admin VARCHAR(255),
+    diputuskan_oleh INT NULL,
+    diputuskan_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    FOREIGN KEY (diputuskan_oleh) REFERENCES user(id),
+    INDEX idx_retur_toko (id_toko, status, created_at),
+    INDEX idx_retur_user (id_user, created_at)
+);
+
+CREATE TABLE retur_item (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_retur INT NOT NULL,
+    id_detail_trx INT NOT NULL,
+    kuantitas INT NOT NULL,
+    jumlah DECIMAL(15,2) NOT NULL,
+    FOREIGN KEY (id_retur) REFERENCES retur(id),
+    FOREIGN KEY (id_detail_trx) REFERENCES detail_trx(id),
+    INDEX idx_retur_item_detail (id_detail_trx)
+);
+
+CREATE TABLE retur_foto (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_retur INT NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_retur) REFERENCES retur(id)
+);
+
+CREATE TABLE refund (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_retur INT NOT NULL,
+    id_trx INT NOT NULL,
+    id_toko INT NOT NULL,
+    jumlah DECIMAL(15,2) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_retur) REFERENCES retur(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    UNIQUE INDEX ux_refund_retur (id_retur)
+);
+
+-- total refund per paket, mengurangi hak toko atas paket tersebut
+ALTER TABLE paket_toko
+ADD COLUMN total_refund DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER ongkir;
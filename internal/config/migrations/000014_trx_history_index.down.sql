@@ -0,0 +1,3 @@
+ALTER TABLE trx
+DROP INDEX idx_trx_user_created,
+DROP INDEX idx_trx_user_harga;
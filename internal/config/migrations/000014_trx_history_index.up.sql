@@ -0,0 +1,3 @@
+ALTER TABLE trx
+ADD INDEX idx_trx_user_created (id_user, created_at),
+ADD INDEX idx_trx_user_harga (id_user, harga_total);
@@ -0,0 +1,13 @@
+UPDATE produk
+JOIN (
+    SELECT id_produk, SUM(kuantitas) AS kuantitas
+    FROM stok_reservasi
+    WHERE status = 'active'
+    GROUP BY id_produk
+) ditahan ON ditahan.id_produk = produk.id
+SET produk.stok = produk.stok - ditahan.kuantitas;
+
+ALTER TABLE produk
+DROP COLUMN stok_ditahan;
+
+DROP TABLE IF EXISTS stok_reservasi;
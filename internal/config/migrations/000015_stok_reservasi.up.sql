@@ -0,0 +1,37 @@
+CREATE TABLE stok_reservasi (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_trx INT NOT NULL,
+    id_produk INT NOT NULL,
+    kuantitas INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_produk) REFERENCES produk(id),
+    INDEX idx_stok_reservasi_trx (id_trx),
+    INDEX idx_stok_reservasi_expiry (status, expires_at)
+);
+
+-- jumlah stok yang sedang ditahan pesanan belum dibayar, stok tersedia = stok - stok_ditahan
+ALTER TABLE produk
+ADD COLUMN stok_ditahan INT NOT NULL DEFAULT 0;
+
+-- pesanan pending lama sudah memotong stok: ubah potongannya menjadi reservasi aktif
+INSERT INTO stok_reservasi (id_trx, id_produk, kuantitas, status, expires_at)
+SELECT detail_trx.id_trx, log_produk.id_produk, SUM(detail_trx.kuantitas), 'active', NOW() + INTERVAL 1 HOUR
+FROM detail_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+JOIN trx ON trx.id = detail_trx.id_trx
+WHERE trx.status = 'pending_payment'
+GROUP BY detail_trx.id_trx, log_produk.id_produk;
+
+UPDATE produk
+JOIN (
+    SELECT id_produk, SUM(kuantitas) AS kuantitas
+    FROM stok_reservasi
+    WHERE status = 'active'
+    GROUP BY id_produk
+) ditahan ON ditahan.id_produk = produk.id
+SET produk.stok = produk.stok + ditahan.kuantitas,
+    produk.stok_ditahan = ditahan.kuantitas;
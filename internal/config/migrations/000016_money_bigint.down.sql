@@ -0,0 +1,39 @@
+ALTER TABLE refund
+MODIFY jumlah DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE retur_item
+MODIFY jumlah DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE paket_toko
+MODIFY ongkir DECIMAL(15,2) NOT NULL,
+MODIFY total_refund DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE voucher_pemakaian
+MODIFY diskon DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE voucher
+MODIFY min_belanja DECIMAL(15,2) NOT NULL DEFAULT 0,
+MODIFY maks_diskon DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE keranjang
+MODIFY harga_saat_ditambah DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE pembayaran
+MODIFY jumlah DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE detail_trx
+MODIFY harga_total INT,
+MODIFY diskon DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE trx
+MODIFY harga_total INT,
+MODIFY diskon DECIMAL(15,2) NOT NULL DEFAULT 0,
+MODIFY ongkos_kirim DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE produk
+MODIFY harga_reseller DECIMAL(15,2) NOT NULL,
+MODIFY harga_konsumen DECIMAL(15,2) NOT NULL;
+
+ALTER TABLE log_produk
+MODIFY harga_reseller VARCHAR(255),
+MODIFY harga_konsumen VARCHAR(255);
@@ -0,0 +1,83 @@
+-- semua nominal disimpan sebagai rupiah bulat, lihat aturan pembulatan di internal/utils/money.
+-- Setiap kolom dibulatkan eksplisit (ROUND, setengah ke atas) sebelum diubah ke BIGINT supaya
+-- hasilnya tidak bergantung pada sql_mode server.
+UPDATE log_produk
+SET harga_reseller = COALESCE(ROUND(harga_reseller), 0),
+    harga_konsumen = COALESCE(ROUND(harga_konsumen), 0);
+
+ALTER TABLE log_produk
+MODIFY harga_reseller BIGINT NOT NULL,
+MODIFY harga_konsumen BIGINT NOT NULL;
+
+UPDATE produk
+SET harga_reseller = COALESCE(ROUND(harga_reseller), 0),
+    harga_konsumen = COALESCE(ROUND(harga_konsumen), 0);
+
+ALTER TABLE produk
+MODIFY harga_reseller BIGINT NOT NULL,
+MODIFY harga_konsumen BIGINT NOT NULL;
+
+UPDATE trx
+SET harga_total = ROUND(harga_total),
+    diskon = COALESCE(ROUND(diskon), 0),
+    ongkos_kirim = COALESCE(ROUND(ongkos_kirim), 0);
+
+ALTER TABLE trx
+MODIFY harga_total BIGINT,
+MODIFY diskon BIGINT NOT NULL DEFAULT 0,
+MODIFY ongkos_kirim BIGINT NOT NULL DEFAULT 0;
+
+UPDATE detail_trx
+SET harga_total = ROUND(harga_total),
+    diskon = COALESCE(ROUND(diskon), 0);
+
+ALTER TABLE detail_trx
+MODIFY harga_total BIGINT,
+MODIFY diskon BIGINT NOT NULL DEFAULT 0;
+
+UPDATE pembayaran
+SET jumlah = COALESCE(ROUND(jumlah), 0);
+
+ALTER TABLE pembayaran
+MODIFY jumlah BIGINT NOT NULL;
+
+UPDATE keranjang
+SET harga_saat_ditambah = COALESCE(ROUND(harga_saat_ditambah), 0);
+
+ALTER TABLE keranjang
+MODIFY harga_saat_ditambah BIGINT NOT NULL;
+
+-- nilai tetap DECIMAL karena bisa berupa persen
+UPDATE voucher
+SET min_belanja = COALESCE(ROUND(min_belanja), 0),
+    maks_diskon = COALESCE(ROUND(maks_diskon), 0);
+
+ALTER TABLE voucher
+MODIFY min_belanja BIGINT NOT NULL DEFAULT 0,
+MODIFY maks_diskon BIGINT NOT NULL DEFAULT 0;
+
+UPDATE voucher_pemakaian
+SET diskon = COALESCE(ROUND(diskon), 0);
+
+ALTER TABLE voucher_pemakaian
+MODIFY diskon BIGINT NOT NULL;
+
+UPDATE paket_toko
+SET ongkir = COALESCE(ROUND(ongkir), 0),
+    total_refund = COALESCE(ROUND(total_refund), 0);
+
+ALTER TABLE paket_toko
+MODIFY ongkir BIGINT NOT NULL,
+MODIFY total_refund BIGINT NOT NULL DEFAULT 0;
+
+UPDATE retur_item
+SET jumlah = COALESCE(ROUND(jumlah), 0);
+
+ALTER TABLE retur_item
+MODIFY jumlah BIGINT NOT NULL;
+
+UPDATE refund
+SET jumlah = COALESCE(ROUND(jumlah), 0);
+
+ALTER TABLE refund
+MODIFY jumlah BIGINT NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rekap_pembeli_harian;
+DROP TABLE IF EXISTS rekap_produk_harian;
+DROP TABLE IF EXISTS rekap_toko_harian;
@@ -0,0 +1,59 @@
+-- rekap penjualan harian per toko, diisi saat pesanan dibayar dan dikurangi saat paket dibatalkan.
+-- tanggal mengikuti tanggal pesanan (trx.created_at) supaya pembatalan selalu mengurangi baris yang sama
+CREATE TABLE rekap_toko_harian (
+    id_toko INT NOT NULL,
+    tanggal DATE NOT NULL,
+    metode_bayar VARCHAR(50) NOT NULL,
+    pesanan INT NOT NULL DEFAULT 0,
+    unit INT NOT NULL DEFAULT 0,
+    pendapatan BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id_toko, tanggal, metode_bayar)
+);
+
+CREATE TABLE rekap_produk_harian (
+    id_toko INT NOT NULL,
+    tanggal DATE NOT NULL,
+    id_produk INT NOT NULL,
+    unit INT NOT NULL DEFAULT 0,
+    pendapatan BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id_toko, tanggal, id_produk)
+);
+
+CREATE TABLE rekap_pembeli_harian (
+    id_toko INT NOT NULL,
+    tanggal DATE NOT NULL,
+    id_user INT NOT NULL,
+    pesanan INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id_toko, tanggal, id_user)
+);
+
+-- isi dari pesanan yang sudah dibayar, paket yang ditolak penjual tidak dihitung
+INSERT INTO rekap_toko_harian (id_toko, tanggal, metode_bayar, pesanan, unit, pendapatan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), COALESCE(trx.metode_bayar, ''),
+       COUNT(DISTINCT detail_trx.id_trx), SUM(detail_trx.kuantitas), SUM(detail_trx.harga_total)
+FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+WHERE trx.status IN ('paid', 'processing', 'shipped', 'delivered', 'completed')
+  AND (paket_toko.id IS NULL OR paket_toko.status <> 'cancelled')
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), COALESCE(trx.metode_bayar, '');
+
+INSERT INTO rekap_produk_harian (id_toko, tanggal, id_produk, unit, pendapatan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), log_produk.id_produk,
+       SUM(detail_trx.kuantitas), SUM(detail_trx.harga_total)
+FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+WHERE trx.status IN ('paid', 'processing', 'shipped', 'delivered', 'completed')
+  AND (paket_toko.id IS NULL OR paket_toko.status <> 'cancelled')
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), log_produk.id_produk;
+
+INSERT INTO rekap_pembeli_harian (id_toko, tanggal, id_user, pesanan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), trx.id_user, COUNT(DISTINCT detail_trx.id_trx)
+FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+WHERE trx.status IN ('paid', 'processing', 'shipped', 'delivered', 'completed')
+  AND (paket_toko.id IS NULL OR paket_toko.status <> 'cancelled')
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), trx.id_user;
@@ -0,0 +1,14 @@
+ALTER TABLE rekap_produk_harian
+DROP INDEX idx_rekap_produk_tanggal;
+
+ALTER TABLE rekap_toko_harian
+DROP INDEX idx_rekap_toko_tanggal;
+
+ALTER TABLE toko
+DROP INDEX idx_toko_created;
+
+ALTER TABLE user
+DROP INDEX idx_user_created;
+
+ALTER TABLE trx
+DROP INDEX idx_trx_created_status;
@@ -0,0 +1,15 @@
+-- laporan admin membaca rentang tanggal tanpa filter toko/user
+ALTER TABLE trx
+ADD INDEX idx_trx_created_status (created_at, status);
+
+ALTER TABLE user
+ADD INDEX idx_user_created (created_at);
+
+ALTER TABLE toko
+ADD INDEX idx_toko_created (created_at);
+
+ALTER TABLE rekap_toko_harian
+ADD INDEX idx_rekap_toko_tanggal (tanggal);
+
+ALTER TABLE rekap_produk_harian
+ADD INDEX idx_rekap_produk_tanggal (tanggal);
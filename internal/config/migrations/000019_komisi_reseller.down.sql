@@ -0,0 +1 @@
+DROP TABLE IF EXISTS komisi_reseller;
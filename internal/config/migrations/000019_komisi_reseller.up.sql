@@ -0,0 +1,53 @@
+-- buku komisi reseller, satu baris per kejadian per detail_trx dan tidak pernah diubah nominalnya.
+-- margin = (harga_konsumen - harga_reseller) snapshot log_produk x kuantitas, bertanda negatif untuk pembatalan dan retur.
+-- status tertahan sampai trx completed, setelah itu tersedia untuk ditarik
+CREATE TABLE komisi_reseller (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_user INT NOT NULL,
+    id_trx INT NOT NULL,
+    id_detail_trx INT NOT NULL,
+    id_toko INT NOT NULL,
+    id_produk INT NOT NULL,
+    id_retur INT NULL,
+    jenis VARCHAR(32) NOT NULL,
+    kuantitas INT NOT NULL,
+    harga_reseller BIGINT NOT NULL,
+    harga_konsumen BIGINT NOT NULL,
+    margin BIGINT NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'tertahan',
+    tersedia_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_detail_trx) REFERENCES detail_trx(id),
+    FOREIGN KEY (id_retur) REFERENCES retur(id),
+    INDEX idx_komisi_user (id_user, created_at),
+    INDEX idx_komisi_trx (id_trx, status),
+    INDEX idx_komisi_created (created_at)
+);
+
+-- isi dari pesanan yang sudah dibayar, paket yang ditolak penjual tidak dihitung
+INSERT INTO komisi_reseller (id_user, id_trx, id_detail_trx, id_toko, id_produk, jenis, kuantitas, harga_reseller, harga_konsumen, margin, status, tersedia_pada, created_at)
+SELECT trx.id_user, trx.id, detail_trx.id, detail_trx.id_toko, log_produk.id_produk, 'penjualan', detail_trx.kuantitas,
+    log_produk.harga_reseller, log_produk.harga_konsumen,
+    GREATEST(log_produk.harga_konsumen - log_produk.harga_reseller, 0) * detail_trx.kuantitas,
+    IF(trx.status = 'completed', 'tersedia', 'tertahan'), IF(trx.status = 'completed', trx.updated_at, NULL), trx.created_at
+FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+WHERE trx.status IN ('paid', 'processing', 'shipped', 'delivered', 'completed')
+    AND (paket_toko.id IS NULL OR paket_toko.status <> 'cancelled');
+
+-- retur yang sudah disetujui mengurangi margin sebanyak kuantitas yang diretur
+INSERT INTO komisi_reseller (id_user, id_trx, id_detail_trx, id_toko, id_produk, id_retur, jenis, kuantitas, harga_reseller, harga_konsumen, margin, status, tersedia_pada, created_at)
+SELECT trx.id_user, trx.id, detail_trx.id, detail_trx.id_toko, log_produk.id_produk, retur.id, 'retur', -retur_item.kuantitas,
+    log_produk.harga_reseller, log_produk.harga_konsumen,
+    -GREATEST(log_produk.harga_konsumen - log_produk.harga_reseller, 0) * retur_item.kuantitas,
+    IF(trx.status = 'completed', 'tersedia', 'tertahan'), IF(trx.status = 'completed', retur.diputuskan_pada, NULL), retur.diputuskan_pada
+FROM retur_item
+JOIN retur ON retur.id = retur_item.id_retur
+JOIN detail_trx ON detail_trx.id = retur_item.id_detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+WHERE retur.status = 'approved';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS mutasi_saldo_toko;
+DROP TABLE IF EXISTS penarikan_saldo;
+DROP TABLE IF EXISTS rekening_toko;
+DROP TABLE IF EXISTS saldo_toko;
@@ -0,0 +1,63 @@
+-- saldo berjalan per toko, baris ini dikunci setiap kali mutasi ditulis
+CREATE TABLE saldo_toko (
+    id_toko INT PRIMARY KEY,
+    saldo BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_toko) REFERENCES toko(id)
+);
+
+-- buku saldo toko, hanya ditambah. saldo = saldo baris sebelumnya di toko yang sama + jumlah
+CREATE TABLE mutasi_saldo_toko (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_toko INT NOT NULL,
+    jenis VARCHAR(32) NOT NULL,
+    id_trx INT NULL,
+    id_detail_trx INT NULL,
+    id_refund INT NULL,
+    id_penarikan INT NULL,
+    jumlah BIGINT NOT NULL,
+    saldo BIGINT NOT NULL,
+    keterangan VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_detail_trx) REFERENCES detail_trx(id),
+    FOREIGN KEY (id_refund) REFERENCES refund(id),
+    INDEX idx_mutasi_toko (id_toko, id),
+    INDEX idx_mutasi_detail (id_detail_trx, jenis),
+    INDEX idx_mutasi_refund (id_refund),
+    INDEX idx_mutasi_penarikan (id_penarikan)
+);
+
+CREATE TABLE rekening_toko (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_toko INT NOT NULL,
+    nama_bank VARCHAR(100) NOT NULL,
+    nomor_rekening VARCHAR(50) NOT NULL,
+    atas_nama VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    UNIQUE KEY uq_rekening_toko (id_toko, nama_bank, nomor_rekening)
+);
+
+CREATE TABLE penarikan_saldo (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_toko INT NOT NULL,
+    id_rekening INT NOT NULL,
+    jumlah BIGINT NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'requested',
+    alasan_tolak VARCHAR(255),
+    referensi VARCHAR(100),
+    diputuskan_oleh INT NULL,
+    diputuskan_pada DATETIME NULL,
+    dibayar_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    FOREIGN KEY (id_rekening) REFERENCES rekening_toko(id),
+    FOREIGN KEY (diputuskan_oleh) REFERENCES user(id),
+    INDEX idx_penarikan_toko (id_toko, created_at),
+    INDEX idx_penarikan_status (status, created_at)
+);
+
+ALTER TABLE mutasi_saldo_toko ADD FOREIGN KEY (id_penarikan) REFERENCES penarikan_saldo(id);
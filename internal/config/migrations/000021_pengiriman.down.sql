@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS pengiriman_event;
+DROP TABLE IF EXISTS pengiriman;
@@ -0,0 +1,33 @@
+-- satu pengiriman per paket toko, dibuat saat penjual memasukkan nomor resi
+CREATE TABLE pengiriman (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_paket INT NOT NULL,
+    id_trx INT NOT NULL,
+    id_toko INT NOT NULL,
+    kurir VARCHAR(32) NOT NULL,
+    no_resi VARCHAR(64) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'in_transit',
+    terakhir_dilacak DATETIME NULL,
+    sampai_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_paket) REFERENCES paket_toko(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    UNIQUE KEY uq_pengiriman_paket (id_paket),
+    INDEX idx_pengiriman_trx (id_trx),
+    INDEX idx_pengiriman_lacak (status, terakhir_dilacak)
+);
+
+-- riwayat pelacakan dari kurir, event yang sama tidak dicatat dua kali
+CREATE TABLE pengiriman_event (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_pengiriman INT NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    keterangan VARCHAR(255),
+    lokasi VARCHAR(255),
+    waktu DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_pengiriman) REFERENCES pengiriman(id),
+    UNIQUE KEY uq_pengiriman_event (id_pengiriman, waktu, status)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE toko
+DROP COLUMN jumlah_ulasan,
+DROP COLUMN rating_rata;
+
+ALTER TABLE produk
+DROP COLUMN jumlah_ulasan,
+DROP COLUMN rating_rata;
+
+DROP TABLE IF EXISTS ulasan_foto;
+DROP TABLE IF EXISTS ulasan;
@@ -0,0 +1,41 @@
+-- ulasan pembeli, satu per baris detail_trx dari trx yang sudah completed
+CREATE TABLE ulasan (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_detail_trx INT NOT NULL,
+    id_trx INT NOT NULL,
+    id_produk INT NOT NULL,
+    id_toko INT NOT NULL,
+    id_user INT NOT NULL,
+    rating TINYINT NOT NULL,
+    komentar TEXT,
+    balasan TEXT NULL,
+    dibalas_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_detail_trx) REFERENCES detail_trx(id),
+    FOREIGN KEY (id_trx) REFERENCES trx(id),
+    FOREIGN KEY (id_produk) REFERENCES produk(id),
+    FOREIGN KEY (id_toko) REFERENCES toko(id),
+    FOREIGN KEY (id_user) REFERENCES user(id),
+    UNIQUE KEY uq_ulasan_detail (id_detail_trx),
+    INDEX idx_ulasan_produk (id_produk, created_at),
+    INDEX idx_ulasan_toko (id_toko, created_at),
+    CHECK (rating BETWEEN 1 AND 5)
+);
+
+CREATE TABLE ulasan_foto (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    id_ulasan INT NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (id_ulasan) REFERENCES ulasan(id)
+);
+
+-- ringkasan rating, dihitung ulang dari tabel ulasan setiap ada ulasan baru
+ALTER TABLE produk
+ADD COLUMN rating_rata DECIMAL(3,2) NOT NULL DEFAULT 0,
+ADD COLUMN jumlah_ulasan INT NOT NULL DEFAULT 0;
+
+ALTER TABLE toko
+ADD COLUMN rating_rata DECIMAL(3,2) NOT NULL DEFAULT 0,
+ADD COLUMN jumlah_ulasan INT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+ALTER TABLE trx
+DROP COLUMN sembunyikan_harga_reseller,
+DROP COLUMN telp_pengirim,
+DROP COLUMN nama_pengirim,
+DROP COLUMN dropship;
+
+ALTER TABLE alamat
+DROP COLUMN dropship;
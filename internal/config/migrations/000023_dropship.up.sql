@@ -0,0 +1,10 @@
+-- alamat penerima pesanan dropship, disimpan per trx dan tidak tampil di daftar alamat user
+ALTER TABLE alamat
+ADD COLUMN dropship TINYINT(1) NOT NULL DEFAULT 0;
+
+-- identitas reseller yang dicetak sebagai pengirim di label dan invoice pesanan dropship
+ALTER TABLE trx
+ADD COLUMN dropship TINYINT(1) NOT NULL DEFAULT 0,
+ADD COLUMN nama_pengirim VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN telp_pengirim VARCHAR(32) NOT NULL DEFAULT '',
+ADD COLUMN sembunyikan_harga_reseller TINYINT(1) NOT NULL DEFAULT 0;
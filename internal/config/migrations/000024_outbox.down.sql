@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS outbox_terkirim;
+DROP TABLE IF EXISTS outbox_event;
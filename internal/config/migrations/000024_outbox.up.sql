@@ -0,0 +1,27 @@
+-- event domain yang ditulis di db transaction yang sama dengan perubahannya,
+-- dikirim worker ke subscriber in-process minimal sekali
+CREATE TABLE outbox_event (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    jenis VARCHAR(64) NOT NULL,
+    agregat VARCHAR(32) NOT NULL,
+    id_agregat INT NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    percobaan INT NOT NULL DEFAULT 0,
+    error_terakhir TEXT NULL,
+    tersedia_pada DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    terkirim_pada DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_outbox_due (status, tersedia_pada, id),
+    INDEX idx_outbox_agregat (agregat, id_agregat, id),
+    INDEX idx_outbox_jenis (jenis, id)
+);
+
+-- subscriber yang sudah berhasil menerima event, percobaan ulang melewatinya
+CREATE TABLE outbox_terkirim (
+    id_event BIGINT NOT NULL,
+    subscriber VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id_event, subscriber),
+    FOREIGN KEY (id_event) REFERENCES outbox_event(id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE pembayaran
+DROP INDEX idx_pembayaran_perlu_refund,
+DROP COLUMN catatan_refund,
+DROP COLUMN perlu_refund;
@@ -0,0 +1,6 @@
+-- pembayaran yang diterima provider setelah trx expired/dibatalkan tetap dicatat paid,
+-- ditandai supaya dananya dikembalikan atau ditinjau admin
+ALTER TABLE pembayaran
+ADD COLUMN perlu_refund TINYINT(1) NOT NULL DEFAULT 0 AFTER dibayar_pada,
+ADD COLUMN catatan_refund VARCHAR(255) NOT NULL DEFAULT '' AFTER perlu_refund,
+ADD INDEX idx_pembayaran_perlu_refund (perlu_refund, created_at);
@@ -0,0 +1,7 @@
+DELETE FROM refund WHERE id_retur IS NULL;
+
+ALTER TABLE refund
+DROP FOREIGN KEY fk_refund_paket,
+DROP INDEX ux_refund_paket,
+DROP COLUMN id_paket,
+MODIFY id_retur INT NOT NULL;
@@ -0,0 +1,7 @@
+-- refund untuk paket yang ditolak penjual setelah dibayar, tidak terkait retur.
+-- Refund paket tidak memotong saldo toko karena penjualannya tidak pernah dikreditkan.
+ALTER TABLE refund
+MODIFY id_retur INT NULL,
+ADD COLUMN id_paket INT NULL AFTER id_retur,
+ADD CONSTRAINT fk_refund_paket FOREIGN KEY (id_paket) REFERENCES paket_toko(id),
+ADD UNIQUE INDEX ux_refund_paket (id_paket);
@@ -0,0 +1 @@
+DROP INDEX idx_outbox_retensi ON outbox_event;
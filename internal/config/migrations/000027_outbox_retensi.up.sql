@@ -0,0 +1,2 @@
+-- retensi outbox mencari event delivered berdasarkan waktu terkirim
+CREATE INDEX idx_outbox_retensi ON outbox_event (status, terkirim_pada, id);
@@ -0,0 +1,9 @@
+ALTER TABLE voucher
+ADD COLUMN nilai DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER tipe;
+
+UPDATE voucher
+SET nilai = IF(tipe = 'percent', persen, potongan);
+
+ALTER TABLE voucher
+DROP COLUMN persen,
+DROP COLUMN potongan;
@@ -0,0 +1,11 @@
+-- nilai voucher dipisah: persen untuk tipe percent, potongan rupiah bulat untuk tipe fixed
+ALTER TABLE voucher
+ADD COLUMN persen DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER tipe,
+ADD COLUMN potongan BIGINT NOT NULL DEFAULT 0 AFTER persen;
+
+UPDATE voucher
+SET persen = IF(tipe = 'percent', nilai, 0),
+    potongan = IF(tipe = 'fixed', ROUND(nilai), 0);
+
+ALTER TABLE voucher
+DROP COLUMN nilai;
@@ -0,0 +1,8 @@
+ALTER TABLE trx
+ADD COLUMN sembunyikan_harga_reseller TINYINT(1) NOT NULL DEFAULT 0 AFTER telp_pengirim;
+
+UPDATE trx
+SET sembunyikan_harga_reseller = NOT tampilkan_harga_reseller;
+
+ALTER TABLE trx
+DROP COLUMN tampilkan_harga_reseller;
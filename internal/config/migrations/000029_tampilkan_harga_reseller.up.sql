@@ -0,0 +1,5 @@
+-- harga reseller di invoice dropship hanya dicetak bila reseller memintanya, pesanan lama
+-- tidak ikut menampilkan karena invoicenya diterima pelanggan akhir
+ALTER TABLE trx
+ADD COLUMN tampilkan_harga_reseller TINYINT(1) NOT NULL DEFAULT 0 AFTER telp_pengirim,
+DROP COLUMN sembunyikan_harga_reseller;
@@ -1,10 +0,0 @@
-package helper
-
-import (
-	"fmt"
-	"time"
-)
-
-func GenerateInvoice() string {
-	return fmt.Sprintf("INV-%d", time.Now().Unix())
-}
\ No newline at end of file
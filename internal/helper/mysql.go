@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// IsDuplicateKeyError cek pelanggaran unique index MySQL (1062)
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// IsDeadlockError cek transaksi yang dibatalkan MySQL karena deadlock (1213)
+func IsDeadlockError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1213
+}
+
+// RetryOnDeadlock menjalankan fn ulang selama gagal karena deadlock, maksimal attempts kali.
+// fn harus membuka db transaction sendiri karena transaksi yang kalah deadlock sudah di-rollback.
+func RetryOnDeadlock(ctx context.Context, attempts int, fn func() error) error {
+	var err error
+	for i := 1; i <= attempts; i++ {
+		err = fn()
+		if err == nil || !IsDeadlockError(err) || i == attempts {
+			return err
+		}
+
+		// jeda acak supaya transaksi yang bertabrakan tidak langsung bertemu lagi
+		wait := time.Duration(i*20+rand.Intn(20)) * time.Millisecond
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminReportController interface {
+	GMV(ctx *fiber.Ctx) error
+	OrderStatus(ctx *fiber.Ctx) error
+	Signups(ctx *fiber.Ctx) error
+	TopCategories(ctx *fiber.Ctx) error
+	TopTokos(ctx *fiber.Ctx) error
+	PaymentMethods(ctx *fiber.Ctx) error
+	ResellerCommissions(ctx *fiber.Ctx) error
+}
+
+type adminReportImpl struct {
+	reportUsc usecase.AdminReportUsecase
+}
+
+func NewAdminReportController(reportUsc usecase.AdminReportUsecase) AdminReportController {
+	return &adminReportImpl{
+		reportUsc: reportUsc,
+	}
+}
+
+// respond membaca filter lalu mengirim laporan sebagai JSON, atau file CSV bila format=csv
+func (c *adminReportImpl) respond(ctx *fiber.Ctx, report string, fetch func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct)) error {
+	filter := &entity.AdminReportFilter{
+		Periode: ctx.Query("periode"),
+	}
+
+	if v := ctx.Query("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid dari", err.Error())
+		}
+		filter.Dari = t
+	}
+
+	if v := ctx.Query("sampai"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid sampai", err.Error())
+		}
+		filter.Sampai = t
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid limit", err.Error())
+		}
+		filter.Limit = l
+	}
+
+	switch ctx.Query("format") {
+	case "", "json":
+		data, herr := fetch(filter)
+		if herr != nil {
+			return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+		}
+		return helper.Success(ctx, "Succeed to GET data", data)
+	case "csv":
+		file, herr := c.reportUsc.CSV(ctx.Context(), report, filter)
+		if herr != nil {
+			return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+		}
+		ctx.Set(fiber.HeaderContentType, file.ContentType)
+		ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, file.FileName))
+		return ctx.Send(file.Body)
+	default:
+		return helper.BadRequest(ctx, "Invalid format", "format harus json atau csv")
+	}
+}
+
+// GMV godoc
+// @Summary      Platform GMV over time
+// @Description  Value of paid orders (after discount, including shipping) per day, week or month
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        periode query string false "day, week or month (default day)"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {object} models.AdminGMVResponse "GMV series"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/gmv [get]
+func (c *adminReportImpl) GMV(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "gmv", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.GMV(ctx.Context(), filter)
+	})
+}
+
+// OrderStatus godoc
+// @Summary      Order counts by status
+// @Description  Orders created in the date range grouped by their current status
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {array}  models.AdminStatusCountResponse "Order counts"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/order-status [get]
+func (c *adminReportImpl) OrderStatus(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "order-status", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.OrderStatus(ctx.Context(), filter)
+	})
+}
+
+// Signups godoc
+// @Summary      New users and stores
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        periode query string false "day, week or month (default day)"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {object} models.AdminSignupResponse "Signups"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/signups [get]
+func (c *adminReportImpl) Signups(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "signups", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.Signups(ctx.Context(), filter)
+	})
+}
+
+// TopCategories godoc
+// @Summary      Top categories by revenue
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        limit   query int    false "Limit (default 10, max 100)"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {array}  models.AdminCategoryResponse "Top categories"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/top-categories [get]
+func (c *adminReportImpl) TopCategories(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "top-categories", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.TopCategories(ctx.Context(), filter)
+	})
+}
+
+// TopTokos godoc
+// @Summary      Top stores by revenue
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        limit   query int    false "Limit (default 10, max 100)"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {array}  models.AdminTokoResponse "Top stores"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/top-tokos [get]
+func (c *adminReportImpl) TopTokos(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "top-tokos", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.TopTokos(ctx.Context(), filter)
+	})
+}
+
+// PaymentMethods godoc
+// @Summary      Payment method mix
+// @Description  Paid orders and their value per payment method
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {array}  models.AnalyticsPaymentMethodResponse "Payment methods"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/payment-methods [get]
+func (c *adminReportImpl) PaymentMethods(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "payment-methods", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.PaymentMethods(ctx.Context(), filter)
+	})
+}
+
+// ResellerCommissions godoc
+// @Summary      Reseller commissions
+// @Description  Net reseller margin from ledger entries posted in the date range, split into held and withdrawable
+// @Tags         Admin Report
+// @Produce      json
+// @Produce      text/csv
+// @Param        limit   query int    false "Limit (default 10, max 100)"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Param        format  query string false "json or csv" Enums(json, csv)
+// @Success      200 {array}  models.AdminResellerKomisiResponse "Reseller commissions"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/reports/reseller-commissions [get]
+func (c *adminReportImpl) ResellerCommissions(ctx *fiber.Ctx) error {
+	return c.respond(ctx, "reseller-commissions", func(filter *entity.AdminReportFilter) (interface{}, *helper.ErrorStruct) {
+		return c.reportUsc.ResellerKomisi(ctx.Context(), filter)
+	})
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsController interface {
+	Summary(ctx *fiber.Ctx) error
+	Sales(ctx *fiber.Ctx) error
+	TopProducts(ctx *fiber.Ctx) error
+	PaymentMethods(ctx *fiber.Ctx) error
+}
+
+type analyticsImpl struct {
+	analyticsUsc usecase.AnalyticsUsecase
+}
+
+func NewAnalyticsController(analyticsUsc usecase.AnalyticsUsecase) AnalyticsController {
+	return &analyticsImpl{
+		analyticsUsc: analyticsUsc,
+	}
+}
+
+// parseAnalyticsFilter membaca query yang dipakai semua endpoint analitik, rentang tanggal inklusif.
+// Bila gagal, nama parameter yang tidak valid ikut dikembalikan.
+func parseAnalyticsFilter(ctx *fiber.Ctx, userID int) (*entity.AnalyticsFilter, string, error) {
+	filter := &entity.AnalyticsFilter{
+		IDUser:  userID,
+		Periode: ctx.Query("periode"),
+		Sort:    ctx.Query("sort"),
+	}
+
+	if v := ctx.Query("id_toko"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "id_toko", err
+		}
+		filter.IDToko = id
+	}
+
+	if v := ctx.Query("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, "dari", err
+		}
+		filter.Dari = t
+	}
+
+	if v := ctx.Query("sampai"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, "sampai", err
+		}
+		filter.Sampai = t
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "limit", err
+		}
+		filter.Limit = l
+	}
+
+	return filter, "", nil
+}
+
+// Summary godoc
+// @Summary      Seller sales summary
+// @Description  Revenue, units, average order value and repeat-buyer ratio for the user's stores
+// @Tags         Seller Analytics
+// @Produce      json
+// @Param        id_toko query int    false "Store ID, all owned stores when empty"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Success      200 {object} models.AnalyticsSummaryResponse "Summary"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/analytics/summary [get]
+func (c *analyticsImpl) Summary(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter, param, err := parseAnalyticsFilter(ctx, userID)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+
+	data, herr := c.analyticsUsc.Summary(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Sales godoc
+// @Summary      Seller sales over time
+// @Description  Revenue, orders and units per day, week (starting Monday) or month
+// @Tags         Seller Analytics
+// @Produce      json
+// @Param        periode query string false "day, week or month (default day)"
+// @Param        id_toko query int    false "Store ID, all owned stores when empty"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Success      200 {object} models.AnalyticsSalesResponse "Sales series"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/analytics/sales [get]
+func (c *analyticsImpl) Sales(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter, param, err := parseAnalyticsFilter(ctx, userID)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+
+	data, herr := c.analyticsUsc.Sales(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// TopProducts godoc
+// @Summary      Seller top products
+// @Tags         Seller Analytics
+// @Produce      json
+// @Param        sort    query string false "pendapatan (default) or unit"
+// @Param        limit   query int    false "Limit (default 10, max 100)"
+// @Param        id_toko query int    false "Store ID, all owned stores when empty"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Success      200 {array}  models.AnalyticsProductResponse "Top products"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/analytics/top-products [get]
+func (c *analyticsImpl) TopProducts(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter, param, err := parseAnalyticsFilter(ctx, userID)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+
+	data, herr := c.analyticsUsc.TopProducts(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// PaymentMethods godoc
+// @Summary      Seller sales by payment method
+// @Tags         Seller Analytics
+// @Produce      json
+// @Param        id_toko query int    false "Store ID, all owned stores when empty"
+// @Param        dari    query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param        sampai  query string false "End date, inclusive (YYYY-MM-DD), default today"
+// @Success      200 {array}  models.AnalyticsPaymentMethodResponse "Sales per payment method"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/analytics/payment-methods [get]
+func (c *analyticsImpl) PaymentMethods(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter, param, err := parseAnalyticsFilter(ctx, userID)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+
+	data, herr := c.analyticsUsc.PaymentMethods(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
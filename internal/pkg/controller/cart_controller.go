@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"errors"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartController interface {
+	Add(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Remove(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Checkout(ctx *fiber.Ctx) error
+}
+
+type cartImpl struct {
+	cartUsc usecase.CartUsecase
+}
+
+func NewCartController(cartUsc usecase.CartUsecase) CartController {
+	return &cartImpl{
+		cartUsc: cartUsc,
+	}
+}
+
+// Add godoc
+// @Summary      Add item to cart
+// @Description  Add a product to the cart, quantity is summed when the product is already in the cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        request body models.CartAddRequest true "Cart item"
+// @Success      200 {object} object "Item added"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Product not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /cart [post]
+func (c *cartImpl) Add(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.CartAddRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	if herr := c.cartUsc.Add(ctx.Context(), userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", nil)
+}
+
+// Update godoc
+// @Summary      Update cart item quantity
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Cart item ID"
+// @Param        request body models.CartUpdateRequest true "Quantity"
+// @Success      200 {object} object "Item updated"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Cart item not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /cart/{id} [put]
+func (c *cartImpl) Update(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	cartID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid cart item ID")
+	}
+
+	var req models.CartUpdateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.cartUsc.UpdateKuantitas(ctx.Context(), userID, cartID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Remove godoc
+// @Summary      Remove cart item
+// @Tags         Cart
+// @Produce      json
+// @Param        id path int true "Cart item ID"
+// @Success      200 {object} object "Item removed"
+// @Failure      400 {object} object "Invalid cart item ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Cart item not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /cart/{id} [delete]
+func (c *cartImpl) Remove(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	cartID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to DELETE data", "Invalid cart item ID")
+	}
+
+	if herr := c.cartUsc.Remove(ctx.Context(), userID, cartID); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to DELETE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to DELETE data", nil)
+}
+
+// List godoc
+// @Summary      Get cart
+// @Description  Get cart items with live price and stock, flagging changed prices and missing stock
+// @Tags         Cart
+// @Produce      json
+// @Success      200 {object} models.CartResponse "Cart"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /cart [get]
+func (c *cartImpl) List(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	data, herr := c.cartUsc.List(ctx.Context(), userID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Checkout godoc
+// @Summary      Checkout cart
+// @Description  Turn selected cart lines into a transaction. Changed prices must be confirmed with konfirmasi_harga
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key header string false "Client generated key"
+// @Param        request body models.CartCheckoutRequest true "Checkout payload"
+// @Success      200 {object} models.CartCheckoutResponse "Transaction created"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Cart item not found"
+// @Failure      409 {object} object "Stale price or out of stock lines"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /cart/checkout [post]
+func (c *cartImpl) Checkout(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.CartCheckoutRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	data, herr := c.cartUsc.Checkout(ctx.Context(), userID, ctx.Get("Idempotency-Key"), &req)
+	if herr != nil {
+		var issueErr *usecase.CartIssueError
+		if errors.As(herr.Err, &issueErr) {
+			return helper.Error(ctx, herr.Code, "Failed to POST data", issueErr.Issues...)
+		}
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", data)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type KomisiController interface {
+	Saldo(ctx *fiber.Ctx) error
+	Riwayat(ctx *fiber.Ctx) error
+}
+
+type komisiImpl struct {
+	komisiUsc usecase.KomisiUsecase
+}
+
+func NewKomisiController(komisiUsc usecase.KomisiUsecase) KomisiController {
+	return &komisiImpl{
+		komisiUsc: komisiUsc,
+	}
+}
+
+// Saldo godoc
+// @Summary      Reseller commission balance
+// @Description  Net margin of the authenticated reseller. Only tersedia (orders completed) can be withdrawn
+// @Tags         Komisi
+// @Produce      json
+// @Success      200 {object} models.KomisiSaldoResponse "Balance"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /komisi/saldo [get]
+func (c *komisiImpl) Saldo(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	data, herr := c.komisiUsc.Saldo(ctx.Context(), userID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Riwayat godoc
+// @Summary      Reseller commission history
+// @Description  Ledger entries of the authenticated reseller, one per order line, newest first
+// @Tags         Komisi
+// @Produce      json
+// @Param        status query string false "tertahan or tersedia"
+// @Param        jenis  query string false "penjualan, pembatalan or retur"
+// @Param        dari   query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        page   query int    false "Page"
+// @Param        limit  query int    false "Limit (max 100)"
+// @Success      200 {object} models.KomisiListResponse "Ledger entries"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /komisi [get]
+func (c *komisiImpl) Riwayat(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter := &entity.KomisiFilter{
+		IDUser: userID,
+		Status: ctx.Query("status"),
+		Jenis:  ctx.Query("jenis"),
+	}
+
+	if v := ctx.Query("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid dari", err.Error())
+		}
+		filter.Dari = &t
+	}
+
+	if v := ctx.Query("sampai"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid sampai", err.Error())
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.Sampai = &t
+	}
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid page", err.Error())
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid limit", err.Error())
+		}
+		filter.Limit = l
+	}
+
+	data, herr := c.komisiUsc.Riwayat(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
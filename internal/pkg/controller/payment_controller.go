@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentController interface {
+	Start(ctx *fiber.Ctx) error
+	Callback(ctx *fiber.Ctx) error
+	SimulateMock(ctx *fiber.Ctx) error
+}
+
+type paymentImpl struct {
+	payUsc usecase.PaymentUsecase
+}
+
+func NewPaymentController(payUsc usecase.PaymentUsecase) PaymentController {
+	return &paymentImpl{
+		payUsc: payUsc,
+	}
+}
+
+// Start godoc
+// @Summary      Start Payment
+// @Description  Create a payment intent for a pending transaction and return the payment URL or instructions
+// @Tags         Payments
+// @Produce      json
+// @Param        id path int true "Transaction ID"
+// @Success      200 {object} models.PaymentResponse "Payment intent created"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      409 {object} object "Transaction is not awaiting payment"
+// @Failure      500 {object} object "Internal Server Error"
+// @Failure      502 {object} object "Payment provider error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/pay [post]
+func (c *paymentImpl) Start(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", "Invalid transaction ID")
+	}
+
+	data, herr := c.payUsc.Start(ctx.Context(), trxID, userID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", data)
+}
+
+// Callback godoc
+// @Summary      Payment Callback
+// @Description  Signed webhook from a payment provider. Signature is HMAC-SHA256 of the raw body in X-Signature
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider (ovo, dana, gopay, cod)"
+// @Param        X-Signature header string true "HMAC-SHA256 signature"
+// @Param        request body models.PaymentCallbackRequest true "Callback payload"
+// @Success      200 {object} object "Callback processed"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Invalid signature"
+// @Failure      404 {object} object "Payment not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Router       /payments/{provider}/callback [post]
+func (c *paymentImpl) Callback(ctx *fiber.Ctx) error {
+	herr := c.payUsc.HandleCallback(ctx.Context(), ctx.Params("provider"), ctx.Body(), ctx.Get("X-Signature"))
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", nil)
+}
+
+// SimulateMock godoc
+// @Summary      Simulate Mock Payment
+// @Description  Local mock wallet: sends a signed callback for the payment (only when PAYMENT_MODE=mock)
+// @Tags         Payments
+// @Produce      json
+// @Param        referensi path string true "Payment reference"
+// @Param        status query string false "paid (default) or failed"
+// @Success      200 {object} object "Mock payment processed"
+// @Failure      404 {object} object "Payment not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Router       /payments/mock/{referensi}/pay [post]
+func (c *paymentImpl) SimulateMock(ctx *fiber.Ctx) error {
+	herr := c.payUsc.SimulateMock(ctx.Context(), ctx.Params("referensi"), ctx.Query("status"))
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", nil)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PengirimanController interface {
+	ListByTrx(ctx *fiber.Ctx) error
+}
+
+type pengirimanImpl struct {
+	kirimUsc usecase.PengirimanUsecase
+}
+
+func NewPengirimanController(kirimUsc usecase.PengirimanUsecase) PengirimanController {
+	return &pengirimanImpl{
+		kirimUsc: kirimUsc,
+	}
+}
+
+// ListByTrx godoc
+// @Summary      Get Transaction Shipments
+// @Description  Courier, airway bill number and tracking timeline of every package in a transaction (buyer, seller or admin)
+// @Tags         Transactions
+// @Produce      json
+// @Param        id path int true "Transaction ID"
+// @Success      200 {array} models.PengirimanResponse "Shipments"
+// @Failure      400 {object} object "Invalid transaction ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/pengiriman [get]
+func (c *pengirimanImpl) ListByTrx(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid transaction ID")
+	}
+
+	data, herr := c.kirimUsc.ListByTrx(ctx.Context(), trxID, userID, isAdmin)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
@@ -5,6 +5,7 @@ import (
 	"pbi/internal/pkg/entity"
 	"pbi/internal/pkg/models"
 	"pbi/internal/pkg/usecase"
+	"pbi/internal/utils/money"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -42,6 +43,7 @@ func NewProductController(uc usecase.ProductUsecase) ProductController {
 // @Param       harga_reseller   formData number true  "Reseller price"
 // @Param       harga_konsumen   formData number true  "Consumer price"
 // @Param       stok             formData int    true  "Stock"
+// @Param       berat            formData int    false "Weight in grams (default 1000)"
 // @Param       photos           formData file   false "Product images (multiple)"
 // @Success     200 {object} object "Success create product"
 // @Failure     400 {object} object "Bad Request"
@@ -69,19 +71,19 @@ func (c *productImpl) Create(ctx *fiber.Ctx) error {
 	}
 
 	if v := ctx.FormValue("harga_reseller"); v != "" {
-		val, err := strconv.ParseFloat(v, 64)
+		val, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid harga_reseller", err.Error())
 		}
-		req.HargaReseller = val
+		req.HargaReseller = money.Rupiah(val)
 	}
 
 	if v := ctx.FormValue("harga_konsumen"); v != "" {
-		val, err := strconv.ParseFloat(v, 64)
+		val, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid harga_konsumen", err.Error())
 		}
-		req.HargaKonsumen = val
+		req.HargaKonsumen = money.Rupiah(val)
 	}
 
 	if v := ctx.FormValue("stok"); v != "" {
@@ -92,6 +94,14 @@ func (c *productImpl) Create(ctx *fiber.Ctx) error {
 		req.Stok = val
 	}
 
+	if v := ctx.FormValue("berat"); v != "" {
+		val, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid berat", err.Error())
+		}
+		req.Berat = val
+	}
+
 	form, err := ctx.MultipartForm()
 	if err == nil {
 		if files, ok := form.File["photos"]; ok {
@@ -121,12 +131,14 @@ func (c *productImpl) Create(ctx *fiber.Ctx) error {
 // @Param       id_category      formData int    false "Category ID"
 // @Param       harga_reseller   formData number false "Reseller price"
 // @Param       harga_konsumen   formData number false "Consumer price"
-// @Param       stok             formData int    false "Stock"
+// @Param       stok             formData int    false "Physical stock (stok_fisik), must not be below stok_ditahan"
+// @Param       berat            formData int    false "Weight in grams"
 // @Param       photos           formData file   false "Product images (multiple)"
 // @Success     200 {object} object "Success update product"
 // @Failure     400 {object} object "Bad Request"
 // @Failure     401 {object} object "Unauthorized"
 // @Failure     404 {object} object "Product not found"
+// @Failure     409 {object} object "Stock below reserved stock"
 // @Failure     500 {object} object "Internal Server Error"
 // @Router      /product/{id} [put]
 func (c *productImpl) Update(ctx *fiber.Ctx) error {
@@ -154,19 +166,19 @@ func (c *productImpl) Update(ctx *fiber.Ctx) error {
 	}
 
 	if v := ctx.FormValue("harga_reseller"); v != "" {
-		val, err := strconv.ParseFloat(v, 64)
+		val, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid harga_reseller", err.Error())
 		}
-		req.HargaReseller = val
+		req.HargaReseller = money.Rupiah(val)
 	}
 
 	if v := ctx.FormValue("harga_konsumen"); v != "" {
-		val, err := strconv.ParseFloat(v, 64)
+		val, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid harga_konsumen", err.Error())
 		}
-		req.HargaKonsumen = val
+		req.HargaKonsumen = money.Rupiah(val)
 	}
 
 	if v := ctx.FormValue("stok"); v != "" {
@@ -177,6 +189,14 @@ func (c *productImpl) Update(ctx *fiber.Ctx) error {
 		req.Stok = stok
 	}
 
+	if v := ctx.FormValue("berat"); v != "" {
+		berat, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid berat", err.Error())
+		}
+		req.Berat = berat
+	}
+
 	form, err := ctx.MultipartForm()
 	if err == nil {
 		if files, ok := form.File["photos"]; ok {
@@ -261,19 +281,19 @@ func (c *productImpl) GetAll(ctx *fiber.Ctx) error {
 	}
 
 	if minHarga := ctx.Query("min_harga"); minHarga != "" {
-		val, err := strconv.ParseFloat(minHarga, 64)
+		val, err := strconv.ParseInt(minHarga, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid min_harga", err.Error())
 		}
-		filter.MinHarga = val
+		filter.MinHarga = money.Rupiah(val)
 	}
 
 	if maxHarga := ctx.Query("max_harga"); maxHarga != "" {
-		val, err := strconv.ParseFloat(maxHarga, 64)
+		val, err := strconv.ParseInt(maxHarga, 10, 64)
 		if err != nil {
 			return helper.BadRequest(ctx, "Invalid max_harga", err.Error())
 		}
-		filter.MaxHarga = val
+		filter.MaxHarga = money.Rupiah(val)
 	}
 
 	if page := ctx.Query("page"); page != "" {
@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"encoding/json"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReturController interface {
+	Create(ctx *fiber.Ctx) error
+	ListMy(ctx *fiber.Ctx) error
+	ListToko(ctx *fiber.Ctx) error
+	ListAll(ctx *fiber.Ctx) error
+	GetByID(ctx *fiber.Ctx) error
+	Approve(ctx *fiber.Ctx) error
+	Reject(ctx *fiber.Ctx) error
+	Dispute(ctx *fiber.Ctx) error
+	Resolve(ctx *fiber.Ctx) error
+}
+
+type returImpl struct {
+	returUsc usecase.ReturUsecase
+}
+
+func NewReturController(returUsc usecase.ReturUsecase) ReturController {
+	return &returImpl{
+		returUsc: returUsc,
+	}
+}
+
+// Create godoc
+// @Summary     Request a return
+// @Description Return delivered items from one toko with photo evidence (multipart/form-data)
+// @Tags        Retur
+// @Accept      multipart/form-data
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id_trx     formData int    true  "Transaction ID"
+// @Param       alasan     formData string true  "Reason" Enums(rusak, salah_kirim, tidak_sesuai, kurang, lainnya)
+// @Param       keterangan formData string false "Description"
+// @Param       items      formData string true  "JSON array, e.g. [{\"id_detail_trx\":1,\"kuantitas\":1}]"
+// @Param       photos     formData file   false "Evidence photos (multiple)"
+// @Success     200 {object} object "Return requested"
+// @Failure     400 {object} object "Bad Request"
+// @Failure     401 {object} object "Unauthorized"
+// @Failure     404 {object} object "Transaction not found"
+// @Failure     409 {object} object "Package not delivered yet"
+// @Failure     500 {object} object "Internal Server Error"
+// @Router      /retur [post]
+func (c *returImpl) Create(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.ReturCreateRequest
+
+	req.Alasan = ctx.FormValue("alasan")
+	req.Keterangan = ctx.FormValue("keterangan")
+
+	if v := ctx.FormValue("id_trx"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid id_trx", err.Error())
+		}
+		req.IDTrx = id
+	}
+
+	if v := ctx.FormValue("items"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Items); err != nil {
+			return helper.BadRequest(ctx, "Invalid items", err.Error())
+		}
+	}
+
+	form, err := ctx.MultipartForm()
+	if err == nil {
+		if files, ok := form.File["photos"]; ok {
+			req.Photos = files
+		}
+	}
+
+	id, herr := c.returUsc.Create(ctx.Context(), userID, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", id)
+}
+
+// ListMy godoc
+// @Summary      List my returns
+// @Tags         Retur
+// @Produce      json
+// @Param        status query string false "Return status"
+// @Success      200 {object} object "Returns"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/my [get]
+func (c *returImpl) ListMy(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	data, herr := c.returUsc.List(ctx.Context(), &entity.ReturFilter{IDUser: userID, Status: ctx.Query("status")})
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// ListToko godoc
+// @Summary      List returns for my toko
+// @Tags         Retur
+// @Produce      json
+// @Param        status query string false "Return status"
+// @Success      200 {object} object "Returns"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/toko [get]
+func (c *returImpl) ListToko(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	data, herr := c.returUsc.List(ctx.Context(), &entity.ReturFilter{IDSeller: userID, Status: ctx.Query("status")})
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// ListAll godoc
+// @Summary      List all returns (admin)
+// @Tags         Retur
+// @Produce      json
+// @Param        status query string false "Return status, e.g. disputed"
+// @Success      200 {object} object "Returns"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur [get]
+func (c *returImpl) ListAll(ctx *fiber.Ctx) error {
+	data, herr := c.returUsc.List(ctx.Context(), &entity.ReturFilter{Status: ctx.Query("status")})
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// GetByID godoc
+// @Summary      Get return detail
+// @Tags         Retur
+// @Produce      json
+// @Param        id path int true "Return ID"
+// @Success      200 {object} models.ReturResponse "Return"
+// @Failure      400 {object} object "Invalid return ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Return not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/{id} [get]
+func (c *returImpl) GetByID(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	returID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid return ID")
+	}
+
+	data, herr := c.returUsc.GetByID(ctx.Context(), returID, userID, isAdmin)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Approve godoc
+// @Summary      Approve a return (seller)
+// @Description  Creates a refund, deducts it from the toko's package and optionally restocks the items
+// @Tags         Retur
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Return ID"
+// @Param        request body models.ApproveReturRequest false "Restock and note"
+// @Success      200 {object} object "Return approved"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Return not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/{id}/approve [put]
+func (c *returImpl) Approve(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	returID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid return ID")
+	}
+
+	var req models.ApproveReturRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+		}
+	}
+
+	if herr := c.returUsc.Approve(ctx.Context(), returID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Reject godoc
+// @Summary      Reject a return (seller)
+// @Tags         Retur
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Return ID"
+// @Param        request body models.RejectReturRequest true "Reason"
+// @Success      200 {object} object "Return rejected"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Return not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/{id}/reject [put]
+func (c *returImpl) Reject(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	returID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid return ID")
+	}
+
+	var req models.RejectReturRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.returUsc.Reject(ctx.Context(), returID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Dispute godoc
+// @Summary      Dispute a rejected return (buyer)
+// @Description  Escalates a return rejected by the seller to an admin, once per return
+// @Tags         Retur
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Return ID"
+// @Param        request body models.DisputeReturRequest true "Reason"
+// @Success      200 {object} object "Return disputed"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Return not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/{id}/dispute [put]
+func (c *returImpl) Dispute(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	returID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid return ID")
+	}
+
+	var req models.DisputeReturRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.returUsc.Dispute(ctx.Context(), returID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Resolve godoc
+// @Summary      Resolve a return (admin)
+// @Description  Final admin decision on a requested or disputed return
+// @Tags         Retur
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Return ID"
+// @Param        request body models.ResolveReturRequest true "Decision"
+// @Success      200 {object} object "Return resolved"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Return not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /retur/{id}/resolve [put]
+func (c *returImpl) Resolve(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	returID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid return ID")
+	}
+
+	var req models.ResolveReturRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.returUsc.Resolve(ctx.Context(), returID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
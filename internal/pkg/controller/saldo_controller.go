@@ -0,0 +1,456 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SaldoController interface {
+	ListSaldo(ctx *fiber.Ctx) error
+	Mutasi(ctx *fiber.Ctx) error
+	AddRekening(ctx *fiber.Ctx) error
+	ListRekening(ctx *fiber.Ctx) error
+	RequestPenarikan(ctx *fiber.Ctx) error
+	ListPenarikan(ctx *fiber.Ctx) error
+	ListPenarikanAll(ctx *fiber.Ctx) error
+	ApprovePenarikan(ctx *fiber.Ctx) error
+	RejectPenarikan(ctx *fiber.Ctx) error
+	PayPenarikan(ctx *fiber.Ctx) error
+	Rekonsiliasi(ctx *fiber.Ctx) error
+}
+
+type saldoImpl struct {
+	saldoUsc usecase.SaldoUsecase
+}
+
+func NewSaldoController(saldoUsc usecase.SaldoUsecase) SaldoController {
+	return &saldoImpl{
+		saldoUsc: saldoUsc,
+	}
+}
+
+// parsePenarikanFilter membaca query daftar penarikan, nama parameter yang tidak valid ikut dikembalikan
+func parsePenarikanFilter(ctx *fiber.Ctx) (*entity.PenarikanFilter, string, error) {
+	filter := &entity.PenarikanFilter{
+		Status: ctx.Query("status"),
+	}
+
+	if v := ctx.Query("id_toko"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "id_toko", err
+		}
+		filter.IDToko = id
+	}
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "page", err
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "limit", err
+		}
+		filter.Limit = l
+	}
+
+	return filter, "", nil
+}
+
+// ListSaldo godoc
+// @Summary      Toko balances
+// @Description  Current balance of every toko owned by the authenticated seller, with payouts still in progress
+// @Tags         Saldo Toko
+// @Produce      json
+// @Success      200 {array} models.SaldoTokoResponse "Balances"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/saldo [get]
+func (c *saldoImpl) ListSaldo(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	data, herr := c.saldoUsc.ListSaldo(ctx.Context(), userID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Mutasi godoc
+// @Summary      Toko balance ledger
+// @Description  Ledger entries of one toko with the running balance after each entry, newest first
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id_toko path  int    true  "Toko ID"
+// @Param        jenis   query string false "penjualan, biaya_platform, refund, penarikan or penarikan_batal"
+// @Param        page    query int    false "Page"
+// @Param        limit   query int    false "Limit (max 100)"
+// @Success      200 {object} models.MutasiListResponse "Ledger entries"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Not Found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/saldo/{id_toko}/mutasi [get]
+func (c *saldoImpl) Mutasi(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	tokoID, err := strconv.Atoi(ctx.Params("id_toko"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid id_toko", err.Error())
+	}
+
+	filter := &entity.MutasiFilter{
+		IDToko: tokoID,
+		Jenis:  ctx.Query("jenis"),
+	}
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid page", err.Error())
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid limit", err.Error())
+		}
+		filter.Limit = l
+	}
+
+	data, herr := c.saldoUsc.Mutasi(ctx.Context(), userID, filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// AddRekening godoc
+// @Summary      Add payout bank account
+// @Description  Registers a bank account of a toko owned by the authenticated seller
+// @Tags         Saldo Toko
+// @Accept       json
+// @Produce      json
+// @Param        body body models.RekeningRequest true "Bank account"
+// @Success      200 {object} int "Bank account ID"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Not Found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/rekening [post]
+func (c *saldoImpl) AddRekening(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.RekeningRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	id, herr := c.saldoUsc.AddRekening(ctx.Context(), userID, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", id)
+}
+
+// ListRekening godoc
+// @Summary      List payout bank accounts
+// @Description  Bank accounts of the tokos owned by the authenticated seller
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id_toko query int false "Toko ID"
+// @Success      200 {array} models.RekeningResponse "Bank accounts"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/rekening [get]
+func (c *saldoImpl) ListRekening(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var tokoID int
+	if v := ctx.Query("id_toko"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid id_toko", err.Error())
+		}
+		tokoID = id
+	}
+
+	data, herr := c.saldoUsc.ListRekening(ctx.Context(), userID, tokoID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// RequestPenarikan godoc
+// @Summary      Request payout
+// @Description  Requests a payout of the toko balance to one of its bank accounts. The amount is held from the balance until the request is rejected
+// @Tags         Saldo Toko
+// @Accept       json
+// @Produce      json
+// @Param        body body models.PenarikanRequest true "Payout request"
+// @Success      200 {object} int "Payout ID"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Not Found"
+// @Failure      409 {object} object "Insufficient balance"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/penarikan [post]
+func (c *saldoImpl) RequestPenarikan(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.PenarikanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	id, herr := c.saldoUsc.RequestPenarikan(ctx.Context(), userID, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", id)
+}
+
+// ListPenarikan godoc
+// @Summary      List my payouts
+// @Description  Payout requests of the tokos owned by the authenticated seller, newest first
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id_toko query int    false "Toko ID"
+// @Param        status  query string false "requested, approved, paid or rejected"
+// @Param        page    query int    false "Page"
+// @Param        limit   query int    false "Limit (max 100)"
+// @Success      200 {object} models.PenarikanListResponse "Payouts"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/penarikan [get]
+func (c *saldoImpl) ListPenarikan(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter, param, err := parsePenarikanFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+	filter.IDUser = userID
+
+	data, herr := c.saldoUsc.ListPenarikan(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// ListPenarikanAll godoc
+// @Summary      List all payouts (admin)
+// @Description  Payout requests of every toko, newest first
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id_toko query int    false "Toko ID"
+// @Param        status  query string false "requested, approved, paid or rejected"
+// @Param        page    query int    false "Page"
+// @Param        limit   query int    false "Limit (max 100)"
+// @Success      200 {object} models.PenarikanListResponse "Payouts"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/penarikan [get]
+func (c *saldoImpl) ListPenarikanAll(ctx *fiber.Ctx) error {
+	filter, param, err := parsePenarikanFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+
+	data, herr := c.saldoUsc.ListPenarikan(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// ApprovePenarikan godoc
+// @Summary      Approve payout (admin)
+// @Description  Moves a requested payout to approved
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id path int true "Payout ID"
+// @Success      200 {object} object "Approved"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Not Found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/penarikan/{id}/approve [put]
+func (c *saldoImpl) ApprovePenarikan(ctx *fiber.Ctx) error {
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	penarikanID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid payout ID")
+	}
+
+	if herr := c.saldoUsc.ApprovePenarikan(ctx.Context(), penarikanID, adminID); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// RejectPenarikan godoc
+// @Summary      Reject payout (admin)
+// @Description  Rejects a requested or approved payout and returns the held amount to the toko balance
+// @Tags         Saldo Toko
+// @Accept       json
+// @Produce      json
+// @Param        id   path int                           true "Payout ID"
+// @Param        body body models.RejectPenarikanRequest true "Reason"
+// @Success      200 {object} object "Rejected"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Not Found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/penarikan/{id}/reject [put]
+func (c *saldoImpl) RejectPenarikan(ctx *fiber.Ctx) error {
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	penarikanID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid payout ID")
+	}
+
+	var req models.RejectPenarikanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.saldoUsc.RejectPenarikan(ctx.Context(), penarikanID, adminID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// PayPenarikan godoc
+// @Summary      Mark payout as paid (admin)
+// @Description  Marks an approved payout as transferred, with the transfer reference
+// @Tags         Saldo Toko
+// @Accept       json
+// @Produce      json
+// @Param        id   path int                        true "Payout ID"
+// @Param        body body models.PayPenarikanRequest true "Transfer reference"
+// @Success      200 {object} object "Paid"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Not Found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/penarikan/{id}/paid [put]
+func (c *saldoImpl) PayPenarikan(ctx *fiber.Ctx) error {
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	penarikanID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid payout ID")
+	}
+
+	var req models.PayPenarikanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.saldoUsc.PayPenarikan(ctx.Context(), penarikanID, adminID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Rekonsiliasi godoc
+// @Summary      Toko balance reconciliation (admin)
+// @Description  Checks the toko balance against its ledger running balance, the completed order lines and the approved refunds
+// @Tags         Saldo Toko
+// @Produce      json
+// @Param        id_toko path int true "Toko ID"
+// @Success      200 {object} models.RekonsiliasiSaldoResponse "Reconciliation"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      404 {object} object "Not Found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /admin/saldo-toko/{id_toko}/rekonsiliasi [get]
+func (c *saldoImpl) Rekonsiliasi(ctx *fiber.Ctx) error {
+	tokoID, err := strconv.Atoi(ctx.Params("id_toko"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid id_toko", err.Error())
+	}
+
+	data, herr := c.saldoUsc.Rekonsiliasi(ctx.Context(), tokoID)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SellerOrderController interface {
+	List(ctx *fiber.Ctx) error
+	Accept(ctx *fiber.Ctx) error
+	Reject(ctx *fiber.Ctx) error
+	Ship(ctx *fiber.Ctx) error
+	Deliver(ctx *fiber.Ctx) error
+	Label(ctx *fiber.Ctx) error
+}
+
+type sellerOrderImpl struct {
+	sellerUsc usecase.SellerOrderUsecase
+}
+
+func NewSellerOrderController(sellerUsc usecase.SellerOrderUsecase) SellerOrderController {
+	return &sellerOrderImpl{
+		sellerUsc: sellerUsc,
+	}
+}
+
+// List godoc
+// @Summary      Seller order inbox
+// @Description  List per-toko packages the current user has to fulfil
+// @Tags         Seller Order
+// @Produce      json
+// @Param        status query string false "Package status (paid, processing, shipped, ...)"
+// @Param        dari   query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        page   query int    false "Page"
+// @Param        limit  query int    false "Limit"
+// @Success      200 {object} models.SellerOrderListResponse "Orders"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders [get]
+func (c *sellerOrderImpl) List(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	filter := &entity.SellerOrderFilter{
+		IDUser: userID,
+		Status: ctx.Query("status"),
+	}
+
+	if v := ctx.Query("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid dari", err.Error())
+		}
+		filter.Dari = &t
+	}
+
+	if v := ctx.Query("sampai"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid sampai", err.Error())
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.Sampai = &t
+	}
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid page", err.Error())
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid limit", err.Error())
+		}
+		filter.Limit = l
+	}
+
+	data, herr := c.sellerUsc.List(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Accept godoc
+// @Summary      Accept order package
+// @Tags         Seller Order
+// @Produce      json
+// @Param        id path int true "Package ID"
+// @Success      200 {object} object "Package accepted"
+// @Failure      400 {object} object "Invalid package ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Package not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders/{id}/accept [put]
+func (c *sellerOrderImpl) Accept(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	paketID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid package ID")
+	}
+
+	if herr := c.sellerUsc.Accept(ctx.Context(), paketID, userID); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Reject godoc
+// @Summary      Reject order package
+// @Description  Cancel the seller's own package and put its stock back
+// @Tags         Seller Order
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Package ID"
+// @Param        request body models.RejectPaketRequest true "Reason"
+// @Success      200 {object} object "Package rejected"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Package not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders/{id}/reject [put]
+func (c *sellerOrderImpl) Reject(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	paketID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid package ID")
+	}
+
+	var req models.RejectPaketRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.sellerUsc.Reject(ctx.Context(), paketID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Ship godoc
+// @Summary      Ship order package
+// @Description  Marks the package as shipped with its airway bill number (no_resi). The shipment is then tracked until the courier reports delivery
+// @Tags         Seller Order
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Package ID"
+// @Param        request body models.ShipPaketRequest true "Airway bill number, optional courier and note"
+// @Success      200 {object} object "Package shipped"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Package not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders/{id}/ship [put]
+func (c *sellerOrderImpl) Ship(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	paketID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid package ID")
+	}
+
+	var req models.ShipPaketRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+		}
+	}
+
+	if herr := c.sellerUsc.Ship(ctx.Context(), paketID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Deliver godoc
+// @Summary      Mark order package delivered
+// @Description  Marks a shipped package of the seller's own toko as delivered. The transaction becomes delivered once every package that is not rejected has arrived
+// @Tags         Seller Order
+// @Produce      json
+// @Param        id path int true "Package ID"
+// @Success      200 {object} object "Package delivered"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Package not found"
+// @Failure      409 {object} object "Invalid status transition"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders/{id}/deliver [put]
+func (c *sellerOrderImpl) Deliver(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	paketID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid package ID")
+	}
+
+	if herr := c.sellerUsc.Deliver(ctx.Context(), paketID, userID); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// Label godoc
+// @Summary      Print shipping label
+// @Description  Printable shipping label as PDF (default) or HTML without prices. Dropship orders print the reseller as sender instead of the toko
+// @Tags         Seller Order
+// @Produce      application/pdf
+// @Produce      text/html
+// @Param        id path int true "Package ID"
+// @Param        format query string false "pdf or html" Enums(pdf, html)
+// @Success      200 {file} file "Shipping label"
+// @Failure      400 {object} object "Invalid package ID or format"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Package not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /toko/my/orders/{id}/label [get]
+func (c *sellerOrderImpl) Label(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	paketID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid package ID")
+	}
+
+	file, herr := c.sellerUsc.Label(ctx.Context(), paketID, userID, ctx.Query("format"))
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, file.FileName))
+	return ctx.Send(file.Body)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShippingController interface {
+	Quote(ctx *fiber.Ctx) error
+}
+
+type shippingImpl struct {
+	shippingUsc usecase.ShippingUsecase
+}
+
+func NewShippingController(shippingUsc usecase.ShippingUsecase) ShippingController {
+	return &shippingImpl{
+		shippingUsc: shippingUsc,
+	}
+}
+
+// Quote godoc
+// @Summary      Shipping quote
+// @Description  Get courier options and shipping cost per toko for the given items and destination address
+// @Tags         Shipping
+// @Accept       json
+// @Produce      json
+// @Param        request body models.ShippingQuoteRequest true "Items and destination"
+// @Success      200 {array} models.ShippingQuoteResponse "Courier options per toko"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Address or product not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /shipping/quote [post]
+func (c *shippingImpl) Quote(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.ShippingQuoteRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	data, herr := c.shippingUsc.Quote(ctx.Context(), userID, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", data)
+}
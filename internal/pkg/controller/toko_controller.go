@@ -54,9 +54,11 @@ func (tc *tokoControllerImpl) GetAll(ctx *fiber.Ctx) error {
 	var resp []models.TokoResponse
 	for _, t := range tokos {
 		resp = append(resp, models.TokoResponse{
-			ID:       t.ID,
-			NamaToko: t.NamaToko,
-			UrlFoto:  t.UrlFoto,
+			ID:           t.ID,
+			NamaToko:     t.NamaToko,
+			UrlFoto:      t.UrlFoto,
+			RatingRata:   t.RatingRata,
+			JumlahUlasan: t.JumlahUlasan,
 		})
 	}
 
@@ -106,9 +108,11 @@ func (tc *tokoControllerImpl) GetMy(ctx *fiber.Ctx) error {
 	var resp []models.TokoResponse
 	for _, t := range tokos {
 		resp = append(resp, models.TokoResponse{
-			ID:       t.ID,
-			NamaToko: t.NamaToko,
-			UrlFoto:  t.UrlFoto,
+			ID:           t.ID,
+			NamaToko:     t.NamaToko,
+			UrlFoto:      t.UrlFoto,
+			RatingRata:   t.RatingRata,
+			JumlahUlasan: t.JumlahUlasan,
 		})
 	}
 
@@ -146,9 +150,13 @@ func (tc *tokoControllerImpl) GetByID(ctx *fiber.Ctx) error {
 	}
 
 	resp := models.TokoResponse{
-		ID:       toko.ID,
-		NamaToko: toko.NamaToko,
-		UrlFoto:  toko.UrlFoto,
+		ID:            toko.ID,
+		NamaToko:      toko.NamaToko,
+		UrlFoto:       toko.UrlFoto,
+		IDKota:        toko.IDKota,
+		PrefixInvoice: toko.PrefixInvoice,
+		RatingRata:    toko.RatingRata,
+		JumlahUlasan:  toko.JumlahUlasan,
 	}
 
 
@@ -166,6 +174,8 @@ func (tc *tokoControllerImpl) GetByID(ctx *fiber.Ctx) error {
 // @Param       id        path     int    true  "Toko ID"
 // @Param       nama_toko formData string false "Nama toko"
 // @Param       url_foto  formData file   false "Foto toko"
+// @Param       id_kota   formData string false "ID kota asal pengiriman"
+// @Param       prefix_invoice formData string false "Kode toko pada nomor invoice paket (2-16 huruf/angka)"
 // @Success     200 {object} object "Success update toko"
 // @Failure     400 {object} object "Bad Request"
 // @Failure     401 {object} object "Unauthorized"
@@ -191,10 +201,12 @@ func (tc *tokoControllerImpl) Update(ctx *fiber.Ctx) error {
 	}
 
 	namaToko := ctx.FormValue("nama_toko")
+	idKota := ctx.FormValue("id_kota")
+	prefixInvoice := ctx.FormValue("prefix_invoice")
 
 	file, fileErr := ctx.FormFile("url_foto")
 
-	if namaToko == "" && fileErr != nil {
+	if namaToko == "" && idKota == "" && prefixInvoice == "" && fileErr != nil {
     	return helper.BadRequest(ctx, "Failed to UPDATE data", "key salah")
 	}
 	var filePath string
@@ -211,8 +223,10 @@ func (tc *tokoControllerImpl) Update(ctx *fiber.Ctx) error {
 	}
 
 	req := &models.TokoUpdateRequest{
-		NamaToko: namaToko,
-		UrlFoto:  filePath, // kosong jika tidak upload
+		NamaToko:      namaToko,
+		UrlFoto:       filePath, // kosong jika tidak upload
+		IDKota:        idKota,
+		PrefixInvoice: prefixInvoice,
 	}
 
 	updated, errUc := tc.TokoUsc.Update(ctx.Context(), tokoID, userID, req)
@@ -223,9 +237,11 @@ func (tc *tokoControllerImpl) Update(ctx *fiber.Ctx) error {
 	}
 
 	resp := models.TokoResponse{
-		ID:       updated.ID,
-		NamaToko: updated.NamaToko,
-		UrlFoto:  updated.UrlFoto,
+		ID:            updated.ID,
+		NamaToko:      updated.NamaToko,
+		UrlFoto:       updated.UrlFoto,
+		IDKota:        updated.IDKota,
+		PrefixInvoice: updated.PrefixInvoice,
 	}
 
 	return helper.Success(ctx, "Succeed to UPDATE data", resp)
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"bufio"
+	"context"
+	"fmt"
 	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
 	"pbi/internal/pkg/models"
 	"pbi/internal/pkg/usecase"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,6 +18,13 @@ type TransactionController interface {
 	Create(ctx *fiber.Ctx) error
 	GetAll(ctx *fiber.Ctx) error
 	GetByID(ctx *fiber.Ctx) error
+	UpdateStatus(ctx *fiber.Ctx) error
+	GetStatusHistory(ctx *fiber.Ctx) error
+	Cancel(ctx *fiber.Ctx) error
+	GetInvoice(ctx *fiber.Ctx) error
+	Export(ctx *fiber.Ctx) error
+	ExportToko(ctx *fiber.Ctx) error
+	ExportAll(ctx *fiber.Ctx) error
 }
 
 type transactionImpl struct {
@@ -31,11 +43,13 @@ func NewTransactionController(trxUsc usecase.TransactionUsecase) TransactionCont
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
+// @Param        Idempotency-Key header string false "Client generated key, retries with the same key replay the first result"
 // @Param        request body object true "Transaction Request"
 // @Success      200 {object} object "Transaction created successfully"
 // @Failure      400 {object} object "Bad Request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      404 {object} object "Alamat pengiriman tidak valid"
+// @Failure      409 {object} object "Idempotency-Key reused with a different body"
 // @Failure      500 {object} object "Internal Server Error"
 // @Security     BearerAuth
 // @Router       /trx/ [post]
@@ -50,7 +64,7 @@ func (c *transactionImpl) Create(ctx *fiber.Ctx) error {
 		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
 	}
 
-	trxID, herr := c.trxUsc.CreateTransaction(ctx.Context(), userID, &req)
+	trxID, herr := c.trxUsc.CreateTransaction(ctx.Context(), userID, ctx.Get("Idempotency-Key"), &req)
 	if herr != nil {
 		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
 	}
@@ -58,13 +72,63 @@ func (c *transactionImpl) Create(ctx *fiber.Ctx) error {
 	return helper.Success(ctx, "Succeed to POST data", trxID)
 }
 
+// parseTrxFilter membaca filter riwayat transaksi yang dipakai daftar dan export, sampai inklusif.
+// Bila gagal, nama parameter yang tidak valid ikut dikembalikan.
+func parseTrxFilter(ctx *fiber.Ctx) (*entity.TrxFilter, string, error) {
+	filter := &entity.TrxFilter{
+		MetodeBayar: ctx.Query("metode_bayar"),
+		Status:      ctx.Query("status"),
+		KodeInvoice: ctx.Query("kode_invoice"),
+		Sort:        ctx.Query("sort"),
+		Order:       ctx.Query("order"),
+	}
+
+	if v := ctx.Query("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, "dari", err
+		}
+		filter.Dari = &t
+	}
+
+	if v := ctx.Query("sampai"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, "sampai", err
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.Sampai = &t
+	}
+
+	if v := ctx.Query("id_toko"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "id_toko", err
+		}
+		filter.IDToko = id
+	}
+
+	return filter, "", nil
+}
+
 // GetAll godoc
 // @Summary      Get All Transactions
-// @Description  Get all transactions for authenticated user with full details
+// @Description  Paginated transaction history of the authenticated user with full details
 // @Tags         Transactions
 // @Accept       json
 // @Produce      json
-// @Success      200 {object} object "Success get all transactions"
+// @Param        dari         query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai       query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        metode_bayar query string false "Payment method"
+// @Param        status       query string false "Transaction status"
+// @Param        id_toko      query int    false "Only transactions containing items from this toko"
+// @Param        kode_invoice query string false "Invoice code search"
+// @Param        sort         query string false "Sort by: tanggal (default) or harga"
+// @Param        order        query string false "asc or desc (default)"
+// @Param        page         query int    false "Page"
+// @Param        limit        query int    false "Limit (max 100)"
+// @Success      200 {object} models.TransactionListResponseWrapper "Success get all transactions"
+// @Failure      400 {object} object "Bad Request"
 // @Failure      401 {object} object "Unauthorized"
 // @Failure      500 {object} object "Internal Server Error"
 // @Security     BearerAuth
@@ -75,7 +139,29 @@ func (c *transactionImpl) GetAll(ctx *fiber.Ctx) error {
 		return helper.Unauthorized(ctx, "Unauthorized")
 	}
 
-	data, herr := c.trxUsc.GetAll(ctx.Context(), userID)
+	filter, param, err := parseTrxFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+	filter.IDUser = userID
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid page", err.Error())
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid limit", err.Error())
+		}
+		filter.Limit = l
+	}
+
+	data, herr := c.trxUsc.GetAll(ctx.Context(), filter)
 	if herr != nil {
 		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
 	}
@@ -114,4 +200,293 @@ func (c *transactionImpl) GetByID(ctx *fiber.Ctx) error {
 	}
 
 	return helper.Success(ctx, "Succeed to GET transaction detail", data)
-}
\ No newline at end of file
+}
+
+// UpdateStatus godoc
+// @Summary      Update Transaction Status
+// @Description  Move a transaction to the next status. Buyer and seller may only perform their own transitions. Catatan is required when the status is cancelled
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Transaction ID"
+// @Param        request body models.UpdateTrxStatusRequest true "Status payload"
+// @Success      200 {object} object "Transaction status updated"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      409 {object} object "Status transition not allowed"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/status [put]
+func (c *transactionImpl) UpdateStatus(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid transaction ID")
+	}
+
+	var req models.UpdateTrxStatusRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.trxUsc.UpdateStatus(ctx.Context(), trxID, userID, isAdmin, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// GetStatusHistory godoc
+// @Summary      Get Transaction Status History
+// @Description  Get the status timeline of a transaction (buyer, seller or admin)
+// @Tags         Transactions
+// @Produce      json
+// @Param        id path int true "Transaction ID"
+// @Success      200 {object} object "Success get status history"
+// @Failure      400 {object} object "Invalid transaction ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/history [get]
+func (c *transactionImpl) GetStatusHistory(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid transaction ID")
+	}
+
+	data, herr := c.trxUsc.GetStatusHistory(ctx.Context(), trxID, userID, isAdmin)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Cancel godoc
+// @Summary      Cancel Transaction
+// @Description  Cancel a transaction that has not been shipped yet and restore product stock
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Transaction ID"
+// @Param        request body models.CancelTrxRequest true "Cancel payload"
+// @Success      200 {object} object "Transaction cancelled"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      409 {object} object "Transaction already shipped"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/cancel [post]
+func (c *transactionImpl) Cancel(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", "Invalid transaction ID")
+	}
+
+	var req models.CancelTrxRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	if herr := c.trxUsc.Cancel(ctx.Context(), trxID, userID, isAdmin, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", nil)
+}
+
+// GetInvoice godoc
+// @Summary      Download Transaction Invoice
+// @Description  Printable invoice as PDF (default) or HTML. Sellers only get the lines of their own toko
+// @Tags         Transactions
+// @Produce      application/pdf
+// @Produce      text/html
+// @Param        id path int true "Transaction ID"
+// @Param        format query string false "pdf or html" Enums(pdf, html)
+// @Success      200 {file} file "Invoice document"
+// @Failure      400 {object} object "Invalid transaction ID or format"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Transaction not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /trx/{id}/invoice [get]
+func (c *transactionImpl) GetInvoice(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	trxID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid transaction ID")
+	}
+
+	file, herr := c.trxUsc.GetInvoice(ctx.Context(), trxID, userID, isAdmin, ctx.Query("format"))
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, file.FileName))
+	return ctx.Send(file.Body)
+}
+
+// Export godoc
+// @Summary      Export My Transactions
+// @Description  Purchases of the authenticated user, one row per detail_trx line with its log_produk snapshot. Streamed as CSV (default) or XLSX
+// @Tags         Transactions
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format       query string false "csv or xlsx" Enums(csv, xlsx)
+// @Param        dari         query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai       query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        metode_bayar query string false "Payment method"
+// @Param        status       query string false "Transaction status"
+// @Param        id_toko      query int    false "Only transactions containing items from this toko"
+// @Param        kode_invoice query string false "Invoice code search"
+// @Param        sort         query string false "Sort by: tanggal (default) or harga"
+// @Param        order        query string false "asc or desc (default)"
+// @Success      200 {file} file "Export file"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Security     BearerAuth
+// @Router       /trx/export [get]
+func (c *transactionImpl) Export(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	return c.export(ctx, func(filter *entity.TrxFilter) {
+		filter.IDUser = userID
+	})
+}
+
+// ExportToko godoc
+// @Summary      Export My Toko Sales
+// @Description  Sales lines of all tokos owned by the authenticated user, lines of other tokos in the same transaction are left out. Streamed as CSV (default) or XLSX
+// @Tags         Transactions
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format       query string false "csv or xlsx" Enums(csv, xlsx)
+// @Param        dari         query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai       query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        metode_bayar query string false "Payment method"
+// @Param        status       query string false "Transaction status"
+// @Param        id_toko      query int    false "Only lines of this toko"
+// @Param        kode_invoice query string false "Invoice code search"
+// @Param        sort         query string false "Sort by: tanggal (default) or harga"
+// @Param        order        query string false "asc or desc (default)"
+// @Success      200 {file} file "Export file"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Security     BearerAuth
+// @Router       /trx/export/toko [get]
+func (c *transactionImpl) ExportToko(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	return c.export(ctx, func(filter *entity.TrxFilter) {
+		filter.IDPenjual = userID
+	})
+}
+
+// ExportAll godoc
+// @Summary      Export All Transactions
+// @Description  Admin only. Lines of every transaction, streamed as CSV (default) or XLSX
+// @Tags         Transactions
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format       query string false "csv or xlsx" Enums(csv, xlsx)
+// @Param        dari         query string false "Start date (YYYY-MM-DD)"
+// @Param        sampai       query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param        metode_bayar query string false "Payment method"
+// @Param        status       query string false "Transaction status"
+// @Param        id_toko      query int    false "Only transactions containing items from this toko"
+// @Param        kode_invoice query string false "Invoice code search"
+// @Param        sort         query string false "Sort by: tanggal (default) or harga"
+// @Param        order        query string false "asc or desc (default)"
+// @Success      200 {file} file "Export file"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Security     BearerAuth
+// @Router       /trx/export/all [get]
+func (c *transactionImpl) ExportAll(ctx *fiber.Ctx) error {
+	return c.export(ctx, func(filter *entity.TrxFilter) {})
+}
+
+// export menulis file langsung ke response. Body dikirim setelah handler selesai,
+// karena itu query memakai context.Background, bukan context request.
+func (c *transactionImpl) export(ctx *fiber.Ctx, scope func(filter *entity.TrxFilter)) error {
+	filter, param, err := parseTrxFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+	scope(filter)
+
+	stream, herr := c.trxUsc.Export(ctx.Context(), filter, ctx.Query("format"))
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	ctx.Set(fiber.HeaderContentType, stream.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, stream.FileName))
+	reqCtx := ctx.Context()
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		// query export berhenti saat server dimatikan atau client putus, yang terlihat
+		// dari gagalnya menulis ke koneksi
+		writeCtx, cancel := context.WithCancel(reqCtx)
+		defer cancel()
+
+		if err := stream.Write(writeCtx, &cancelOnErrorWriter{w: w, cancel: cancel}); err != nil {
+			helper.LogError(fmt.Errorf("export transaksi: %w", err))
+		}
+	})
+	return nil
+}
+
+// cancelOnErrorWriter membatalkan context export begitu menulis atau flush ke client gagal
+type cancelOnErrorWriter struct {
+	w      *bufio.Writer
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnErrorWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	if err != nil {
+		c.cancel()
+	}
+	return n, err
+}
+
+func (c *cancelOnErrorWriter) Flush() error {
+	err := c.w.Flush()
+	if err != nil {
+		c.cancel()
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UlasanController interface {
+	Create(ctx *fiber.Ctx) error
+	Reply(ctx *fiber.Ctx) error
+	ListByProduct(ctx *fiber.Ctx) error
+	ListByToko(ctx *fiber.Ctx) error
+}
+
+type ulasanImpl struct {
+	ulasanUsc usecase.UlasanUsecase
+}
+
+func NewUlasanController(ulasanUsc usecase.UlasanUsecase) UlasanController {
+	return &ulasanImpl{
+		ulasanUsc: ulasanUsc,
+	}
+}
+
+// parseUlasanFilter membaca query daftar ulasan, nama parameter yang tidak valid ikut dikembalikan
+func parseUlasanFilter(ctx *fiber.Ctx) (*entity.UlasanFilter, string, error) {
+	filter := &entity.UlasanFilter{}
+
+	if v := ctx.Query("rating"); v != "" {
+		r, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "rating", err
+		}
+		filter.Rating = r
+	}
+
+	if v := ctx.Query("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "page", err
+		}
+		filter.Page = p
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "limit", err
+		}
+		filter.Limit = l
+	}
+
+	return filter, "", nil
+}
+
+// Create godoc
+// @Summary      Review a purchased product
+// @Description  Buyer reviews one order line (id_detail_trx) of a completed transaction, once per line, with optional photos
+// @Tags         Ulasan
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id_detail_trx formData int    true  "Order line ID"
+// @Param        rating        formData int    true  "Rating 1-5"
+// @Param        komentar      formData string false "Review text"
+// @Param        photos        formData file   false "Photos (max 5)"
+// @Success      200 {object} int "Review ID"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Order line not found"
+// @Failure      409 {object} object "Order not completed, package cancelled or already reviewed"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /ulasan [post]
+func (c *ulasanImpl) Create(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	var req models.UlasanCreateRequest
+
+	req.Komentar = ctx.FormValue("komentar")
+
+	if v := ctx.FormValue("id_detail_trx"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid id_detail_trx", err.Error())
+		}
+		req.IDDetailTrx = id
+	}
+
+	if v := ctx.FormValue("rating"); v != "" {
+		r, err := strconv.Atoi(v)
+		if err != nil {
+			return helper.BadRequest(ctx, "Invalid rating", err.Error())
+		}
+		req.Rating = r
+	}
+
+	form, err := ctx.MultipartForm()
+	if err == nil {
+		if files, ok := form.File["photos"]; ok {
+			req.Photos = files
+		}
+	}
+
+	id, herr := c.ulasanUsc.Create(ctx.Context(), userID, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", id)
+}
+
+// Reply godoc
+// @Summary      Reply to a review
+// @Description  The seller owning the toko replies once to a review
+// @Tags         Ulasan
+// @Accept       json
+// @Produce      json
+// @Param        id      path int                       true "Review ID"
+// @Param        request body models.BalasUlasanRequest true "Reply"
+// @Success      200 {object} object "Replied"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Review not found"
+// @Failure      409 {object} object "Already replied"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /ulasan/{id}/balasan [put]
+func (c *ulasanImpl) Reply(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+
+	ulasanID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", "Invalid review ID")
+	}
+
+	var req models.BalasUlasanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to UPDATE data", err.Error())
+	}
+
+	if herr := c.ulasanUsc.Reply(ctx.Context(), ulasanID, userID, &req); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to UPDATE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to UPDATE data", nil)
+}
+
+// ListByProduct godoc
+// @Summary      Product reviews
+// @Description  Reviews of a product, newest first
+// @Tags         Ulasan
+// @Produce      json
+// @Param        id     path  int true  "Product ID"
+// @Param        rating query int false "Only this rating (1-5)"
+// @Param        page   query int false "Page"
+// @Param        limit  query int false "Limit (max 100)"
+// @Success      200 {object} models.UlasanListResponse "Reviews"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      500 {object} object "Internal Server Error"
+// @Router       /product/{id}/ulasan [get]
+func (c *ulasanImpl) ListByProduct(ctx *fiber.Ctx) error {
+	produkID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid product ID")
+	}
+
+	filter, param, err := parseUlasanFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+	filter.IDProduk = produkID
+
+	data, herr := c.ulasanUsc.List(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// ListByToko godoc
+// @Summary      Toko reviews
+// @Description  Reviews of every product of a toko, newest first
+// @Tags         Ulasan
+// @Produce      json
+// @Param        id     path  int true  "Toko ID"
+// @Param        rating query int false "Only this rating (1-5)"
+// @Param        page   query int false "Page"
+// @Param        limit  query int false "Limit (max 100)"
+// @Success      200 {object} models.UlasanListResponse "Reviews"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      500 {object} object "Internal Server Error"
+// @Router       /toko/{id}/ulasan [get]
+func (c *ulasanImpl) ListByToko(ctx *fiber.Ctx) error {
+	tokoID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to GET data", "Invalid toko ID")
+	}
+
+	filter, param, err := parseUlasanFilter(ctx)
+	if err != nil {
+		return helper.BadRequest(ctx, "Invalid "+param, err.Error())
+	}
+	filter.IDToko = tokoID
+
+	data, herr := c.ulasanUsc.List(ctx.Context(), filter)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"pbi/internal/helper"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type VoucherController interface {
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Deactivate(ctx *fiber.Ctx) error
+}
+
+type voucherImpl struct {
+	voucherUsc usecase.VoucherUsecase
+}
+
+func NewVoucherController(voucherUsc usecase.VoucherUsecase) VoucherController {
+	return &voucherImpl{
+		voucherUsc: voucherUsc,
+	}
+}
+
+// Create godoc
+// @Summary      Create voucher
+// @Description  Admins can create platform, toko or category vouchers. Toko owners can only create vouchers for their own toko
+// @Tags         Voucher
+// @Accept       json
+// @Produce      json
+// @Param        request body models.VoucherCreateRequest true "Voucher payload"
+// @Success      200 {object} object "Voucher ID"
+// @Failure      400 {object} object "Bad Request"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      403 {object} object "Forbidden"
+// @Failure      409 {object} object "Voucher code already used"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /voucher [post]
+func (c *voucherImpl) Create(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	var req models.VoucherCreateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return helper.BadRequest(ctx, "Failed to POST data", err.Error())
+	}
+
+	id, herr := c.voucherUsc.Create(ctx.Context(), userID, isAdmin, &req)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to POST data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to POST data", id)
+}
+
+// List godoc
+// @Summary      List my vouchers
+// @Description  List vouchers created by the current user, admins see every voucher
+// @Tags         Voucher
+// @Produce      json
+// @Success      200 {array} models.VoucherResponse "Vouchers"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /voucher/my [get]
+func (c *voucherImpl) List(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	data, herr := c.voucherUsc.List(ctx.Context(), userID, isAdmin)
+	if herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to GET data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to GET data", data)
+}
+
+// Deactivate godoc
+// @Summary      Deactivate voucher
+// @Tags         Voucher
+// @Produce      json
+// @Param        id path int true "Voucher ID"
+// @Success      200 {object} object "Voucher deactivated"
+// @Failure      400 {object} object "Invalid voucher ID"
+// @Failure      401 {object} object "Unauthorized"
+// @Failure      404 {object} object "Voucher not found"
+// @Failure      500 {object} object "Internal Server Error"
+// @Security     BearerAuth
+// @Router       /voucher/{id} [delete]
+func (c *voucherImpl) Deactivate(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return helper.Unauthorized(ctx, "Unauthorized")
+	}
+	isAdmin, _ := ctx.Locals("is_admin").(bool)
+
+	voucherID, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return helper.BadRequest(ctx, "Failed to DELETE data", "Invalid voucher ID")
+	}
+
+	if herr := c.voucherUsc.Deactivate(ctx.Context(), voucherID, userID, isAdmin); herr != nil {
+		return helper.Error(ctx, herr.Code, "Failed to DELETE data", herr.Err.Error())
+	}
+
+	return helper.Success(ctx, "Succeed to DELETE data", nil)
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// AdminReportFilter rentang tanggal (inklusif) laporan admin untuk seluruh platform
+type AdminReportFilter struct {
+	Dari    time.Time
+	Sampai  time.Time
+	Periode string
+	Limit   int
+}
+
+// GMVPoint nilai pesanan terbayar dalam satu periode, termasuk ongkir dan setelah diskon
+type GMVPoint struct {
+	Periode string       `gorm:"column:periode"`
+	Pesanan int64        `gorm:"column:pesanan"`
+	GMV     money.Rupiah `gorm:"column:gmv"`
+}
+
+type StatusCount struct {
+	Status string `gorm:"column:status"`
+	Jumlah int64  `gorm:"column:jumlah"`
+}
+
+type PeriodeCount struct {
+	Periode string `gorm:"column:periode"`
+	Jumlah  int64  `gorm:"column:jumlah"`
+}
+
+type CategorySales struct {
+	IDCategory   int          `gorm:"column:id_category"`
+	NamaCategory string       `gorm:"column:nama_category"`
+	Unit         int64        `gorm:"column:unit"`
+	Pendapatan   money.Rupiah `gorm:"column:pendapatan"`
+}
+
+type TokoSales struct {
+	IDToko     int          `gorm:"column:id_toko"`
+	NamaToko   string       `gorm:"column:nama_toko"`
+	Pesanan    int64        `gorm:"column:pesanan"`
+	Unit       int64        `gorm:"column:unit"`
+	Pendapatan money.Rupiah `gorm:"column:pendapatan"`
+}
+
+// ResellerKomisi komisi bersih satu reseller dari entri yang dibuat dalam rentang laporan
+type ResellerKomisi struct {
+	IDUser   int          `gorm:"column:id_user"`
+	Nama     string       `gorm:"column:nama"`
+	Pesanan  int64        `gorm:"column:pesanan"`
+	Unit     int64        `gorm:"column:unit"`
+	Margin   money.Rupiah `gorm:"column:margin"`
+	Tertahan money.Rupiah `gorm:"column:tertahan"`
+	Tersedia money.Rupiah `gorm:"column:tersedia"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Periode pengelompokan grafik penjualan
+const (
+	AnalyticsPeriodeDay   = "day"
+	AnalyticsPeriodeWeek  = "week"
+	AnalyticsPeriodeMonth = "month"
+)
+
+// AnalyticsFilter rentang tanggal (inklusif) untuk analitik toko milik IDUser.
+// IDToko 0 berarti semua toko milik user.
+type AnalyticsFilter struct {
+	IDUser  int
+	IDToko  int
+	Dari    time.Time
+	Sampai  time.Time
+	Periode string
+	Sort    string
+	Limit   int
+}
+
+// SalesPoint penjualan dalam satu periode, Periode berisi tanggal awal periode (YYYY-MM-DD)
+type SalesPoint struct {
+	Periode    string       `gorm:"column:periode"`
+	Pesanan    int64        `gorm:"column:pesanan"`
+	Unit       int64        `gorm:"column:unit"`
+	Pendapatan money.Rupiah `gorm:"column:pendapatan"`
+}
+
+// ProductSales penjualan satu produk dalam rentang tanggal
+type ProductSales struct {
+	IDProduk   int          `gorm:"column:id_produk"`
+	NamaProduk string       `gorm:"column:nama_produk"`
+	Unit       int64        `gorm:"column:unit"`
+	Pendapatan money.Rupiah `gorm:"column:pendapatan"`
+}
+
+// PaymentMethodSales penjualan per metode bayar
+type PaymentMethodSales struct {
+	MetodeBayar string       `gorm:"column:metode_bayar"`
+	Pesanan     int64        `gorm:"column:pesanan"`
+	Pendapatan  money.Rupiah `gorm:"column:pendapatan"`
+}
+
+// BuyerStats jumlah pembeli unik dan pembeli dengan lebih dari satu pesanan
+type BuyerStats struct {
+	Pembeli         int64 `gorm:"column:pembeli"`
+	PembeliBerulang int64 `gorm:"column:pembeli_berulang"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type CartItem struct {
+	ID                int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDUser            int          `gorm:"column:id_user;not null"`
+	IDProduk          int          `gorm:"column:id_produk;not null"`
+	Kuantitas         int          `gorm:"column:kuantitas;not null"`
+	HargaSaatDitambah money.Rupiah `gorm:"column:harga_saat_ditambah;type:bigint;not null"`
+	CreatedAt         time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// CartItemWithProduk baris keranjang beserta harga & stok produk saat ini
+type CartItemWithProduk struct {
+	CartItem
+	NamaProduk    string       `gorm:"column:nama_produk"`
+	HargaKonsumen money.Rupiah `gorm:"column:harga_konsumen"`
+	Stok          int          `gorm:"column:stok"`
+	IDToko        int          `gorm:"column:id_toko"`
+	NamaToko      string       `gorm:"column:nama_toko"`
+}
+
+func (CartItem) TableName() string {
+	return "keranjang"
+}
@@ -9,6 +9,8 @@ type Destination struct {
 	NamaPenerima string    `gorm:"column:nama_penerima"`
 	NoTelp       string    `gorm:"column:no_telp"`
 	DetailAlamat string    `gorm:"column:detail_alamat"`
+	IDKota       string    `gorm:"column:id_kota"`
+	Dropship     bool      `gorm:"column:dropship"`
 	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
 }
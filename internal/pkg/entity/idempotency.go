@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// IdempotencyKey menyimpan hasil checkout pertama per user + key
+type IdempotencyKey struct {
+	ID          int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDUser      int       `gorm:"column:id_user;not null"`
+	IdemKey     string    `gorm:"column:idem_key;not null"`
+	RequestHash string    `gorm:"column:request_hash;not null"`
+	IDTrx       *int      `gorm:"column:id_trx"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
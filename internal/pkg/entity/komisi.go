@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Jenis entri buku komisi reseller
+const (
+	KomisiJenisPenjualan  = "penjualan"
+	KomisiJenisPembatalan = "pembatalan"
+	KomisiJenisRetur      = "retur"
+)
+
+// Status entri komisi, tertahan sampai pesanan completed
+const (
+	KomisiStatusTertahan = "tertahan"
+	KomisiStatusTersedia = "tersedia"
+)
+
+// KomisiReseller satu entri buku komisi per detail_trx, margin negatif untuk pembatalan dan retur
+type KomisiReseller struct {
+	ID            int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDUser        int          `gorm:"column:id_user;not null"`
+	IDTrx         int          `gorm:"column:id_trx;not null"`
+	IDDetailTrx   int          `gorm:"column:id_detail_trx;not null"`
+	IDToko        int          `gorm:"column:id_toko;not null"`
+	IDProduk      int          `gorm:"column:id_produk;not null"`
+	IDRetur       *int         `gorm:"column:id_retur"`
+	Jenis         string       `gorm:"column:jenis;not null"`
+	Kuantitas     int          `gorm:"column:kuantitas;not null"`
+	HargaReseller money.Rupiah `gorm:"column:harga_reseller;type:bigint;not null"`
+	HargaKonsumen money.Rupiah `gorm:"column:harga_konsumen;type:bigint;not null"`
+	Margin        money.Rupiah `gorm:"column:margin;type:bigint;not null"`
+	Status        string       `gorm:"column:status;not null"`
+	TersediaPada  *time.Time   `gorm:"column:tersedia_pada"`
+	CreatedAt     time.Time    `gorm:"column:created_at;autoCreateTime"`
+}
+
+// KomisiEntry entri komisi beserta kode invoice trx dan nama produk snapshot
+type KomisiEntry struct {
+	KomisiReseller
+	KodeInvoice string `gorm:"column:kode_invoice"`
+	NamaProduk  string `gorm:"column:nama_produk"`
+}
+
+// KomisiFilter filter riwayat komisi reseller, Sampai bersifat eksklusif
+type KomisiFilter struct {
+	IDUser int
+	Status string
+	Jenis  string
+	Dari   *time.Time
+	Sampai *time.Time
+	Page   int
+	Limit  int
+}
+
+// KomisiSaldo jumlah margin bersih per status
+type KomisiSaldo struct {
+	Tertahan money.Rupiah `gorm:"column:tertahan"`
+	Tersedia money.Rupiah `gorm:"column:tersedia"`
+}
+
+func (KomisiReseller) TableName() string {
+	return "komisi_reseller"
+}
@@ -1,15 +1,18 @@
 package entity
 
+import "pbi/internal/utils/money"
+
 type DetailTrxWithJoin struct {
     IDTrx       int
     Kuantitas   int
-    HargaTotal  int
+    HargaTotal  money.Rupiah
+    Diskon      money.Rupiah
 
     IDLogProduk   int
     NamaProduk    string
     Slug          string
-    HargaReseller int
-    HargaKonsumen int
+    HargaReseller money.Rupiah
+    HargaKonsumen money.Rupiah
     Deskripsi     string
     IDCategory    int 
 
@@ -18,4 +21,10 @@ type DetailTrxWithJoin struct {
     URLFoto  string
     
     NamaCategory string
-}
\ No newline at end of file
+}
+
+// LogProdukPhoto foto produk beserta id log_produk yang merujuknya
+type LogProdukPhoto struct {
+	ProductPhoto
+	IDLogProduk int `gorm:"column:id_log_produk"`
+}
@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Jenis event domain yang ditulis ke outbox
+const (
+	EventTransactionCreated       = "TransactionCreated"
+	EventTransactionStatusChanged = "TransactionStatusChanged"
+	EventProductStockChanged      = "ProductStockChanged"
+	EventProductPriceChanged      = "ProductPriceChanged"
+)
+
+// Agregat pemilik event, id_agregat merujuk ke id baris tabelnya
+const (
+	OutboxAgregatTrx    = "trx"
+	OutboxAgregatProduk = "produk"
+)
+
+// Status event outbox. failed berarti batas percobaan habis dan event menunggu replay.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusFailed    = "failed"
+)
+
+// Sebab perubahan stok pada ProductStockChanged
+const (
+	StokSebabDitahan      = "ditahan"
+	StokSebabDibayar      = "dibayar"
+	StokSebabDilepas      = "dilepas"
+	StokSebabDikembalikan = "dikembalikan"
+	StokSebabRetur        = "retur"
+	StokSebabDiubah       = "diubah"
+)
+
+// OutboxEvent satu event domain, payload berisi JSON salah satu struct *Payload di bawah
+type OutboxEvent struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	Jenis         string     `gorm:"column:jenis;not null"`
+	Agregat       string     `gorm:"column:agregat;not null"`
+	IDAgregat     int        `gorm:"column:id_agregat;not null"`
+	Payload       string     `gorm:"column:payload;type:json;not null"`
+	Status        string     `gorm:"column:status;not null"`
+	Percobaan     int        `gorm:"column:percobaan;not null"`
+	ErrorTerakhir *string    `gorm:"column:error_terakhir"`
+	TersediaPada  time.Time  `gorm:"column:tersedia_pada;not null"`
+	TerkirimPada  *time.Time `gorm:"column:terkirim_pada"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+// OutboxTerkirim subscriber yang sudah berhasil menerima satu event
+type OutboxTerkirim struct {
+	IDEvent    int64     `gorm:"column:id_event;primaryKey"`
+	Subscriber string    `gorm:"column:subscriber;primaryKey"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// OutboxReplayFilter event yang dikirim ulang, field kosong tidak membatasi.
+// Subscriber diisi untuk mengirim ulang ke satu subscriber saja.
+type OutboxReplayFilter struct {
+	DariID     int64
+	SampaiID   int64
+	Jenis      string
+	Status     string
+	Subscriber string
+	Dari       *time.Time
+	Sampai     *time.Time
+}
+
+// ProdukStok stok produk setelah berubah, dibaca di dalam db transaction yang mengubahnya
+type ProdukStok struct {
+	ID          int `gorm:"column:id"`
+	Stok        int `gorm:"column:stok"`
+	StokDitahan int `gorm:"column:stok_ditahan"`
+}
+
+type TransactionCreatedItem struct {
+	IDProduk   int          `json:"id_produk"`
+	IDToko     int          `json:"id_toko"`
+	Kuantitas  int          `json:"kuantitas"`
+	HargaTotal money.Rupiah `json:"harga_total"`
+}
+
+type TransactionCreatedPayload struct {
+	IDTrx       int                      `json:"id_trx"`
+	IDUser      int                      `json:"id_user"`
+	KodeInvoice string                   `json:"kode_invoice"`
+	MetodeBayar string                   `json:"metode_bayar"`
+	HargaTotal  money.Rupiah             `json:"harga_total"`
+	Dropship    bool                     `json:"dropship"`
+	Items       []TransactionCreatedItem `json:"items"`
+}
+
+type TransactionStatusChangedPayload struct {
+	IDTrx      int    `json:"id_trx"`
+	DariStatus string `json:"dari_status"`
+	KeStatus   string `json:"ke_status"`
+	Peran      string `json:"peran"`
+	DiubahOleh int    `json:"diubah_oleh"`
+	Catatan    string `json:"catatan"`
+}
+
+// ProductStockChangedPayload stok sesudah perubahan, bukan selisihnya, sehingga aman diterima berulang
+type ProductStockChangedPayload struct {
+	IDProduk     int    `json:"id_produk"`
+	Stok         int    `json:"stok"`
+	StokDitahan  int    `json:"stok_ditahan"`
+	StokTersedia int    `json:"stok_tersedia"`
+	Sebab        string `json:"sebab"`
+	IDTrx        int    `json:"id_trx,omitempty"`
+}
+
+type ProductPriceChangedPayload struct {
+	IDProduk          int          `json:"id_produk"`
+	HargaResellerLama money.Rupiah `json:"harga_reseller_lama"`
+	HargaReseller     money.Rupiah `json:"harga_reseller"`
+	HargaKonsumenLama money.Rupiah `json:"harga_konsumen_lama"`
+	HargaKonsumen     money.Rupiah `json:"harga_konsumen"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_event"
+}
+
+func (OutboxTerkirim) TableName() string {
+	return "outbox_terkirim"
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Status pembayaran
+const (
+	PaymentStatusPending = "pending"
+	PaymentStatusPaid    = "paid"
+	PaymentStatusFailed  = "failed"
+)
+
+// MetodeBayarCOD dibayar tunai ke kurir, tidak melalui payment provider
+const MetodeBayarCOD = "cod"
+
+type Payment struct {
+	ID          int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDTrx       int          `gorm:"column:id_trx;not null"`
+	Provider    string       `gorm:"column:provider;not null"`
+	Referensi   string       `gorm:"column:referensi;not null"`
+	Jumlah      money.Rupiah `gorm:"column:jumlah;type:bigint;not null"`
+	Status      string       `gorm:"column:status"`
+	PaymentURL  string       `gorm:"column:payment_url"`
+	Instruksi   string       `gorm:"column:instruksi;type:text"`
+	DibayarPada *time.Time   `gorm:"column:dibayar_pada"`
+	// PerluRefund pembayaran diterima saat trx sudah tidak menunggu pembayaran
+	PerluRefund   bool      `gorm:"column:perlu_refund"`
+	CatatanRefund string    `gorm:"column:catatan_refund"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Payment) TableName() string {
+	return "pembayaran"
+}
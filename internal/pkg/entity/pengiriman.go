@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Status pengiriman. Pelacakan berhenti setelah kurir melaporkan paket sampai.
+const (
+	PengirimanStatusInTransit = "in_transit"
+	PengirimanStatusDelivered = "delivered"
+)
+
+// PengirimanEventDelivered status event dari kurir yang menandakan paket sudah sampai
+const PengirimanEventDelivered = "delivered"
+
+// Pengiriman resi satu paket toko
+type Pengiriman struct {
+	ID              int        `gorm:"column:id;primaryKey;autoIncrement"`
+	IDPaket         int        `gorm:"column:id_paket;not null"`
+	IDTrx           int        `gorm:"column:id_trx;not null"`
+	IDToko          int        `gorm:"column:id_toko;not null"`
+	Kurir           string     `gorm:"column:kurir;not null"`
+	NoResi          string     `gorm:"column:no_resi;not null"`
+	Status          string     `gorm:"column:status;not null"`
+	TerakhirDilacak *time.Time `gorm:"column:terakhir_dilacak"`
+	SampaiPada      *time.Time `gorm:"column:sampai_pada"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type PengirimanEvent struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDPengiriman int       `gorm:"column:id_pengiriman;not null"`
+	Status       string    `gorm:"column:status;not null"`
+	Keterangan   string    `gorm:"column:keterangan"`
+	Lokasi       string    `gorm:"column:lokasi"`
+	Waktu        time.Time `gorm:"column:waktu;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Pengiriman) TableName() string {
+	return "pengiriman"
+}
+
+func (PengirimanEvent) TableName() string {
+	return "pengiriman_event"
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
 
 type Produk struct {
 	ID            int       `gorm:"column:id;primaryKey;autoIncrement"`
@@ -8,10 +11,14 @@ type Produk struct {
 	IDCategory    int       `gorm:"column:id_category;not null"`
 	NamaProduk    string    `gorm:"column:nama_produk;type:varchar(255)"`
 	Slug          string    `gorm:"column:slug;type:varchar(255)"`
-	HargaReseller float64   `gorm:"column:harga_reseller;type:decimal(15,2);not null"`
-	HargaKonsumen float64   `gorm:"column:harga_konsumen;type:decimal(15,2);not null"`
+	HargaReseller money.Rupiah `gorm:"column:harga_reseller;type:bigint;not null"`
+	HargaKonsumen money.Rupiah `gorm:"column:harga_konsumen;type:bigint;not null"`
 	Stok          int       `gorm:"column:stok"`
+	StokDitahan   int       `gorm:"column:stok_ditahan;->"`
+	Berat         int       `gorm:"column:berat"`
 	Deskripsi     string    `gorm:"column:deskripsi;type:text"`
+	RatingRata    float64   `gorm:"column:rating_rata;->"`
+	JumlahUlasan  int       `gorm:"column:jumlah_ulasan;->"`
 	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
@@ -33,12 +40,20 @@ type ProductFilter struct {
 	NamaProduk string
 	CategoryID int
 	TokoID     int
-	MinHarga   float64
-	MaxHarga   float64
+	MinHarga   money.Rupiah
+	MaxHarga   money.Rupiah
 	Page       int
 	Limit      int
 }
 
+// StokTersedia stok fisik dikurangi stok yang ditahan pesanan belum dibayar
+func (p Produk) StokTersedia() int {
+	if p.Stok < p.StokDitahan {
+		return 0
+	}
+	return p.Stok - p.StokDitahan
+}
+
 func (Produk) TableName() string { 
 	return "produk" 
 }
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Status reservasi stok. Reservasi active menahan stok sampai expires_at,
+// committed berarti stok sudah dipotong permanen karena pesanan dibayar.
+const (
+	ReservasiStatusActive    = "active"
+	ReservasiStatusCommitted = "committed"
+	ReservasiStatusReleased  = "released"
+)
+
+type StokReservasi struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDTrx     int       `gorm:"column:id_trx"`
+	IDProduk  int       `gorm:"column:id_produk"`
+	Kuantitas int       `gorm:"column:kuantitas"`
+	Status    string    `gorm:"column:status"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (StokReservasi) TableName() string {
+	return "stok_reservasi"
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Status retur. rejected masih bisa dibanding pembeli menjadi disputed,
+// keputusan admin atas retur disputed bersifat final.
+const (
+	ReturStatusRequested = "requested"
+	ReturStatusApproved  = "approved"
+	ReturStatusRejected  = "rejected"
+	ReturStatusDisputed  = "disputed"
+)
+
+// Alasan retur
+const (
+	ReturAlasanRusak       = "rusak"
+	ReturAlasanSalahKirim  = "salah_kirim"
+	ReturAlasanTidakSesuai = "tidak_sesuai"
+	ReturAlasanKurang      = "kurang"
+	ReturAlasanLainnya     = "lainnya"
+)
+
+const (
+	RefundStatusPending = "pending"
+)
+
+type Retur struct {
+	ID             int        `gorm:"column:id;primaryKey;autoIncrement"`
+	IDTrx          int        `gorm:"column:id_trx;not null"`
+	IDToko         int        `gorm:"column:id_toko;not null"`
+	IDUser         int        `gorm:"column:id_user;not null"`
+	Alasan         string     `gorm:"column:alasan;not null"`
+	Keterangan     string     `gorm:"column:keterangan;type:text"`
+	Status         string     `gorm:"column:status;not null"`
+	Restock        bool       `gorm:"column:restock"`
+	CatatanPenjual string     `gorm:"column:catatan_penjual"`
+	CatatanBanding string     `gorm:"column:catatan_banding"`
+	CatatanAdmin   string     `gorm:"column:catatan_admin"`
+	DiputuskanOleh *int       `gorm:"column:diputuskan_oleh"`
+	DiputuskanPada *time.Time `gorm:"column:diputuskan_pada"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type ReturItem struct {
+	ID          int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDRetur     int          `gorm:"column:id_retur;not null"`
+	IDDetailTrx int          `gorm:"column:id_detail_trx;not null"`
+	Kuantitas   int          `gorm:"column:kuantitas;not null"`
+	Jumlah      money.Rupiah `gorm:"column:jumlah;type:bigint;not null"`
+}
+
+type ReturFoto struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDRetur   int       `gorm:"column:id_retur;not null"`
+	Url       string    `gorm:"column:url;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// Refund dana pembeli dari retur yang disetujui (IDRetur) atau paket yang ditolak penjual (IDPaket)
+type Refund struct {
+	ID        int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDRetur   *int         `gorm:"column:id_retur"`
+	IDPaket   *int         `gorm:"column:id_paket"`
+	IDTrx     int          `gorm:"column:id_trx;not null"`
+	IDToko    int          `gorm:"column:id_toko;not null"`
+	Jumlah    money.Rupiah `gorm:"column:jumlah;type:bigint;not null"`
+	Status    string       `gorm:"column:status;not null"`
+	CreatedAt time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// ReturLine baris detail_trx yang bisa diretur beserta kuantitas yang sudah diajukan
+type ReturLine struct {
+	IDDetailTrx int          `gorm:"column:id_detail_trx"`
+	IDToko      int          `gorm:"column:id_toko"`
+	IDProduk    int          `gorm:"column:id_produk"`
+	NamaProduk  string       `gorm:"column:nama_produk"`
+	Kuantitas   int          `gorm:"column:kuantitas"`
+	HargaTotal  money.Rupiah `gorm:"column:harga_total"`
+	Diretur     int          `gorm:"column:diretur"`
+}
+
+// ReturItemDetail item retur dengan nama produk dari snapshot log_produk
+type ReturItemDetail struct {
+	ReturItem
+	IDProduk   int    `gorm:"column:id_produk"`
+	NamaProduk string `gorm:"column:nama_produk"`
+}
+
+// ReturFilter filter daftar retur. IDUser pembeli, IDSeller pemilik toko, keduanya 0 untuk admin.
+type ReturFilter struct {
+	IDUser   int
+	IDSeller int
+	Status   string
+}
+
+func (Retur) TableName() string {
+	return "retur"
+}
+
+func (ReturItem) TableName() string {
+	return "retur_item"
+}
+
+func (ReturFoto) TableName() string {
+	return "retur_foto"
+}
+
+func (Refund) TableName() string {
+	return "refund"
+}
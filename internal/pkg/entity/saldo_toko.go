@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Jenis mutasi saldo toko, jumlah negatif untuk biaya, refund dan penarikan
+const (
+	MutasiJenisPenjualan      = "penjualan"
+	MutasiJenisBiayaPlatform  = "biaya_platform"
+	MutasiJenisRefund         = "refund"
+	MutasiJenisPenarikan      = "penarikan"
+	MutasiJenisPenarikanBatal = "penarikan_batal"
+)
+
+// Status penarikan saldo. Saldo dipotong saat diajukan dan dikembalikan bila ditolak.
+const (
+	PenarikanStatusRequested = "requested"
+	PenarikanStatusApproved  = "approved"
+	PenarikanStatusPaid      = "paid"
+	PenarikanStatusRejected  = "rejected"
+)
+
+type SaldoToko struct {
+	IDToko    int          `gorm:"column:id_toko;primaryKey"`
+	Saldo     money.Rupiah `gorm:"column:saldo;type:bigint;not null"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// MutasiSaldo satu baris buku saldo toko, Saldo adalah saldo toko setelah baris ini
+type MutasiSaldo struct {
+	ID          int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDToko      int          `gorm:"column:id_toko;not null"`
+	Jenis       string       `gorm:"column:jenis;not null"`
+	IDTrx       *int         `gorm:"column:id_trx"`
+	IDDetailTrx *int         `gorm:"column:id_detail_trx"`
+	IDRefund    *int         `gorm:"column:id_refund"`
+	IDPenarikan *int         `gorm:"column:id_penarikan"`
+	Jumlah      money.Rupiah `gorm:"column:jumlah;type:bigint;not null"`
+	Saldo       money.Rupiah `gorm:"column:saldo;type:bigint;not null"`
+	Keterangan  string       `gorm:"column:keterangan"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime"`
+}
+
+type RekeningToko struct {
+	ID            int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDToko        int       `gorm:"column:id_toko;not null"`
+	NamaBank      string    `gorm:"column:nama_bank;not null"`
+	NomorRekening string    `gorm:"column:nomor_rekening;not null"`
+	AtasNama      string    `gorm:"column:atas_nama;not null"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+type Penarikan struct {
+	ID             int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDToko         int          `gorm:"column:id_toko;not null"`
+	IDRekening     int          `gorm:"column:id_rekening;not null"`
+	Jumlah         money.Rupiah `gorm:"column:jumlah;type:bigint;not null"`
+	Status         string       `gorm:"column:status;not null"`
+	AlasanTolak    string       `gorm:"column:alasan_tolak"`
+	Referensi      string       `gorm:"column:referensi"`
+	DiputuskanOleh *int         `gorm:"column:diputuskan_oleh"`
+	DiputuskanPada *time.Time   `gorm:"column:diputuskan_pada"`
+	DibayarPada    *time.Time   `gorm:"column:dibayar_pada"`
+	CreatedAt      time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PenarikanDetail penarikan beserta toko dan rekening tujuan
+type PenarikanDetail struct {
+	Penarikan
+	NamaToko      string `gorm:"column:nama_toko"`
+	NamaBank      string `gorm:"column:nama_bank"`
+	NomorRekening string `gorm:"column:nomor_rekening"`
+	AtasNama      string `gorm:"column:atas_nama"`
+}
+
+// SaldoLine baris detail_trx yang belum dikreditkan ke saldo toko
+type SaldoLine struct {
+	IDTrx       int          `gorm:"column:id_trx"`
+	IDDetailTrx int          `gorm:"column:id_detail_trx"`
+	IDToko      int          `gorm:"column:id_toko"`
+	HargaTotal  money.Rupiah `gorm:"column:harga_total"`
+}
+
+// SaldoTokoOwner saldo satu toko milik user beserta penarikan yang masih diproses
+type SaldoTokoOwner struct {
+	IDToko          int          `gorm:"column:id_toko"`
+	NamaToko        string       `gorm:"column:nama_toko"`
+	Saldo           money.Rupiah `gorm:"column:saldo"`
+	PenarikanProses money.Rupiah `gorm:"column:penarikan_proses"`
+}
+
+type MutasiFilter struct {
+	IDToko int
+	Jenis  string
+	Page   int
+	Limit  int
+}
+
+// PenarikanFilter IDUser membatasi ke toko milik user, kosong berarti semua toko (admin)
+type PenarikanFilter struct {
+	IDUser int
+	IDToko int
+	Status string
+	Page   int
+	Limit  int
+}
+
+// RekonsiliasiSaldo pembanding buku saldo toko dengan detail_trx dan refund sumbernya
+type RekonsiliasiSaldo struct {
+	SaldoToko          money.Rupiah `gorm:"column:saldo_toko"`
+	SaldoTerakhir      money.Rupiah `gorm:"column:saldo_terakhir"`
+	TotalMutasi        money.Rupiah `gorm:"column:total_mutasi"`
+	BarisTidakSesuai   int64        `gorm:"column:baris_tidak_sesuai"`
+	PenjualanBuku      money.Rupiah `gorm:"column:penjualan_buku"`
+	PenjualanDetailTrx money.Rupiah `gorm:"column:penjualan_detail_trx"`
+	RefundBuku         money.Rupiah `gorm:"column:refund_buku"`
+	RefundSumber       money.Rupiah `gorm:"column:refund_sumber"`
+}
+
+func (SaldoToko) TableName() string {
+	return "saldo_toko"
+}
+
+func (MutasiSaldo) TableName() string {
+	return "mutasi_saldo_toko"
+}
+
+func (RekeningToko) TableName() string {
+	return "rekening_toko"
+}
+
+func (Penarikan) TableName() string {
+	return "penarikan_saldo"
+}
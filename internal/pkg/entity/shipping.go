@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// PaketToko sub-order per toko dalam satu transaksi.
+// Status memakai nilai TrxStatus*, status trx diturunkan dari status paketnya.
+type PaketToko struct {
+	ID           int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDTrx        int          `gorm:"column:id_trx;not null"`
+	IDToko       int          `gorm:"column:id_toko;not null"`
+	KodeInvoice  string       `gorm:"column:kode_invoice"`
+	Kurir        string       `gorm:"column:kurir;not null"`
+	Layanan      string       `gorm:"column:layanan;not null"`
+	Berat        int          `gorm:"column:berat;not null"`
+	Ongkir       money.Rupiah `gorm:"column:ongkir;type:bigint;not null"`
+	TotalRefund  money.Rupiah `gorm:"column:total_refund;type:bigint"`
+	Estimasi     string       `gorm:"column:estimasi"`
+	Status       string       `gorm:"column:status;not null"`
+	AlasanTolak  string       `gorm:"column:alasan_tolak"`
+	DiterimaPada *time.Time   `gorm:"column:diterima_pada"`
+	DikirimPada  *time.Time   `gorm:"column:dikirim_pada"`
+	CreatedAt    time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// SellerOrder satu baris inbox pesanan penjual
+type SellerOrder struct {
+	PaketToko
+	KodeInvoiceTrx   string `gorm:"column:kode_invoice_trx"`
+	MetodeBayar      string `gorm:"column:metode_bayar"`
+	IDPembeli        int    `gorm:"column:id_pembeli"`
+	AlamatPengiriman int    `gorm:"column:alamat_pengiriman"`
+	NoResi           string `gorm:"column:no_resi"`
+	Dropship         bool   `gorm:"column:dropship"`
+	NamaPengirim     string `gorm:"column:nama_pengirim"`
+	TelpPengirim     string `gorm:"column:telp_pengirim"`
+}
+
+// SellerOrderFilter filter inbox pesanan penjual
+type SellerOrderFilter struct {
+	IDUser int
+	Status string
+	Dari   *time.Time
+	Sampai *time.Time
+	Page   int
+	Limit  int
+}
+
+// SellerOrderItem baris produk dalam satu paket
+type SellerOrderItem struct {
+	IDTrx      int          `gorm:"column:id_trx"`
+	IDToko     int          `gorm:"column:id_toko"`
+	NamaProduk string       `gorm:"column:nama_produk"`
+	Kuantitas  int          `gorm:"column:kuantitas"`
+	HargaTotal money.Rupiah `gorm:"column:harga_total"`
+}
+
+func (PaketToko) TableName() string {
+	return "paket_toko"
+}
@@ -6,7 +6,12 @@ type Toko struct {
 	ID       int		`gorm:"primaryKey"`
 	IDUser   int
 	NamaToko string
+	PrefixInvoice string
 	UrlFoto  string
+	IDKota   string
+	// ringkasan ulasan, dihitung ulang setiap ada ulasan baru
+	RatingRata   float64 `gorm:"column:rating_rata;->"`
+	JumlahUlasan int     `gorm:"column:jumlah_ulasan;->"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
\ No newline at end of file
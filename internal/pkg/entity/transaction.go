@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
 
 type (
 	LogProduk struct {
@@ -10,19 +13,31 @@ type (
 		IDCategory     int       `gorm:"column:id_category;not null"`
 		NamaProduk     string    `gorm:"column:nama_produk"`
 		Slug           string    `gorm:"column:slug"`
-		HargaReseller  float64   `gorm:"column:harga_reseller"`
-		HargaKonsumen  float64   `gorm:"column:harga_konsumen"`
+		HargaReseller  money.Rupiah `gorm:"column:harga_reseller"`
+		HargaKonsumen  money.Rupiah `gorm:"column:harga_konsumen"`
 		Deskripsi      string    `gorm:"column:deskripsi"`
 		CreatedAt      time.Time
 		UpdatedAt      time.Time
 	}
 	Transaction struct {
-		ID               int       `gorm:"primaryKey;autoIncrement"`
-		IDUser           int       `gorm:"column:id_user;not null"`
-		AlamatPengiriman int       `gorm:"column:alamat_pengiriman;not null"`
-		HargaTotal       float64   `gorm:"column:harga_total"`
-		KodeInvoice      string    `gorm:"column:kode_invoice"`
-		MetodeBayar      string    `gorm:"column:metode_bayar"`
+		ID               int        `gorm:"primaryKey;autoIncrement"`
+		IDUser           int        `gorm:"column:id_user;not null"`
+		AlamatPengiriman int        `gorm:"column:alamat_pengiriman;not null"`
+		HargaTotal       money.Rupiah `gorm:"column:harga_total"`
+		IDVoucher        *int       `gorm:"column:id_voucher"`
+		Diskon           money.Rupiah `gorm:"column:diskon"`
+		OngkosKirim      money.Rupiah `gorm:"column:ongkos_kirim"`
+		KodeInvoice      string     `gorm:"column:kode_invoice"`
+		MetodeBayar      string     `gorm:"column:metode_bayar"`
+		Status           string     `gorm:"column:status"`
+		DibatalkanOleh   *int       `gorm:"column:dibatalkan_oleh"`
+		AlasanPembatalan string     `gorm:"column:alasan_pembatalan"`
+		DibatalkanPada   *time.Time `gorm:"column:dibatalkan_pada"`
+		// pesanan dropship: reseller dicetak sebagai pengirim di label dan invoice
+		Dropship               bool   `gorm:"column:dropship"`
+		NamaPengirim           string `gorm:"column:nama_pengirim"`
+		TelpPengirim           string `gorm:"column:telp_pengirim"`
+		TampilkanHargaReseller bool   `gorm:"column:tampilkan_harga_reseller"`
 		CreatedAt        time.Time
 		UpdatedAt        time.Time
 
@@ -35,14 +50,41 @@ type (
 		IDLogProduk int       `gorm:"column:id_log_produk;not null"`
 		IDToko      int       `gorm:"column:id_toko;not null"`
 		Kuantitas   int       `gorm:"column:kuantitas"`
-		HargaTotal  float64   `gorm:"column:harga_total"`
+		HargaTotal  money.Rupiah `gorm:"column:harga_total"`
+		Diskon      money.Rupiah `gorm:"column:diskon"`
 		CreatedAt   time.Time
 		UpdatedAt   time.Time
 	}
 
+	// TrxStockItem total kuantitas per produk dalam satu transaksi
+	TrxStockItem struct {
+		IDProduk  int `gorm:"column:id_produk"`
+		Kuantitas int `gorm:"column:kuantitas"`
+	}
+
 	ProdukWithOwner struct {
 		Produk
-		IDUser int `gorm:"column:id_user"`
+		IDUser            int    `gorm:"column:id_user"`
+		IDKotaToko        string `gorm:"column:id_kota_toko"`
+		NamaToko          string `gorm:"column:nama_toko"`
+		PrefixInvoiceToko string `gorm:"column:prefix_invoice_toko"`
+	}
+
+	// TrxFilter filter riwayat transaksi pembeli, Sampai bersifat eksklusif.
+	// IDPenjual membatasi ke baris toko milik penjual, IDUser dan IDPenjual kosong berarti semua trx (admin).
+	TrxFilter struct {
+		IDUser      int
+		IDPenjual   int
+		Dari        *time.Time
+		Sampai      *time.Time
+		MetodeBayar string
+		Status      string
+		IDToko      int
+		KodeInvoice string
+		Sort        string
+		Order       string
+		Page        int
+		Limit       int
 	}
 
 )
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// TrxExportLine satu baris detail_trx beserta data trx, paket toko dan snapshot log_produk
+type TrxExportLine struct {
+	IDTrx            int          `gorm:"column:id_trx"`
+	KodeInvoice      string       `gorm:"column:kode_invoice"`
+	Tanggal          time.Time    `gorm:"column:tanggal"`
+	Status           string       `gorm:"column:status"`
+	MetodeBayar      string       `gorm:"column:metode_bayar"`
+	IDPembeli        int          `gorm:"column:id_pembeli"`
+	IDToko           int          `gorm:"column:id_toko"`
+	NamaToko         string       `gorm:"column:nama_toko"`
+	KodeInvoicePaket string       `gorm:"column:kode_invoice_paket"`
+	Kurir            string       `gorm:"column:kurir"`
+	OngkirPaket      money.Rupiah `gorm:"column:ongkir_paket"`
+	IDDetail         int          `gorm:"column:id_detail"`
+	IDProduk         int          `gorm:"column:id_produk"`
+	NamaProduk       string       `gorm:"column:nama_produk"`
+	Slug             string       `gorm:"column:slug"`
+	NamaCategory     string       `gorm:"column:nama_category"`
+	HargaKonsumen    money.Rupiah `gorm:"column:harga_konsumen"`
+	HargaReseller    money.Rupiah `gorm:"column:harga_reseller"`
+	Kuantitas        int          `gorm:"column:kuantitas"`
+	Diskon           money.Rupiah `gorm:"column:diskon"`
+	HargaTotal       money.Rupiah `gorm:"column:harga_total"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// Status transaksi (order lifecycle)
+const (
+	TrxStatusPendingPayment = "pending_payment"
+	TrxStatusPaid           = "paid"
+	TrxStatusProcessing     = "processing"
+	TrxStatusShipped        = "shipped"
+	TrxStatusDelivered      = "delivered"
+	TrxStatusCompleted      = "completed"
+	TrxStatusCancelled      = "cancelled"
+	TrxStatusExpired        = "expired"
+)
+
+// TrxPaidStatuses status trx yang sudah dibayar dan tidak dibatalkan
+var TrxPaidStatuses = []string{
+	TrxStatusPaid,
+	TrxStatusProcessing,
+	TrxStatusShipped,
+	TrxStatusDelivered,
+	TrxStatusCompleted,
+}
+
+// Peran yang melakukan perubahan status
+const (
+	TrxActorBuyer  = "buyer"
+	TrxActorSeller = "seller"
+	TrxActorAdmin  = "admin"
+	TrxActorSystem = "system"
+)
+
+type TrxStatusHistory struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	IDTrx      int       `gorm:"column:id_trx;not null"`
+	DariStatus string    `gorm:"column:dari_status"`
+	KeStatus   string    `gorm:"column:ke_status;not null"`
+	DiubahOleh int       `gorm:"column:diubah_oleh"`
+	Peran      string    `gorm:"column:peran"`
+	Catatan    string    `gorm:"column:catatan"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (TrxStatusHistory) TableName() string {
+	return "trx_status_history"
+}
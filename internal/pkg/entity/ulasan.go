@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Ulasan penilaian pembeli untuk satu baris detail_trx, balasan diisi penjual toko
+type Ulasan struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement"`
+	IDDetailTrx int        `gorm:"column:id_detail_trx;not null"`
+	IDTrx       int        `gorm:"column:id_trx;not null"`
+	IDProduk    int        `gorm:"column:id_produk;not null"`
+	IDToko      int        `gorm:"column:id_toko;not null"`
+	IDUser      int        `gorm:"column:id_user;not null"`
+	Rating      int        `gorm:"column:rating;not null"`
+	Komentar    string     `gorm:"column:komentar;type:text"`
+	Balasan     *string    `gorm:"column:balasan;type:text"`
+	DibalasPada *time.Time `gorm:"column:dibalas_pada"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// UlasanDetail ulasan beserta nama pembeli dan produk
+type UlasanDetail struct {
+	Ulasan
+	NamaPembeli string `gorm:"column:nama_pembeli"`
+	NamaProduk  string `gorm:"column:nama_produk"`
+}
+
+type UlasanFoto struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	IDUlasan  int       `gorm:"column:id_ulasan;not null"`
+	Url       string    `gorm:"column:url;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// UlasanLine baris detail_trx yang akan diulas beserta pemilik dan status trx-nya
+type UlasanLine struct {
+	IDDetailTrx int    `gorm:"column:id_detail_trx"`
+	IDTrx       int    `gorm:"column:id_trx"`
+	IDProduk    int    `gorm:"column:id_produk"`
+	IDToko      int    `gorm:"column:id_toko"`
+	IDUser      int    `gorm:"column:id_user"`
+	StatusTrx   string `gorm:"column:status_trx"`
+	// kosong untuk trx lama yang belum punya paket_toko
+	StatusPaket string `gorm:"column:status_paket"`
+}
+
+// UlasanFilter salah satu dari IDProduk atau IDToko diisi
+type UlasanFilter struct {
+	IDProduk int
+	IDToko   int
+	Rating   int
+	Page     int
+	Limit    int
+}
+
+func (Ulasan) TableName() string {
+	return "ulasan"
+}
+
+func (UlasanFoto) TableName() string {
+	return "ulasan_foto"
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+// Tipe potongan voucher
+const (
+	VoucherTipePercent = "percent"
+	VoucherTipeFixed   = "fixed"
+)
+
+// Cakupan voucher
+const (
+	VoucherCakupanPlatform = "platform"
+	VoucherCakupanToko     = "toko"
+	VoucherCakupanCategory = "category"
+)
+
+type Voucher struct {
+	ID           int          `gorm:"column:id;primaryKey;autoIncrement"`
+	Kode         string       `gorm:"column:kode;not null"`
+	Tipe         string       `gorm:"column:tipe;not null"`
+	Persen       float64      `gorm:"column:persen;type:decimal(5,2);not null"`
+	Potongan     money.Rupiah `gorm:"column:potongan;type:bigint;not null"`
+	MinBelanja   money.Rupiah `gorm:"column:min_belanja;type:bigint"`
+	MaksDiskon   money.Rupiah `gorm:"column:maks_diskon;type:bigint"`
+	Kuota        int          `gorm:"column:kuota;not null"`
+	Terpakai     int          `gorm:"column:terpakai"`
+	LimitPerUser int          `gorm:"column:limit_per_user"`
+	Cakupan      string       `gorm:"column:cakupan;not null"`
+	IDToko       *int         `gorm:"column:id_toko"`
+	IDCategory   *int         `gorm:"column:id_category"`
+	Mulai        time.Time    `gorm:"column:mulai;not null"`
+	Berakhir     time.Time    `gorm:"column:berakhir;not null"`
+	IsActive     bool         `gorm:"column:is_active"`
+	DibuatOleh   int          `gorm:"column:dibuat_oleh;not null"`
+	CreatedAt    time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type VoucherUsage struct {
+	ID        int          `gorm:"column:id;primaryKey;autoIncrement"`
+	IDVoucher int          `gorm:"column:id_voucher;not null"`
+	IDUser    int          `gorm:"column:id_user;not null"`
+	IDTrx     int          `gorm:"column:id_trx;not null"`
+	Diskon    money.Rupiah `gorm:"column:diskon;type:bigint"`
+	CreatedAt time.Time    `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Voucher) TableName() string {
+	return "voucher"
+}
+
+func (VoucherUsage) TableName() string {
+	return "voucher_pemakaian"
+}
@@ -0,0 +1,68 @@
+package models
+
+import "pbi/internal/utils/money"
+
+type (
+	AdminGMVPoint struct {
+		Periode string       `json:"periode"`
+		Pesanan int64        `json:"pesanan"`
+		GMV     money.Rupiah `json:"gmv"`
+	}
+
+	AdminGMVResponse struct {
+		Dari    string          `json:"dari"`
+		Sampai  string          `json:"sampai"`
+		Periode string          `json:"periode"`
+		Data    []AdminGMVPoint `json:"data"`
+	}
+
+	AdminStatusCountResponse struct {
+		Status string `json:"status"`
+		Jumlah int64  `json:"jumlah"`
+	}
+
+	AdminSignupPoint struct {
+		Periode  string `json:"periode"`
+		UserBaru int64  `json:"user_baru"`
+		TokoBaru int64  `json:"toko_baru"`
+	}
+
+	AdminSignupResponse struct {
+		Dari    string             `json:"dari"`
+		Sampai  string             `json:"sampai"`
+		Periode string             `json:"periode"`
+		Data    []AdminSignupPoint `json:"data"`
+	}
+
+	AdminCategoryResponse struct {
+		IDCategory   int          `json:"id_category"`
+		NamaCategory string       `json:"nama_category"`
+		Unit         int64        `json:"unit"`
+		Pendapatan   money.Rupiah `json:"pendapatan"`
+	}
+
+	AdminTokoResponse struct {
+		IDToko     int          `json:"id_toko"`
+		NamaToko   string       `json:"nama_toko"`
+		Pesanan    int64        `json:"pesanan"`
+		Unit       int64        `json:"unit"`
+		Pendapatan money.Rupiah `json:"pendapatan"`
+	}
+
+	AdminResellerKomisiResponse struct {
+		IDUser   int          `json:"id_user"`
+		Nama     string       `json:"nama"`
+		Pesanan  int64        `json:"pesanan"`
+		Unit     int64        `json:"unit"`
+		Margin   money.Rupiah `json:"margin"`
+		Tertahan money.Rupiah `json:"tertahan"`
+		Tersedia money.Rupiah `json:"tersedia"`
+	}
+
+	// ReportFile hasil unduhan laporan
+	ReportFile struct {
+		FileName    string
+		ContentType string
+		Body        []byte
+	}
+)
@@ -0,0 +1,46 @@
+package models
+
+import "pbi/internal/utils/money"
+
+type (
+	AnalyticsSalesPoint struct {
+		Periode    string       `json:"periode"`
+		Pesanan    int64        `json:"pesanan"`
+		Unit       int64        `json:"unit"`
+		Pendapatan money.Rupiah `json:"pendapatan"`
+	}
+
+	AnalyticsSalesResponse struct {
+		Dari    string                `json:"dari"`
+		Sampai  string                `json:"sampai"`
+		Periode string                `json:"periode"`
+		Data    []AnalyticsSalesPoint `json:"data"`
+	}
+
+	AnalyticsProductResponse struct {
+		IDProduk   int          `json:"id_produk"`
+		NamaProduk string       `json:"nama_produk"`
+		Unit       int64        `json:"unit"`
+		Pendapatan money.Rupiah `json:"pendapatan"`
+	}
+
+	AnalyticsPaymentMethodResponse struct {
+		MetodeBayar string       `json:"metode_bayar"`
+		Pesanan     int64        `json:"pesanan"`
+		Pendapatan  money.Rupiah `json:"pendapatan"`
+		Persentase  float64      `json:"persentase"`
+	}
+
+	// AnalyticsSummaryResponse ringkasan penjualan, rata-rata pesanan dibulatkan ke bawah
+	AnalyticsSummaryResponse struct {
+		Dari                 string       `json:"dari"`
+		Sampai               string       `json:"sampai"`
+		Pesanan              int64        `json:"pesanan"`
+		Unit                 int64        `json:"unit"`
+		Pendapatan           money.Rupiah `json:"pendapatan"`
+		RataRataPesanan      money.Rupiah `json:"rata_rata_pesanan"`
+		Pembeli              int64        `json:"pembeli"`
+		PembeliBerulang      int64        `json:"pembeli_berulang"`
+		RasioPembeliBerulang float64      `json:"rasio_pembeli_berulang"`
+	}
+)
@@ -0,0 +1,47 @@
+package models
+
+import "pbi/internal/utils/money"
+
+type (
+	CartAddRequest struct {
+		ProductID int `json:"product_id" validate:"required"`
+		Kuantitas int `json:"kuantitas" validate:"required,min=1"`
+	}
+
+	CartUpdateRequest struct {
+		Kuantitas int `json:"kuantitas" validate:"required,min=1"`
+	}
+
+	CartCheckoutRequest struct {
+		CartIDs         []int                      `json:"cart_ids" validate:"required,min=1"`
+		MethodBayar     string                     `json:"method_bayar" validate:"required,oneof=ovo dana gopay cod"`
+		AlamatKirim     int                        `json:"alamat_kirim" validate:"required_without=Dropship,excluded_with=Dropship"`
+		Dropship        *DropshipRequest           `json:"dropship,omitempty"`
+		KodeVoucher     string                     `json:"kode_voucher" validate:"omitempty,max=64"`
+		Pengiriman      []CreateTrxShippingRequest `json:"pengiriman" validate:"required,min=1,dive"`
+		KonfirmasiHarga bool                       `json:"konfirmasi_harga"`
+	}
+
+	CartItemResponse struct {
+		ID                int          `json:"id"`
+		ProductID         int          `json:"product_id"`
+		NamaProduk        string       `json:"nama_produk"`
+		Toko              TokoInfo     `json:"toko"`
+		Kuantitas         int          `json:"kuantitas"`
+		HargaSaatDitambah money.Rupiah `json:"harga_saat_ditambah"`
+		HargaKonsumen     money.Rupiah `json:"harga_konsumen"`
+		Subtotal          money.Rupiah `json:"subtotal"`
+		Stok              int          `json:"stok"`
+		HargaBerubah      bool         `json:"harga_berubah"`
+		StokKurang        bool         `json:"stok_kurang"`
+	}
+
+	CartResponse struct {
+		Items      []CartItemResponse `json:"items"`
+		TotalHarga money.Rupiah       `json:"total_harga"`
+	}
+
+	CartCheckoutResponse struct {
+		TrxID int `json:"trx_id"`
+	}
+)
@@ -7,6 +7,7 @@ type (
 		NamaPenerima 	string 	`json:"nama_penerima" validate:"required"`
 		NoTelp       	string 	`json:"no_telp" validate:"required"`
 		DetailAlamat 	string 	`json:"detail_alamat" validate:"required"`
+		IDKota       	string 	`json:"id_kota" validate:"omitempty,numeric"`
 	}
 
 	DestinationResponse struct {
@@ -15,12 +16,14 @@ type (
 		NamaPenerima string `json:"nama_penerima"`
 		NoTelp       string `json:"no_telp"`
 		DetailAlamat string `json:"detail_alamat"`
+		IDKota       string `json:"id_kota"`
 	}
 
 	DestinationUpdateRequest struct {
 		NamaPenerima string `json:"nama_penerima"`
 		NoTelp       string `json:"no_telp"`
 		DetailAlamat string `json:"detail_alamat"`
+		IDKota       string `json:"id_kota"`
 	}
 
 )
\ No newline at end of file
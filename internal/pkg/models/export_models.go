@@ -0,0 +1,13 @@
+package models
+
+import (
+	"context"
+	"io"
+)
+
+// ExportStream file export yang isinya baru ditulis saat dikirim ke client
+type ExportStream struct {
+	FileName    string
+	ContentType string
+	Write       func(ctx context.Context, w io.Writer) error
+}
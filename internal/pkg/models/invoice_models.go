@@ -0,0 +1,54 @@
+package models
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	InvoiceItem struct {
+		NamaProduk    string
+		Kuantitas     int
+		HargaReseller money.Rupiah
+		HargaSatuan   money.Rupiah
+		Diskon        money.Rupiah
+		Total         money.Rupiah
+	}
+
+	// InvoiceToko satu paket toko dalam invoice
+	InvoiceToko struct {
+		NamaToko    string
+		KodeInvoice string
+		Kurir       string
+		Layanan     string
+		Ongkir      money.Rupiah
+		Items       []InvoiceItem
+	}
+
+	// InvoiceDocument data invoice yang siap dirender ke html atau pdf.
+	// Pesanan dropship mencetak reseller sebagai pengirim.
+	InvoiceDocument struct {
+		KodeInvoice            string
+		Tanggal                time.Time
+		Status                 string
+		MetodeBayar            string
+		NamaPenerima           string
+		NoTelp                 string
+		DetailAlamat           string
+		Dropship               bool
+		NamaPengirim           string
+		TelpPengirim           string
+		TampilkanHargaReseller bool
+		Toko                   []InvoiceToko
+		Subtotal               money.Rupiah
+		Diskon                 money.Rupiah
+		OngkosKirim            money.Rupiah
+		Total                  money.Rupiah
+	}
+
+	InvoiceFile struct {
+		FileName    string
+		ContentType string
+		Body        []byte
+	}
+)
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	KomisiSaldoResponse struct {
+		Tertahan money.Rupiah `json:"tertahan"`
+		Tersedia money.Rupiah `json:"tersedia"`
+		Total    money.Rupiah `json:"total"`
+	}
+
+	KomisiEntryResponse struct {
+		ID            int          `json:"id"`
+		IDTrx         int          `json:"id_trx"`
+		KodeInvoice   string       `json:"kode_invoice"`
+		IDDetailTrx   int          `json:"id_detail_trx"`
+		IDToko        int          `json:"id_toko"`
+		IDProduk      int          `json:"id_produk"`
+		NamaProduk    string       `json:"nama_produk"`
+		IDRetur       *int         `json:"id_retur,omitempty"`
+		Jenis         string       `json:"jenis"`
+		Kuantitas     int          `json:"kuantitas"`
+		HargaReseller money.Rupiah `json:"harga_reseller"`
+		HargaKonsumen money.Rupiah `json:"harga_konsumen"`
+		Margin        money.Rupiah `json:"margin"`
+		Status        string       `json:"status"`
+		TersediaPada  *time.Time   `json:"tersedia_pada"`
+		CreatedAt     time.Time    `json:"created_at"`
+	}
+
+	KomisiListResponse struct {
+		Data       []KomisiEntryResponse `json:"data"`
+		Total      int64                 `json:"total"`
+		Page       int                   `json:"page"`
+		Limit      int                   `json:"limit"`
+		TotalPages int                   `json:"total_pages"`
+	}
+)
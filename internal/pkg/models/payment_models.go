@@ -0,0 +1,22 @@
+package models
+
+import "pbi/internal/utils/money"
+
+type (
+	PaymentResponse struct {
+		IDTrx      int          `json:"id_trx"`
+		Provider   string       `json:"provider"`
+		Referensi  string       `json:"referensi"`
+		Jumlah     money.Rupiah `json:"jumlah"`
+		Status     string       `json:"status"`
+		PaymentURL string       `json:"payment_url,omitempty"`
+		Instruksi  string       `json:"instruksi,omitempty"`
+	}
+
+	// PaymentCallbackRequest payload webhook yang dikirim provider
+	PaymentCallbackRequest struct {
+		Referensi string       `json:"referensi"`
+		Status    string       `json:"status"`
+		Jumlah    money.Rupiah `json:"jumlah"`
+	}
+)
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type (
+	PengirimanEventResponse struct {
+		Status     string    `json:"status"`
+		Keterangan string    `json:"keterangan"`
+		Lokasi     string    `json:"lokasi"`
+		Waktu      time.Time `json:"waktu"`
+	}
+
+	// PengirimanResponse resi satu paket toko beserta riwayat pelacakannya, terbaru lebih dulu
+	PengirimanResponse struct {
+		ID              int                       `json:"id"`
+		IDPaket         int                       `json:"id_paket"`
+		IDToko          int                       `json:"id_toko"`
+		Kurir           string                    `json:"kurir"`
+		NoResi          string                    `json:"no_resi"`
+		Status          string                    `json:"status"`
+		TerakhirDilacak *time.Time                `json:"terakhir_dilacak"`
+		SampaiPada      *time.Time                `json:"sampai_pada"`
+		Events          []PengirimanEventResponse `json:"events"`
+	}
+)
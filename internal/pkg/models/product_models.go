@@ -2,6 +2,7 @@ package models
 
 import (
 	"mime/multipart"
+	"pbi/internal/utils/money"
 )
 
 type (
@@ -9,9 +10,10 @@ type (
 		IDCategory    int                   `form:"id_category" validate:"required"`
 		NamaProduk    string                `form:"nama_produk" validate:"required"`
 		Slug          string                `form:"slug"`
-		HargaReseller float64               `form:"harga_reseller" validate:"required"`
-		HargaKonsumen float64               `form:"harga_konsumen" validate:"required"`
+		HargaReseller money.Rupiah          `form:"harga_reseller" validate:"required"`
+		HargaKonsumen money.Rupiah          `form:"harga_konsumen" validate:"required"`
 		Stok          int                   `form:"stok" validate:"required"`
+		Berat         int                   `form:"berat" validate:"min=0"`
 		Deskripsi     string                `form:"deskripsi"`
 		Photos        []*multipart.FileHeader `form:"photos"`
 	}
@@ -20,8 +22,8 @@ type (
 		NamaProduk string  `json:"nama_produk"`
 		CategoryID int     `json:"category_id"`
 		TokoID     int     `json:"toko_id"`
-		MinHarga   float64 `json:"min_harga"`
-		MaxHarga   float64 `json:"max_harga"`
+		MinHarga   money.Rupiah `json:"min_harga"`
+		MaxHarga   money.Rupiah `json:"max_harga"`
 		Page       int     `json:"page"`
 		Limit      int     `json:"limit"`
 	}
@@ -30,10 +32,15 @@ type (
 		ID            int                    `json:"id"`
 		NamaProduk    string                 `json:"nama_produk"`
 		Slug          string                 `json:"slug"`
-		HargaReseller float64                `json:"harga_reseler"`
-		HargaKonsumen float64                `json:"harga_konsumen"`
+		HargaReseller money.Rupiah           `json:"harga_reseler"`
+		HargaKonsumen money.Rupiah           `json:"harga_konsumen"`
 		Stok          int                    `json:"stok"`
+		StokFisik     int                    `json:"stok_fisik"`
+		StokDitahan   int                    `json:"stok_ditahan"`
+		Berat         int                    `json:"berat"`
 		Deskripsi     string                 `json:"deskripsi"`
+		RatingRata    float64                `json:"rating_rata"`
+		JumlahUlasan  int                    `json:"jumlah_ulasan"`
 		Toko          *TokoResponse          `json:"toko"`
 		Category      *CategoryResponse      `json:"category"`
 		Photos        []ProductPhotoResponse `json:"photos"`
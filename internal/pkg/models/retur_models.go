@@ -0,0 +1,74 @@
+package models
+
+import (
+	"mime/multipart"
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	ReturItemRequest struct {
+		IDDetailTrx int `json:"id_detail_trx" validate:"required"`
+		Kuantitas   int `json:"kuantitas" validate:"required,min=1"`
+	}
+
+	ReturCreateRequest struct {
+		IDTrx      int                     `form:"id_trx" validate:"required"`
+		Alasan     string                  `form:"alasan" validate:"required,oneof=rusak salah_kirim tidak_sesuai kurang lainnya"`
+		Keterangan string                  `form:"keterangan" validate:"max=1000"`
+		Items      []ReturItemRequest      `form:"items" validate:"required,min=1,dive"`
+		Photos     []*multipart.FileHeader `form:"photos"`
+	}
+
+	ApproveReturRequest struct {
+		Restock bool   `json:"restock"`
+		Catatan string `json:"catatan" validate:"max=255"`
+	}
+
+	RejectReturRequest struct {
+		Catatan string `json:"catatan" validate:"required,max=255"`
+	}
+
+	DisputeReturRequest struct {
+		Catatan string `json:"catatan" validate:"required,max=255"`
+	}
+
+	ResolveReturRequest struct {
+		Keputusan string `json:"keputusan" validate:"required,oneof=approved rejected"`
+		Restock   bool   `json:"restock"`
+		Catatan   string `json:"catatan" validate:"required,max=255"`
+	}
+
+	ReturItemResponse struct {
+		IDDetailTrx int          `json:"id_detail_trx"`
+		NamaProduk  string       `json:"nama_produk"`
+		Kuantitas   int          `json:"kuantitas"`
+		Jumlah      money.Rupiah `json:"jumlah"`
+	}
+
+	RefundResponse struct {
+		ID     int          `json:"id"`
+		Jumlah money.Rupiah `json:"jumlah"`
+		Status string       `json:"status"`
+	}
+
+	ReturResponse struct {
+		ID             int                 `json:"id"`
+		IDTrx          int                 `json:"id_trx"`
+		IDToko         int                 `json:"id_toko"`
+		IDUser         int                 `json:"id_user"`
+		Alasan         string              `json:"alasan"`
+		Keterangan     string              `json:"keterangan"`
+		Status         string              `json:"status"`
+		Restock        bool                `json:"restock"`
+		CatatanPenjual string              `json:"catatan_penjual,omitempty"`
+		CatatanBanding string              `json:"catatan_banding,omitempty"`
+		CatatanAdmin   string              `json:"catatan_admin,omitempty"`
+		Items          []ReturItemResponse `json:"items"`
+		Photos         []string            `json:"photos"`
+		TotalRetur     money.Rupiah        `json:"total_retur"`
+		Refund         *RefundResponse     `json:"refund"`
+		DiputuskanPada *time.Time          `json:"diputuskan_pada"`
+		CreatedAt      time.Time           `json:"created_at"`
+	}
+)
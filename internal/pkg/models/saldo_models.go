@@ -0,0 +1,101 @@
+package models
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	SaldoTokoResponse struct {
+		IDToko          int          `json:"id_toko"`
+		NamaToko        string       `json:"nama_toko"`
+		Saldo           money.Rupiah `json:"saldo"`
+		PenarikanProses money.Rupiah `json:"penarikan_proses"`
+	}
+
+	MutasiSaldoResponse struct {
+		ID          int          `json:"id"`
+		Jenis       string       `json:"jenis"`
+		IDTrx       *int         `json:"id_trx,omitempty"`
+		IDDetailTrx *int         `json:"id_detail_trx,omitempty"`
+		IDRefund    *int         `json:"id_refund,omitempty"`
+		IDPenarikan *int         `json:"id_penarikan,omitempty"`
+		Jumlah      money.Rupiah `json:"jumlah"`
+		Saldo       money.Rupiah `json:"saldo"`
+		Keterangan  string       `json:"keterangan"`
+		CreatedAt   time.Time    `json:"created_at"`
+	}
+
+	MutasiListResponse struct {
+		Data       []MutasiSaldoResponse `json:"data"`
+		Total      int64                 `json:"total"`
+		Page       int                   `json:"page"`
+		Limit      int                   `json:"limit"`
+		TotalPages int                   `json:"total_pages"`
+	}
+
+	RekeningRequest struct {
+		IDToko        int    `json:"id_toko" validate:"required"`
+		NamaBank      string `json:"nama_bank" validate:"required,max=100"`
+		NomorRekening string `json:"nomor_rekening" validate:"required,numeric,max=50"`
+		AtasNama      string `json:"atas_nama" validate:"required,max=255"`
+	}
+
+	RekeningResponse struct {
+		ID            int       `json:"id"`
+		IDToko        int       `json:"id_toko"`
+		NamaBank      string    `json:"nama_bank"`
+		NomorRekening string    `json:"nomor_rekening"`
+		AtasNama      string    `json:"atas_nama"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	PenarikanRequest struct {
+		IDToko     int          `json:"id_toko" validate:"required"`
+		IDRekening int          `json:"id_rekening" validate:"required"`
+		Jumlah     money.Rupiah `json:"jumlah" validate:"required,gt=0"`
+	}
+
+	PenarikanResponse struct {
+		ID             int              `json:"id"`
+		IDToko         int              `json:"id_toko"`
+		NamaToko       string           `json:"nama_toko"`
+		Rekening       RekeningResponse `json:"rekening"`
+		Jumlah         money.Rupiah     `json:"jumlah"`
+		Status         string           `json:"status"`
+		AlasanTolak    string           `json:"alasan_tolak,omitempty"`
+		Referensi      string           `json:"referensi,omitempty"`
+		DiputuskanPada *time.Time       `json:"diputuskan_pada"`
+		DibayarPada    *time.Time       `json:"dibayar_pada"`
+		CreatedAt      time.Time        `json:"created_at"`
+	}
+
+	PenarikanListResponse struct {
+		Data       []PenarikanResponse `json:"data"`
+		Total      int64               `json:"total"`
+		Page       int                 `json:"page"`
+		Limit      int                 `json:"limit"`
+		TotalPages int                 `json:"total_pages"`
+	}
+
+	RejectPenarikanRequest struct {
+		Alasan string `json:"alasan" validate:"required,max=255"`
+	}
+
+	PayPenarikanRequest struct {
+		Referensi string `json:"referensi" validate:"required,max=100"`
+	}
+
+	RekonsiliasiSaldoResponse struct {
+		IDToko             int          `json:"id_toko"`
+		SaldoToko          money.Rupiah `json:"saldo_toko"`
+		SaldoTerakhir      money.Rupiah `json:"saldo_terakhir"`
+		TotalMutasi        money.Rupiah `json:"total_mutasi"`
+		BarisTidakSesuai   int64        `json:"baris_tidak_sesuai"`
+		PenjualanBuku      money.Rupiah `json:"penjualan_buku"`
+		PenjualanDetailTrx money.Rupiah `json:"penjualan_detail_trx"`
+		RefundBuku         money.Rupiah `json:"refund_buku"`
+		RefundSumber       money.Rupiah `json:"refund_sumber"`
+		Sesuai             bool         `json:"sesuai"`
+	}
+)
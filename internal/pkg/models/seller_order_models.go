@@ -0,0 +1,76 @@
+package models
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	SellerOrderItemResponse struct {
+		NamaProduk string       `json:"nama_produk"`
+		Kuantitas  int          `json:"kuantitas"`
+		HargaTotal money.Rupiah `json:"harga_total"`
+	}
+
+	SellerOrderResponse struct {
+		ID             int                       `json:"id"`
+		IDTrx          int                       `json:"id_trx"`
+		IDToko         int                       `json:"id_toko"`
+		KodeInvoice    string                    `json:"kode_invoice"`
+		KodeInvoiceTrx string                    `json:"kode_invoice_trx"`
+		MethodBayar    string                    `json:"method_bayar"`
+		Status         string                    `json:"status"`
+		Kurir          string                    `json:"kurir"`
+		Layanan        string                    `json:"layanan"`
+		Berat          int                       `json:"berat"`
+		Ongkir         money.Rupiah              `json:"ongkir"`
+		AlasanTolak    string                    `json:"alasan_tolak,omitempty"`
+		NoResi         string                    `json:"no_resi,omitempty"`
+		AlamatKirim    DestinationResponse       `json:"alamat_kirim"`
+		Dropship       *DropshipResponse         `json:"dropship,omitempty"`
+		Items          []SellerOrderItemResponse `json:"items"`
+		DiterimaPada   *time.Time                `json:"diterima_pada"`
+		DikirimPada    *time.Time                `json:"dikirim_pada"`
+		CreatedAt      time.Time                 `json:"created_at"`
+	}
+
+	SellerOrderListResponse struct {
+		Data       []SellerOrderResponse `json:"data"`
+		Total      int64                 `json:"total"`
+		Page       int                   `json:"page"`
+		Limit      int                   `json:"limit"`
+		TotalPages int                   `json:"total_pages"`
+	}
+
+	RejectPaketRequest struct {
+		Alasan string `json:"alasan" validate:"required,max=255"`
+	}
+
+	// ShippingLabelItem isi paket di label, tanpa harga
+	ShippingLabelItem struct {
+		NamaProduk string
+		Kuantitas  int
+	}
+
+	// ShippingLabel data label pengiriman satu paket yang siap dirender ke html atau pdf.
+	// Pesanan dropship mencetak reseller sebagai pengirim, selain itu nama toko.
+	ShippingLabel struct {
+		KodeInvoice  string
+		Kurir        string
+		Layanan      string
+		NoResi       string
+		Berat        int
+		NamaPenerima string
+		NoTelp       string
+		DetailAlamat string
+		NamaPengirim string
+		TelpPengirim string
+		Items        []ShippingLabelItem
+	}
+
+	ShipPaketRequest struct {
+		NoResi  string `json:"no_resi" validate:"required,max=64"`
+		Kurir   string `json:"kurir" validate:"max=32"`
+		Catatan string `json:"catatan" validate:"max=255"`
+	}
+)
@@ -0,0 +1,40 @@
+package models
+
+import "pbi/internal/utils/money"
+
+type (
+	// ShippingQuoteRequest id_kota dipakai menggantikan alamat_kirim untuk pesanan dropship
+	ShippingQuoteRequest struct {
+		AlamatKirim int                    `json:"alamat_kirim" validate:"required_without=IDKota,excluded_with=IDKota"`
+		IDKota      string                 `json:"id_kota" validate:"omitempty,numeric"`
+		Items       []CreateTrxItemRequest `json:"items" validate:"required,min=1,dive"`
+	}
+
+	ShippingRateResponse struct {
+		Kurir    string       `json:"kurir"`
+		Layanan  string       `json:"layanan"`
+		Ongkir   money.Rupiah `json:"ongkir"`
+		Estimasi string       `json:"estimasi"`
+	}
+
+	// ShippingQuoteResponse pilihan kurir untuk satu toko
+	ShippingQuoteResponse struct {
+		IDToko   int                    `json:"id_toko"`
+		NamaToko string                 `json:"nama_toko"`
+		Berat    int                    `json:"berat"`
+		Layanan  []ShippingRateResponse `json:"layanan"`
+	}
+
+	PaketTokoResponse struct {
+		ID          int          `json:"id"`
+		IDToko      int          `json:"id_toko"`
+		KodeInvoice string       `json:"kode_invoice"`
+		Kurir       string       `json:"kurir"`
+		Layanan     string       `json:"layanan"`
+		Berat       int          `json:"berat"`
+		Ongkir      money.Rupiah `json:"ongkir"`
+		TotalRefund money.Rupiah `json:"total_refund"`
+		Estimasi    string       `json:"estimasi"`
+		Status      string       `json:"status"`
+	}
+)
@@ -8,13 +8,19 @@ type (
 	}
 
 	TokoResponse struct {
-		ID       int    `json:"id"`
-		NamaToko string `json:"nama_toko"`
-		UrlFoto  string `json:"url_foto"`
+		ID            int     `json:"id"`
+		NamaToko      string  `json:"nama_toko"`
+		UrlFoto       string  `json:"url_foto"`
+		IDKota        string  `json:"id_kota,omitempty"`
+		PrefixInvoice string  `json:"prefix_invoice,omitempty"`
+		RatingRata    float64 `json:"rating_rata"`
+		JumlahUlasan  int     `json:"jumlah_ulasan"`
 	}
 
 	TokoUpdateRequest struct {
-		NamaToko string `json:"nama_toko"`
-		UrlFoto  string `json:"url_foto"`
+		NamaToko      string `json:"nama_toko"`
+		UrlFoto       string `json:"url_foto"`
+		IDKota        string `json:"id_kota"`
+		PrefixInvoice string `json:"prefix_invoice"`
 	}
 )
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
 
 type TokoBasicInfo struct {
     NamaToko string `json:"nama_toko"`
@@ -29,8 +34,8 @@ type (
 		ID            int           `json:"id"`
 		NamaProduk    string        `json:"nama_produk"`
 		Slug          string        `json:"slug"`
-		HargaReseller int           `json:"harga_reseler"` 
-		HargaKonsumen int           `json:"harga_konsumen"`
+		HargaReseller money.Rupiah  `json:"harga_reseler"` 
+		HargaKonsumen money.Rupiah  `json:"harga_konsumen"`
 		Deskripsi     string        `json:"deskripsi"`
 		Toko          TokoBasicInfo `json:"toko"`     
 		Category      CategoryInfo  `json:"category"` 
@@ -42,18 +47,50 @@ type (
 		Kuantitas int `json:"kuantitas" validate:"required,min=1"`
 	}
 
+	// CreateTrxShippingRequest kurir pilihan pembeli untuk satu toko
+	CreateTrxShippingRequest struct {
+		IDToko  int    `json:"id_toko" validate:"required"`
+		Kurir   string `json:"kurir" validate:"required"`
+		Layanan string `json:"layanan" validate:"required"`
+	}
+
+	// DropshipRequest penerima pelanggan akhir dan identitas reseller sebagai pengirim,
+	// dipakai menggantikan alamat_kirim yang tersimpan
+	DropshipRequest struct {
+		NamaPenerima           string `json:"nama_penerima" validate:"required,max=255"`
+		NoTelp                 string `json:"no_telp" validate:"required,max=32"`
+		DetailAlamat           string `json:"detail_alamat" validate:"required,max=255"`
+		IDKota                 string `json:"id_kota" validate:"required,numeric"`
+		NamaPengirim           string `json:"nama_pengirim" validate:"required,max=255"`
+		TelpPengirim           string `json:"telp_pengirim" validate:"required,max=32"`
+		TampilkanHargaReseller bool   `json:"tampilkan_harga_reseller"`
+	}
+
 	CreateTrxRequest struct {
-		MethodBayar string                 `json:"method_bayar" validate:"required,oneof=ovo dana gopay cod"`
-		AlamatKirim int                    `json:"alamat_kirim" validate:"required"`
-		DetailTrx   []CreateTrxItemRequest `json:"detail_trx" validate:"required,min=1,dive"`
+		MethodBayar string                     `json:"method_bayar" validate:"required,oneof=ovo dana gopay cod"`
+		AlamatKirim int                        `json:"alamat_kirim" validate:"required_without=Dropship,excluded_with=Dropship"`
+		Dropship    *DropshipRequest           `json:"dropship,omitempty"`
+		KodeVoucher string                     `json:"kode_voucher" validate:"omitempty,max=64"`
+		DetailTrx   []CreateTrxItemRequest     `json:"detail_trx" validate:"required,min=1,dive"`
+		Pengiriman  []CreateTrxShippingRequest `json:"pengiriman" validate:"required,min=1,dive"`
+	}
+
+	DropshipResponse struct {
+		NamaPengirim           string `json:"nama_pengirim"`
+		TelpPengirim           string `json:"telp_pengirim"`
+		TampilkanHargaReseller bool   `json:"tampilkan_harga_reseller"`
 	}
 
 	TransactionListResponse struct {
 		ID           	int    						`json:"id"`
-		HargaTotal   	float64     						`json:"harga_total"`
+		HargaTotal   	money.Rupiah 						`json:"harga_total"`
+		Diskon       	money.Rupiah 						`json:"diskon"`
+		OngkosKirim  	money.Rupiah 						`json:"ongkos_kirim"`
 		KodeInvoice  	string 						`json:"kode_invoice"`
 		MethodBayar 	string 						`json:"method_bayar"`
+		Status      	string 						`json:"status"`
 		AlamatKirim 	DestinationResponse 		`json:"alamat_kirim"`
+		Dropship    	*DropshipResponse   		`json:"dropship,omitempty"`
 		DetailTrx    	[]TransactionDetailResponse `json:"detail_trx"`
 	}
 
@@ -61,22 +98,48 @@ type (
 		Product    ProductDetailInTransaction `json:"product"`
 		Toko       TokoInfo                   `json:"toko"`
 		Kuantitas  int                        `json:"kuantitas"`
-		HargaTotal int                        `json:"harga_total"`
+		HargaTotal money.Rupiah               `json:"harga_total"`
+		Diskon     money.Rupiah               `json:"diskon"`
 	}
 
 	TransactionDetailByIDResponse struct {
 		ID          int                         `json:"id"`
-		HargaTotal  float64                     `json:"harga_total"`
+		HargaTotal  money.Rupiah                `json:"harga_total"`
+		Diskon      money.Rupiah                `json:"diskon"`
+		OngkosKirim money.Rupiah                `json:"ongkos_kirim"`
 		KodeInvoice string                      `json:"kode_invoice"`
 		MethodBayar string                      `json:"method_bayar"`
+		Status      string                      `json:"status"`
 		AlamatKirim DestinationResponse         `json:"alamat_kirim"`
+		Dropship    *DropshipResponse           `json:"dropship,omitempty"`
 		DetailTrx   []TransactionDetailResponse `json:"detail_trx"`
+		Pengiriman  []PaketTokoResponse         `json:"pengiriman"`
+	}
+
+	UpdateTrxStatusRequest struct {
+		Status  string `json:"status" validate:"required,oneof=paid processing shipped delivered completed cancelled expired"`
+		Catatan string `json:"catatan" validate:"max=255"`
+	}
+
+	CancelTrxRequest struct {
+		Alasan string `json:"alasan" validate:"required,max=255"`
+	}
+
+	TrxStatusHistoryResponse struct {
+		DariStatus string    `json:"dari_status"`
+		KeStatus   string    `json:"ke_status"`
+		DiubahOleh int       `json:"diubah_oleh"`
+		Peran      string    `json:"peran"`
+		Catatan    string    `json:"catatan"`
+		CreatedAt  time.Time `json:"created_at"`
 	}
 	
 )
 
 type TransactionListResponseWrapper struct {
-    Data  []TransactionListResponse `json:"data"`
-    Page  int                       `json:"page"`
-    Limit int                       `json:"limit"`
+    Data       []TransactionListResponse `json:"data"`
+    Total      int64                     `json:"total"`
+    Page       int                       `json:"page"`
+    Limit      int                       `json:"limit"`
+    TotalPages int                       `json:"total_pages"`
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type (
+	UlasanCreateRequest struct {
+		IDDetailTrx int                     `form:"id_detail_trx" validate:"required"`
+		Rating      int                     `form:"rating" validate:"required,min=1,max=5"`
+		Komentar    string                  `form:"komentar" validate:"max=2000"`
+		Photos      []*multipart.FileHeader `form:"photos" validate:"max=5"`
+	}
+
+	BalasUlasanRequest struct {
+		Balasan string `json:"balasan" validate:"required,max=2000"`
+	}
+
+	UlasanResponse struct {
+		ID          int        `json:"id"`
+		IDProduk    int        `json:"id_produk"`
+		NamaProduk  string     `json:"nama_produk"`
+		IDToko      int        `json:"id_toko"`
+		NamaPembeli string     `json:"nama_pembeli"`
+		Rating      int        `json:"rating"`
+		Komentar    string     `json:"komentar"`
+		Photos      []string   `json:"photos"`
+		Balasan     *string    `json:"balasan"`
+		DibalasPada *time.Time `json:"dibalas_pada"`
+		CreatedAt   time.Time  `json:"created_at"`
+	}
+
+	UlasanListResponse struct {
+		Data       []UlasanResponse `json:"data"`
+		Total      int64            `json:"total"`
+		Page       int              `json:"page"`
+		Limit      int              `json:"limit"`
+		TotalPages int              `json:"total_pages"`
+	}
+)
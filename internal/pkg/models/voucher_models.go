@@ -0,0 +1,43 @@
+package models
+
+import (
+	"pbi/internal/utils/money"
+	"time"
+)
+
+type (
+	VoucherCreateRequest struct {
+		Kode         string       `json:"kode" validate:"required,alphanum,max=64"`
+		Tipe         string       `json:"tipe" validate:"required,oneof=percent fixed"`
+		Persen       float64      `json:"persen" validate:"required_if=Tipe percent,min=0,max=100"`
+		Potongan     money.Rupiah `json:"potongan" validate:"required_if=Tipe fixed,min=0"`
+		MinBelanja   money.Rupiah `json:"min_belanja" validate:"min=0"`
+		MaksDiskon   money.Rupiah `json:"maks_diskon" validate:"min=0"`
+		Kuota        int          `json:"kuota" validate:"required,min=1"`
+		LimitPerUser int          `json:"limit_per_user" validate:"min=0"`
+		Cakupan      string       `json:"cakupan" validate:"required,oneof=platform toko category"`
+		IDToko       int          `json:"id_toko"`
+		IDCategory   int          `json:"id_category"`
+		Mulai        time.Time    `json:"mulai" validate:"required"`
+		Berakhir     time.Time    `json:"berakhir" validate:"required"`
+	}
+
+	VoucherResponse struct {
+		ID           int          `json:"id"`
+		Kode         string       `json:"kode"`
+		Tipe         string       `json:"tipe"`
+		Persen       float64      `json:"persen"`
+		Potongan     money.Rupiah `json:"potongan"`
+		MinBelanja   money.Rupiah `json:"min_belanja"`
+		MaksDiskon   money.Rupiah `json:"maks_diskon"`
+		Kuota        int          `json:"kuota"`
+		Terpakai     int          `json:"terpakai"`
+		LimitPerUser int          `json:"limit_per_user"`
+		Cakupan      string       `json:"cakupan"`
+		IDToko       *int         `json:"id_toko"`
+		IDCategory   *int         `json:"id_category"`
+		Mulai        time.Time    `json:"mulai"`
+		Berakhir     time.Time    `json:"berakhir"`
+		IsActive     bool         `json:"is_active"`
+	}
+)
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+type AdminReportRepository interface {
+	GMVSeries(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.GMVPoint, error)
+	OrderStatusCounts(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.StatusCount, error)
+	NewUsers(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PeriodeCount, error)
+	NewTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PeriodeCount, error)
+	TopCategories(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.CategorySales, error)
+	TopTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.TokoSales, error)
+	PaymentMethods(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PaymentMethodSales, error)
+	ResellerKomisi(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.ResellerKomisi, error)
+}
+
+type adminReportImpl struct {
+	db *gorm.DB
+}
+
+func NewAdminReportRepo(db *gorm.DB) AdminReportRepository {
+	return &adminReportImpl{
+		db: db,
+	}
+}
+
+// createdBetween membatasi kolom created_at ke rentang tanggal filter (sampai inklusif)
+func createdBetween(q *gorm.DB, column string, filter *entity.AdminReportFilter) *gorm.DB {
+	return q.Where(column+" >= ? AND "+column+" < ?", filter.Dari, filter.Sampai.AddDate(0, 0, 1))
+}
+
+// rekapBetween membatasi tabel rekap harian ke rentang tanggal filter
+func rekapBetween(q *gorm.DB, table string, filter *entity.AdminReportFilter) *gorm.DB {
+	return q.Where(table+".tanggal BETWEEN ? AND ?", filter.Dari.Format("2006-01-02"), filter.Sampai.Format("2006-01-02"))
+}
+
+func (r *adminReportImpl) GMVSeries(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.GMVPoint, error) {
+	var res []entity.GMVPoint
+
+	err := createdBetween(r.db.WithContext(ctx).Table("trx"), "trx.created_at", filter).
+		Select(periodeColumn(filter.Periode, "trx.created_at")+" AS periode, COUNT(*) AS pesanan, COALESCE(SUM(trx.harga_total), 0) AS gmv").
+		Where("trx.status IN ?", entity.TrxPaidStatuses).
+		Group("periode").
+		Order("periode ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *adminReportImpl) OrderStatusCounts(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.StatusCount, error) {
+	var res []entity.StatusCount
+
+	err := createdBetween(r.db.WithContext(ctx).Table("trx"), "trx.created_at", filter).
+		Select("trx.status, COUNT(*) AS jumlah").
+		Group("trx.status").
+		Order("jumlah DESC, trx.status ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *adminReportImpl) NewUsers(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PeriodeCount, error) {
+	return r.countCreated(ctx, "user", filter)
+}
+
+func (r *adminReportImpl) NewTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PeriodeCount, error) {
+	return r.countCreated(ctx, "toko", filter)
+}
+
+func (r *adminReportImpl) countCreated(ctx context.Context, table string, filter *entity.AdminReportFilter) ([]entity.PeriodeCount, error) {
+	var res []entity.PeriodeCount
+
+	err := createdBetween(r.db.WithContext(ctx).Table(table), "created_at", filter).
+		Select(periodeColumn(filter.Periode, "created_at") + " AS periode, COUNT(*) AS jumlah").
+		Group("periode").
+		Order("periode ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+// TopCategories dari rekap produk, kategori mengikuti kategori produk saat ini
+func (r *adminReportImpl) TopCategories(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.CategorySales, error) {
+	var res []entity.CategorySales
+
+	err := rekapBetween(r.db.WithContext(ctx).Table("rekap_produk_harian"), "rekap_produk_harian", filter).
+		Select("produk.id_category, COALESCE(category.nama_category, '') AS nama_category, SUM(rekap_produk_harian.unit) AS unit, SUM(rekap_produk_harian.pendapatan) AS pendapatan").
+		Joins("JOIN produk ON produk.id = rekap_produk_harian.id_produk").
+		Joins("LEFT JOIN category ON category.id = produk.id_category").
+		Group("produk.id_category, category.nama_category").
+		Having("SUM(rekap_produk_harian.unit) > 0").
+		Order("pendapatan DESC, produk.id_category ASC").
+		Limit(filter.Limit).
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *adminReportImpl) TopTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.TokoSales, error) {
+	var res []entity.TokoSales
+
+	err := rekapBetween(r.db.WithContext(ctx).Table("rekap_toko_harian"), "rekap_toko_harian", filter).
+		Select("rekap_toko_harian.id_toko, COALESCE(toko.nama_toko, '') AS nama_toko, SUM(rekap_toko_harian.pesanan) AS pesanan, SUM(rekap_toko_harian.unit) AS unit, SUM(rekap_toko_harian.pendapatan) AS pendapatan").
+		Joins("LEFT JOIN toko ON toko.id = rekap_toko_harian.id_toko").
+		Group("rekap_toko_harian.id_toko, toko.nama_toko").
+		Having("SUM(rekap_toko_harian.pesanan) > 0").
+		Order("pendapatan DESC, rekap_toko_harian.id_toko ASC").
+		Limit(filter.Limit).
+		Find(&res).Error
+
+	return res, err
+}
+
+// PaymentMethods dihitung per trx, bukan per paket toko, supaya satu pesanan dihitung sekali
+func (r *adminReportImpl) PaymentMethods(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.PaymentMethodSales, error) {
+	var res []entity.PaymentMethodSales
+
+	err := createdBetween(r.db.WithContext(ctx).Table("trx"), "trx.created_at", filter).
+		Select("COALESCE(trx.metode_bayar, '') AS metode_bayar, COUNT(*) AS pesanan, COALESCE(SUM(trx.harga_total), 0) AS pendapatan").
+		Where("trx.status IN ?", entity.TrxPaidStatuses).
+		Group("COALESCE(trx.metode_bayar, '')").
+		Order("pendapatan DESC, metode_bayar ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+// ResellerKomisi komisi bersih per reseller dari entri buku komisi dalam rentang tanggal.
+// Pesanan dan unit hanya dihitung dari entri penjualan.
+func (r *adminReportImpl) ResellerKomisi(ctx context.Context, filter *entity.AdminReportFilter) ([]entity.ResellerKomisi, error) {
+	var res []entity.ResellerKomisi
+
+	err := createdBetween(r.db.WithContext(ctx).Table("komisi_reseller"), "komisi_reseller.created_at", filter).
+		Select(`komisi_reseller.id_user, COALESCE(user.nama, '') AS nama,
+			COUNT(DISTINCT IF(komisi_reseller.jenis = ?, komisi_reseller.id_trx, NULL)) AS pesanan,
+			COALESCE(SUM(IF(komisi_reseller.jenis = ?, komisi_reseller.kuantitas, 0)), 0) AS unit,
+			SUM(komisi_reseller.margin) AS margin,
+			SUM(IF(komisi_reseller.status = ?, komisi_reseller.margin, 0)) AS tertahan,
+			SUM(IF(komisi_reseller.status = ?, komisi_reseller.margin, 0)) AS tersedia`,
+			entity.KomisiJenisPenjualan, entity.KomisiJenisPenjualan, entity.KomisiStatusTertahan, entity.KomisiStatusTersedia).
+		Joins("LEFT JOIN user ON user.id = komisi_reseller.id_user").
+		Group("komisi_reseller.id_user, user.nama").
+		Order("margin DESC, komisi_reseller.id_user ASC").
+		Limit(filter.Limit).
+		Find(&res).Error
+
+	return res, err
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository interface {
+	RecordTrx(ctx context.Context, tx *gorm.DB, trxID int, sign int) error
+	RecordToko(ctx context.Context, tx *gorm.DB, trxID int, tokoID int, sign int) error
+	SalesSeries(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.SalesPoint, error)
+	SalesTotal(ctx context.Context, filter *entity.AnalyticsFilter) (*entity.SalesPoint, error)
+	TopProducts(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.ProductSales, error)
+	PaymentMethods(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.PaymentMethodSales, error)
+	BuyerStats(ctx context.Context, filter *entity.AnalyticsFilter) (*entity.BuyerStats, error)
+}
+
+type analyticsImpl struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepo(db *gorm.DB) AnalyticsRepository {
+	return &analyticsImpl{
+		db: db,
+	}
+}
+
+// ekspresi awal periode (YYYY-MM-DD) dari kolom tanggal/datetime, minggu dimulai hari Senin
+var analyticsPeriodeColumns = map[string]string{
+	entity.AnalyticsPeriodeDay:   "DATE_FORMAT(%[1]s, '%%Y-%%m-%%d')",
+	entity.AnalyticsPeriodeWeek:  "DATE_FORMAT(DATE_SUB(DATE(%[1]s), INTERVAL WEEKDAY(%[1]s) DAY), '%%Y-%%m-%%d')",
+	entity.AnalyticsPeriodeMonth: "DATE_FORMAT(%[1]s, '%%Y-%%m-01')",
+}
+
+func periodeColumn(periode string, column string) string {
+	expr, ok := analyticsPeriodeColumns[periode]
+	if !ok {
+		expr = analyticsPeriodeColumns[entity.AnalyticsPeriodeDay]
+	}
+	return fmt.Sprintf(expr, column)
+}
+
+var analyticsProductSort = map[string]string{
+	"unit":       "unit DESC",
+	"pendapatan": "pendapatan DESC",
+}
+
+const (
+	rekapTokoSQL = `INSERT INTO rekap_toko_harian (id_toko, tanggal, metode_bayar, pesanan, unit, pendapatan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), COALESCE(trx.metode_bayar, ''), ?, ? * SUM(detail_trx.kuantitas), ? * SUM(detail_trx.harga_total)
+%s
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), COALESCE(trx.metode_bayar, '')
+ON DUPLICATE KEY UPDATE pesanan = pesanan + VALUES(pesanan), unit = unit + VALUES(unit), pendapatan = pendapatan + VALUES(pendapatan)`
+
+	rekapProdukSQL = `INSERT INTO rekap_produk_harian (id_toko, tanggal, id_produk, unit, pendapatan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), log_produk.id_produk, ? * SUM(detail_trx.kuantitas), ? * SUM(detail_trx.harga_total)
+%s
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), log_produk.id_produk
+ON DUPLICATE KEY UPDATE unit = unit + VALUES(unit), pendapatan = pendapatan + VALUES(pendapatan)`
+
+	rekapPembeliSQL = `INSERT INTO rekap_pembeli_harian (id_toko, tanggal, id_user, pesanan)
+SELECT detail_trx.id_toko, DATE(trx.created_at), trx.id_user, ?
+%s
+GROUP BY detail_trx.id_toko, DATE(trx.created_at), trx.id_user
+ON DUPLICATE KEY UPDATE pesanan = pesanan + VALUES(pesanan)`
+
+	rekapSourceSQL = `FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+WHERE detail_trx.id_trx = ? AND %s`
+)
+
+// RecordTrx menambah (sign 1) atau mengurangi (sign -1) rekap dengan seluruh paket trx
+// yang belum dibatalkan. Baris yang dibaca hanya detail_trx milik trx tersebut.
+func (r *analyticsImpl) RecordTrx(ctx context.Context, tx *gorm.DB, trxID int, sign int) error {
+	cond := "(paket_toko.id IS NULL OR paket_toko.status <> ?)"
+	return r.record(ctx, tx, sign, cond, trxID, entity.TrxStatusCancelled)
+}
+
+// RecordToko sama seperti RecordTrx untuk satu paket toko tanpa melihat status paketnya
+func (r *analyticsImpl) RecordToko(ctx context.Context, tx *gorm.DB, trxID int, tokoID int, sign int) error {
+	return r.record(ctx, tx, sign, "detail_trx.id_toko = ?", trxID, tokoID)
+}
+
+func (r *analyticsImpl) record(ctx context.Context, tx *gorm.DB, sign int, cond string, trxID int, arg interface{}) error {
+	source := fmt.Sprintf(rekapSourceSQL, cond)
+	db := tx.WithContext(ctx)
+
+	if err := db.Exec(fmt.Sprintf(rekapTokoSQL, source), sign, sign, sign, trxID, arg).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(fmt.Sprintf(rekapProdukSQL, source), sign, sign, trxID, arg).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(fmt.Sprintf(rekapPembeliSQL, source), sign, trxID, arg).Error
+}
+
+// analyticsScope membatasi tabel rekap ke toko milik user dan rentang tanggal filter
+func (r *analyticsImpl) analyticsScope(ctx context.Context, table string, filter *entity.AnalyticsFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).
+		Table(table).
+		Where(table+".id_toko IN (SELECT id FROM toko WHERE id_user = ?)", filter.IDUser).
+		Where(table+".tanggal BETWEEN ? AND ?", filter.Dari.Format("2006-01-02"), filter.Sampai.Format("2006-01-02"))
+
+	if filter.IDToko > 0 {
+		q = q.Where(table+".id_toko = ?", filter.IDToko)
+	}
+
+	return q
+}
+
+func (r *analyticsImpl) SalesSeries(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.SalesPoint, error) {
+	var res []entity.SalesPoint
+
+	err := r.analyticsScope(ctx, "rekap_toko_harian", filter).
+		Select(periodeColumn(filter.Periode, "tanggal") + " AS periode, SUM(pesanan) AS pesanan, SUM(unit) AS unit, SUM(pendapatan) AS pendapatan").
+		Group("periode").
+		Order("periode ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *analyticsImpl) SalesTotal(ctx context.Context, filter *entity.AnalyticsFilter) (*entity.SalesPoint, error) {
+	var res entity.SalesPoint
+
+	err := r.analyticsScope(ctx, "rekap_toko_harian", filter).
+		Select("COALESCE(SUM(pesanan), 0) AS pesanan, COALESCE(SUM(unit), 0) AS unit, COALESCE(SUM(pendapatan), 0) AS pendapatan").
+		Scan(&res).Error
+
+	return &res, err
+}
+
+func (r *analyticsImpl) TopProducts(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.ProductSales, error) {
+	order, ok := analyticsProductSort[filter.Sort]
+	if !ok {
+		order = analyticsProductSort["pendapatan"]
+	}
+
+	var res []entity.ProductSales
+
+	err := r.analyticsScope(ctx, "rekap_produk_harian", filter).
+		Select("rekap_produk_harian.id_produk, COALESCE(produk.nama_produk, '') AS nama_produk, SUM(rekap_produk_harian.unit) AS unit, SUM(rekap_produk_harian.pendapatan) AS pendapatan").
+		Joins("LEFT JOIN produk ON produk.id = rekap_produk_harian.id_produk").
+		Group("rekap_produk_harian.id_produk, produk.nama_produk").
+		Having("SUM(rekap_produk_harian.unit) > 0").
+		Order(order + ", rekap_produk_harian.id_produk ASC").
+		Limit(filter.Limit).
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *analyticsImpl) PaymentMethods(ctx context.Context, filter *entity.AnalyticsFilter) ([]entity.PaymentMethodSales, error) {
+	var res []entity.PaymentMethodSales
+
+	err := r.analyticsScope(ctx, "rekap_toko_harian", filter).
+		Select("metode_bayar, SUM(pesanan) AS pesanan, SUM(pendapatan) AS pendapatan").
+		Group("metode_bayar").
+		Having("SUM(pesanan) > 0").
+		Order("pendapatan DESC, metode_bayar ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+// BuyerStats pembeli berulang adalah pembeli dengan dua pesanan atau lebih dalam rentang filter
+func (r *analyticsImpl) BuyerStats(ctx context.Context, filter *entity.AnalyticsFilter) (*entity.BuyerStats, error) {
+	perBuyer := r.analyticsScope(ctx, "rekap_pembeli_harian", filter).
+		Select("id_user, SUM(pesanan) AS pesanan").
+		Group("id_user").
+		Having("SUM(pesanan) > 0")
+
+	var res entity.BuyerStats
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS pembeli", perBuyer).
+		Select("COUNT(*) AS pembeli, COALESCE(SUM(pesanan >= 2), 0) AS pembeli_berulang").
+		Scan(&res).Error
+
+	return &res, err
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository interface {
+	Add(ctx context.Context, item *entity.CartItem) error
+	UpdateKuantitas(ctx context.Context, cartID int, userID int, qty int, harga money.Rupiah) error
+	Delete(ctx context.Context, cartID int, userID int) error
+	DeleteByIDs(ctx context.Context, userID int, cartIDs []int) error
+	ListByUser(ctx context.Context, userID int, cartIDs []int) ([]entity.CartItemWithProduk, error)
+	KuantitasProduk(ctx context.Context, userID int, produkID int) (int, error)
+}
+
+type cartImpl struct {
+	db *gorm.DB
+}
+
+func NewCartRepo(db *gorm.DB) CartRepository {
+	return &cartImpl{
+		db: db,
+	}
+}
+
+// Add menambah produk ke keranjang, kuantitas dijumlahkan jika produk sudah ada
+func (r *cartImpl) Add(ctx context.Context, item *entity.CartItem) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"kuantitas":           gorm.Expr("kuantitas + ?", item.Kuantitas),
+				"harga_saat_ditambah": item.HargaSaatDitambah,
+			}),
+		}).
+		Create(item).Error
+}
+
+func (r *cartImpl) UpdateKuantitas(ctx context.Context, cartID int, userID int, qty int, harga money.Rupiah) error {
+	res := r.db.WithContext(ctx).
+		Model(&entity.CartItem{}).
+		Where("id = ? AND id_user = ?", cartID, userID).
+		Updates(map[string]interface{}{
+			"kuantitas":           qty,
+			"harga_saat_ditambah": harga,
+		})
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *cartImpl) Delete(ctx context.Context, cartID int, userID int) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND id_user = ?", cartID, userID).
+		Delete(&entity.CartItem{})
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *cartImpl) DeleteByIDs(ctx context.Context, userID int, cartIDs []int) error {
+	return r.db.WithContext(ctx).
+		Where("id_user = ? AND id IN ?", userID, cartIDs).
+		Delete(&entity.CartItem{}).Error
+}
+
+// ListByUser mengambil isi keranjang dengan harga & stok tersedia terkini dari produk.
+// cartIDs kosong berarti semua baris keranjang user.
+func (r *cartImpl) ListByUser(ctx context.Context, userID int, cartIDs []int) ([]entity.CartItemWithProduk, error) {
+	var items []entity.CartItemWithProduk
+
+	query := r.db.WithContext(ctx).
+		Table("keranjang").
+		Select(`
+			keranjang.*,
+			produk.nama_produk,
+			produk.harga_konsumen,
+			GREATEST(produk.stok - produk.stok_ditahan, 0) AS stok,
+			produk.id_toko,
+			toko.nama_toko
+		`).
+		Joins("JOIN produk ON produk.id = keranjang.id_produk").
+		Joins("JOIN toko ON toko.id = produk.id_toko").
+		Where("keranjang.id_user = ?", userID)
+
+	if len(cartIDs) > 0 {
+		query = query.Where("keranjang.id IN ?", cartIDs)
+	}
+
+	err := query.Order("keranjang.id ASC").Find(&items).Error
+	return items, err
+}
+
+// KuantitasProduk kuantitas produk yang sudah ada di keranjang user, 0 jika belum ada
+func (r *cartImpl) KuantitasProduk(ctx context.Context, userID int, produkID int) (int, error) {
+	var qty int
+
+	err := r.db.WithContext(ctx).
+		Model(&entity.CartItem{}).
+		Select("COALESCE(SUM(kuantitas), 0)").
+		Where("id_user = ? AND id_produk = ?", userID, produkID).
+		Scan(&qty).Error
+
+	return qty, err
+}
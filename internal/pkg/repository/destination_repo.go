@@ -13,6 +13,8 @@ type DestinationRepository interface {
 	Delete(ctx context.Context, id int, userID int) error
 	FindByID(ctx context.Context, id int, userID int) (*entity.Destination, error)
 	FindAllByUserID(ctx context.Context, userID int) ([]entity.Destination, error)
+	FindByIDs(ctx context.Context, ids []int) ([]entity.Destination, error)
+	CreateDropship(ctx context.Context, tx *gorm.DB, dest *entity.Destination) error
 }
 
 type destinationImpl struct {
@@ -32,13 +34,13 @@ func (d *destinationImpl) Create(ctx context.Context,req *entity.Destination) er
 func (d *destinationImpl) Update(ctx context.Context, req *entity.Destination) error {
 	return d.orm.WithContext(ctx).
 		Model(&entity.Destination{}).
-		Where("id = ? AND id_user = ?", req.ID, req.UserID).
+		Where("id = ? AND id_user = ? AND dropship = ?", req.ID, req.UserID, false).
 		Updates(req).Error
 }
 
 func (d *destinationImpl) Delete(ctx context.Context, id int, userID int) error {
 	return d.orm.WithContext(ctx).
-		Where("id = ? AND id_user = ?", id, userID).
+		Where("id = ? AND id_user = ? AND dropship = ?", id, userID, false).
 		Delete(&entity.Destination{}).Error
 }
 
@@ -46,7 +48,7 @@ func (d *destinationImpl) FindByID(ctx context.Context, id int, userID int) (*en
 	var dest entity.Destination
 
 	err := d.orm.WithContext(ctx).
-		Where("id = ? AND id_user = ?", id, userID).
+		Where("id = ? AND id_user = ? AND dropship = ?", id, userID, false).
 		First(&dest).Error
 
 	if err != nil {
@@ -61,7 +63,7 @@ func (d *destinationImpl) FindAllByUserID(ctx context.Context,userID int,) ([]en
 	var dests []entity.Destination
 
 	err := d.orm.WithContext(ctx).
-		Where("id_user = ?", userID).
+		Where("id_user = ? AND dropship = ?", userID, false).
 		Order("id DESC").
 		Find(&dests).
 		Error
@@ -72,3 +74,24 @@ func (d *destinationImpl) FindAllByUserID(ctx context.Context,userID int,) ([]en
 
 	return dests, nil
 }
+
+// FindByIDs dipakai penjual untuk melihat alamat kirim pesanan, tanpa filter pemilik
+func (d *destinationImpl) FindByIDs(ctx context.Context, ids []int) ([]entity.Destination, error) {
+	var dests []entity.Destination
+	if len(ids) == 0 {
+		return dests, nil
+	}
+
+	err := d.orm.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&dests).
+		Error
+
+	return dests, err
+}
+
+// CreateDropship menyimpan alamat penerima pesanan dropship di dalam tx checkout
+func (d *destinationImpl) CreateDropship(ctx context.Context, tx *gorm.DB, dest *entity.Destination) error {
+	dest.Dropship = true
+	return tx.WithContext(ctx).Create(dest).Error
+}
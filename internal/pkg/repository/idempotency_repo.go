@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, key *entity.IdempotencyKey) error
+	SetTrx(ctx context.Context, tx *gorm.DB, keyID int, trxID int) error
+	Find(ctx context.Context, userID int, idemKey string) (*entity.IdempotencyKey, error)
+	DeleteExpired(ctx context.Context, keyID int, now time.Time) error
+}
+
+type idempotencyImpl struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepo(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyImpl{
+		db: db,
+	}
+}
+
+func (r *idempotencyImpl) Create(ctx context.Context, tx *gorm.DB, key *entity.IdempotencyKey) error {
+	return tx.WithContext(ctx).Create(key).Error
+}
+
+func (r *idempotencyImpl) SetTrx(ctx context.Context, tx *gorm.DB, keyID int, trxID int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.IdempotencyKey{}).
+		Where("id = ?", keyID).
+		Update("id_trx", trxID).Error
+}
+
+func (r *idempotencyImpl) Find(ctx context.Context, userID int, idemKey string) (*entity.IdempotencyKey, error) {
+	var key entity.IdempotencyKey
+
+	err := r.db.WithContext(ctx).
+		Where("id_user = ? AND idem_key = ?", userID, idemKey).
+		First(&key).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *idempotencyImpl) DeleteExpired(ctx context.Context, keyID int, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND expires_at < ?", keyID, now).
+		Delete(&entity.IdempotencyKey{}).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	NextSequence(ctx context.Context, tx *gorm.DB, scope string) (int, error)
+}
+
+type invoiceImpl struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepo(db *gorm.DB) InvoiceRepository {
+	return &invoiceImpl{
+		db: db,
+	}
+}
+
+// NextSequence menaikkan urutan untuk scope lalu membaca hasilnya.
+// Baris invoice_sequence ter-lock sampai tx selesai, jadi checkout lain pada scope yang sama
+// menunggu commit/rollback dan nomor tidak pernah loncat atau kembar.
+func (r *invoiceImpl) NextSequence(ctx context.Context, tx *gorm.DB, scope string) (int, error) {
+	err := tx.WithContext(ctx).Exec(`
+		INSERT INTO invoice_sequence (scope, nomor) VALUES (?, 1)
+		ON DUPLICATE KEY UPDATE nomor = nomor + 1`, scope).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var nomor int
+	err = tx.WithContext(ctx).
+		Raw("SELECT nomor FROM invoice_sequence WHERE scope = ?", scope).
+		Scan(&nomor).Error
+
+	return nomor, err
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+)
+
+type KomisiRepository interface {
+	PostSales(ctx context.Context, tx *gorm.DB, trxID int) error
+	Reverse(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) error
+	PostRetur(ctx context.Context, tx *gorm.DB, returID int) error
+	Release(ctx context.Context, tx *gorm.DB, trxID int) error
+	Saldo(ctx context.Context, userID int) (*entity.KomisiSaldo, error)
+	List(ctx context.Context, filter *entity.KomisiFilter) ([]entity.KomisiEntry, int64, error)
+}
+
+type komisiImpl struct {
+	db *gorm.DB
+}
+
+func NewKomisiRepo(db *gorm.DB) KomisiRepository {
+	return &komisiImpl{
+		db: db,
+	}
+}
+
+// margin per unit dari snapshot log_produk, reseller tidak pernah menanggung margin negatif
+const komisiMarginUnit = "GREATEST(log_produk.harga_konsumen - log_produk.harga_reseller, 0)"
+
+const (
+	komisiSalesSQL = `INSERT INTO komisi_reseller (id_user, id_trx, id_detail_trx, id_toko, id_produk, jenis, kuantitas, harga_reseller, harga_konsumen, margin, status)
+SELECT trx.id_user, trx.id, detail_trx.id, detail_trx.id_toko, log_produk.id_produk, ?, detail_trx.kuantitas,
+	log_produk.harga_reseller, log_produk.harga_konsumen, ` + komisiMarginUnit + ` * detail_trx.kuantitas, ?
+FROM detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+WHERE detail_trx.id_trx = ?
+ORDER BY detail_trx.id`
+
+	komisiReverseSQL = `INSERT INTO komisi_reseller (id_user, id_trx, id_detail_trx, id_toko, id_produk, jenis, kuantitas, harga_reseller, harga_konsumen, margin, status)
+SELECT id_user, id_trx, id_detail_trx, id_toko, id_produk, ?, -SUM(kuantitas), MAX(harga_reseller), MAX(harga_konsumen), -SUM(margin), ?
+FROM komisi_reseller
+WHERE id_trx = ? AND %s
+GROUP BY id_user, id_trx, id_detail_trx, id_toko, id_produk
+HAVING SUM(kuantitas) <> 0 OR SUM(margin) <> 0
+ORDER BY id_detail_trx`
+
+	komisiReturSQL = `INSERT INTO komisi_reseller (id_user, id_trx, id_detail_trx, id_toko, id_produk, id_retur, jenis, kuantitas, harga_reseller, harga_konsumen, margin, status, tersedia_pada)
+SELECT trx.id_user, trx.id, detail_trx.id, detail_trx.id_toko, log_produk.id_produk, retur_item.id_retur, ?, -retur_item.kuantitas,
+	log_produk.harga_reseller, log_produk.harga_konsumen, -` + komisiMarginUnit + ` * retur_item.kuantitas,
+	IF(trx.status = ?, ?, ?), IF(trx.status = ?, NOW(), NULL)
+FROM retur_item
+JOIN detail_trx ON detail_trx.id = retur_item.id_detail_trx
+JOIN trx ON trx.id = detail_trx.id_trx
+JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+WHERE retur_item.id_retur = ?
+ORDER BY retur_item.id`
+)
+
+// PostSales mencatat margin setiap detail_trx ketika pesanan dibayar, status tertahan
+func (r *komisiImpl) PostSales(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return tx.WithContext(ctx).
+		Exec(komisiSalesSQL, entity.KomisiJenisPenjualan, entity.KomisiStatusTertahan, trxID).Error
+}
+
+// Reverse menolkan saldo bersih setiap detail_trx milik trx, tokoID 0 berarti semua toko.
+// Baris yang sudah nol (misalnya paket yang lebih dulu ditolak) dilewati.
+func (r *komisiImpl) Reverse(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) error {
+	cond := "1 = 1"
+	args := []interface{}{entity.KomisiJenisPembatalan, entity.KomisiStatusTertahan, trxID}
+	if tokoID > 0 {
+		cond = "id_toko = ?"
+		args = append(args, tokoID)
+	}
+
+	return tx.WithContext(ctx).Exec(fmt.Sprintf(komisiReverseSQL, cond), args...).Error
+}
+
+// PostRetur mengurangi margin sebanyak kuantitas yang diretur. Bila trx sudah completed
+// entri langsung tersedia supaya mengurangi saldo yang bisa ditarik.
+func (r *komisiImpl) PostRetur(ctx context.Context, tx *gorm.DB, returID int) error {
+	return tx.WithContext(ctx).
+		Exec(komisiReturSQL,
+			entity.KomisiJenisRetur,
+			entity.TrxStatusCompleted, entity.KomisiStatusTersedia, entity.KomisiStatusTertahan,
+			entity.TrxStatusCompleted,
+			returID).Error
+}
+
+// Release membuat semua entri tertahan milik trx tersedia untuk ditarik
+func (r *komisiImpl) Release(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.KomisiReseller{}).
+		Where("id_trx = ? AND status = ?", trxID, entity.KomisiStatusTertahan).
+		Updates(map[string]interface{}{
+			"status":        entity.KomisiStatusTersedia,
+			"tersedia_pada": gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *komisiImpl) Saldo(ctx context.Context, userID int) (*entity.KomisiSaldo, error) {
+	var res entity.KomisiSaldo
+
+	err := r.db.WithContext(ctx).
+		Table("komisi_reseller").
+		Select("COALESCE(SUM(IF(status = ?, margin, 0)), 0) AS tertahan, COALESCE(SUM(IF(status = ?, margin, 0)), 0) AS tersedia",
+			entity.KomisiStatusTertahan, entity.KomisiStatusTersedia).
+		Where("id_user = ?", userID).
+		Scan(&res).Error
+
+	return &res, err
+}
+
+func (r *komisiImpl) List(ctx context.Context, filter *entity.KomisiFilter) ([]entity.KomisiEntry, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("komisi_reseller").
+		Where("komisi_reseller.id_user = ?", filter.IDUser)
+
+	if filter.Status != "" {
+		query = query.Where("komisi_reseller.status = ?", filter.Status)
+	}
+	if filter.Jenis != "" {
+		query = query.Where("komisi_reseller.jenis = ?", filter.Jenis)
+	}
+	if filter.Dari != nil {
+		query = query.Where("komisi_reseller.created_at >= ?", *filter.Dari)
+	}
+	if filter.Sampai != nil {
+		query = query.Where("komisi_reseller.created_at < ?", *filter.Sampai)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entity.KomisiEntry
+	err := query.
+		Select("komisi_reseller.*, COALESCE(trx.kode_invoice, '') AS kode_invoice, log_produk.nama_produk").
+		Joins("JOIN trx ON trx.id = komisi_reseller.id_trx").
+		Joins("JOIN detail_trx ON detail_trx.id = komisi_reseller.id_detail_trx").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Order("komisi_reseller.created_at DESC, komisi_reseller.id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&entries).Error
+
+	return entries, total, err
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, event *entity.OutboxEvent) error
+	StokProduk(ctx context.Context, tx *gorm.DB, produkID int) (*entity.ProdukStok, error)
+	ListDueForUpdate(ctx context.Context, tx *gorm.DB, jenis []string, now time.Time, limit int) ([]entity.OutboxEvent, error)
+	Lease(ctx context.Context, tx *gorm.DB, eventIDs []int64, until time.Time) error
+	ListTerkirim(ctx context.Context, eventIDs []int64) ([]entity.OutboxTerkirim, error)
+	AddTerkirim(ctx context.Context, eventID int64, subscriber string) error
+	MarkDelivered(ctx context.Context, eventID int64, at time.Time) error
+	MarkRetry(ctx context.Context, eventID int64, percobaan int, status string, errMsg string, next time.Time) error
+	DeleteTerkirim(ctx context.Context, tx *gorm.DB, filter *entity.OutboxReplayFilter) error
+	Reset(ctx context.Context, tx *gorm.DB, filter *entity.OutboxReplayFilter, now time.Time) (int64, error)
+	DeleteDelivered(ctx context.Context, tx *gorm.DB, before time.Time, limit int) (int64, error)
+}
+
+type outboxImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *gorm.DB) OutboxRepository {
+	return &outboxImpl{
+		db: db,
+	}
+}
+
+func (r *outboxImpl) Create(ctx context.Context, tx *gorm.DB, event *entity.OutboxEvent) error {
+	return tx.WithContext(ctx).Create(event).Error
+}
+
+// StokProduk dibaca di tx yang sama dengan perubahan stoknya, baris produk sudah di-lock oleh UPDATE
+func (r *outboxImpl) StokProduk(ctx context.Context, tx *gorm.DB, produkID int) (*entity.ProdukStok, error) {
+	var stok entity.ProdukStok
+
+	err := tx.WithContext(ctx).
+		Table("produk").
+		Select("id, stok, stok_ditahan").
+		Where("id = ?", produkID).
+		Take(&stok).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &stok, nil
+}
+
+// ListDueForUpdate event pending yang sudah waktunya dikirim, baris yang sedang dikunci dispatcher lain dilewati.
+// jenis nil berarti semua jenis event.
+func (r *outboxImpl) ListDueForUpdate(ctx context.Context, tx *gorm.DB, jenis []string, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+
+	query := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND tersedia_pada <= ?", entity.OutboxStatusPending, now)
+
+	if jenis != nil {
+		query = query.Where("jenis IN ?", jenis)
+	}
+
+	err := query.
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+
+	return events, err
+}
+
+// Lease menunda event yang sedang dikirim, bila proses berhenti di tengah jalan event diambil lagi setelah until
+func (r *outboxImpl) Lease(ctx context.Context, tx *gorm.DB, eventIDs []int64, until time.Time) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id IN ?", eventIDs).
+		Update("tersedia_pada", until).Error
+}
+
+func (r *outboxImpl) ListTerkirim(ctx context.Context, eventIDs []int64) ([]entity.OutboxTerkirim, error) {
+	var res []entity.OutboxTerkirim
+	if len(eventIDs) == 0 {
+		return res, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("id_event IN ?", eventIDs).
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *outboxImpl) AddTerkirim(ctx context.Context, eventID int64, subscriber string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.OutboxTerkirim{IDEvent: eventID, Subscriber: subscriber}).Error
+}
+
+func (r *outboxImpl) MarkDelivered(ctx context.Context, eventID int64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"status":        entity.OutboxStatusDelivered,
+			"terkirim_pada": at,
+		}).Error
+}
+
+func (r *outboxImpl) MarkRetry(ctx context.Context, eventID int64, percobaan int, status string, errMsg string, next time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"percobaan":      percobaan,
+			"error_terakhir": errMsg,
+			"tersedia_pada":  next,
+		}).Error
+}
+
+// DeleteTerkirim menghapus tanda terkirim supaya event dikirim ulang ke subscriber tersebut
+func (r *outboxImpl) DeleteTerkirim(ctx context.Context, tx *gorm.DB, filter *entity.OutboxReplayFilter) error {
+	query := tx.WithContext(ctx).
+		Where("id_event IN (?)", outboxReplayScope(tx.Model(&entity.OutboxEvent{}).Select("id"), filter))
+
+	if filter.Subscriber != "" {
+		query = query.Where("subscriber = ?", filter.Subscriber)
+	}
+
+	return query.Delete(&entity.OutboxTerkirim{}).Error
+}
+
+// Reset mengembalikan event ke pending dengan percobaan dari nol
+func (r *outboxImpl) Reset(ctx context.Context, tx *gorm.DB, filter *entity.OutboxReplayFilter, now time.Time) (int64, error) {
+	res := outboxReplayScope(tx.WithContext(ctx).Model(&entity.OutboxEvent{}), filter).
+		Updates(map[string]interface{}{
+			"status":         entity.OutboxStatusPending,
+			"percobaan":      0,
+			"error_terakhir": nil,
+			"tersedia_pada":  now,
+			"terkirim_pada":  nil,
+		})
+
+	return res.RowsAffected, res.Error
+}
+
+// DeleteDelivered menghapus paling banyak limit event delivered yang terkirim sebelum before,
+// tanda terkirim dihapus lebih dulu karena foreign key
+func (r *outboxImpl) DeleteDelivered(ctx context.Context, tx *gorm.DB, before time.Time, limit int) (int64, error) {
+	var ids []int64
+
+	err := tx.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("status = ? AND terkirim_pada < ?", entity.OutboxStatusDelivered, before).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	if err := tx.WithContext(ctx).Where("id_event IN ?", ids).Delete(&entity.OutboxTerkirim{}).Error; err != nil {
+		return 0, err
+	}
+
+	res := tx.WithContext(ctx).Where("id IN ?", ids).Delete(&entity.OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
+
+func outboxReplayScope(query *gorm.DB, filter *entity.OutboxReplayFilter) *gorm.DB {
+	if filter.DariID > 0 {
+		query = query.Where("outbox_event.id >= ?", filter.DariID)
+	}
+	if filter.SampaiID > 0 {
+		query = query.Where("outbox_event.id <= ?", filter.SampaiID)
+	}
+	if filter.Jenis != "" {
+		query = query.Where("outbox_event.jenis = ?", filter.Jenis)
+	}
+	if filter.Status != "" {
+		query = query.Where("outbox_event.status = ?", filter.Status)
+	}
+	if filter.Dari != nil {
+		query = query.Where("outbox_event.created_at >= ?", *filter.Dari)
+	}
+	if filter.Sampai != nil {
+		query = query.Where("outbox_event.created_at < ?", *filter.Sampai)
+	}
+	return query
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaketRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, paket *entity.PaketToko) error
+	ListByTrx(ctx context.Context, trxID int) ([]entity.PaketToko, error)
+	ListByTrxForUpdate(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.PaketToko, error)
+	FindForSeller(ctx context.Context, paketID int, userID int) (*entity.PaketToko, error)
+	UpdateStatus(ctx context.Context, tx *gorm.DB, paketID int, from string, to string, fields map[string]interface{}) error
+	SyncStatus(ctx context.Context, tx *gorm.DB, trxID int, to string) error
+	ListSellerOrders(ctx context.Context, filter *entity.SellerOrderFilter) ([]entity.SellerOrder, int64, error)
+	ListSellerOrderItems(ctx context.Context, userID int, trxIDs []int) ([]entity.SellerOrderItem, error)
+	AddRefund(ctx context.Context, tx *gorm.DB, trxID int, tokoID int, jumlah money.Rupiah) error
+	TotalBarang(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) (money.Rupiah, error)
+}
+
+type paketImpl struct {
+	db *gorm.DB
+}
+
+func NewPaketRepo(db *gorm.DB) PaketRepository {
+	return &paketImpl{
+		db: db,
+	}
+}
+
+func (r *paketImpl) Create(ctx context.Context, tx *gorm.DB, paket *entity.PaketToko) error {
+	return tx.WithContext(ctx).Create(paket).Error
+}
+
+func (r *paketImpl) ListByTrx(ctx context.Context, trxID int) ([]entity.PaketToko, error) {
+	var pakets []entity.PaketToko
+
+	err := r.db.WithContext(ctx).
+		Where("id_trx = ?", trxID).
+		Order("id ASC").
+		Find(&pakets).Error
+
+	return pakets, err
+}
+
+// ListByTrxForUpdate mengunci semua paket milik trx, dipanggil setelah baris trx di-lock
+func (r *paketImpl) ListByTrxForUpdate(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.PaketToko, error) {
+	var pakets []entity.PaketToko
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_trx = ?", trxID).
+		Order("id ASC").
+		Find(&pakets).Error
+
+	return pakets, err
+}
+
+// FindForSeller mencari paket milik toko user, paket toko lain dianggap tidak ada
+func (r *paketImpl) FindForSeller(ctx context.Context, paketID int, userID int) (*entity.PaketToko, error) {
+	var paket entity.PaketToko
+
+	err := r.db.WithContext(ctx).
+		Table("paket_toko").
+		Select("paket_toko.*").
+		Joins("JOIN toko ON toko.id = paket_toko.id_toko").
+		Where("paket_toko.id = ? AND toko.id_user = ?", paketID, userID).
+		First(&paket).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &paket, nil
+}
+
+func (r *paketImpl) UpdateStatus(ctx context.Context, tx *gorm.DB, paketID int, from string, to string, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+
+	res := tx.WithContext(ctx).
+		Model(&entity.PaketToko{}).
+		Where("id = ? AND status = ?", paketID, from).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SyncStatus menyamakan status paket yang belum dibatalkan dengan status trx
+func (r *paketImpl) SyncStatus(ctx context.Context, tx *gorm.DB, trxID int, to string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.PaketToko{}).
+		Where("id_trx = ? AND status <> ?", trxID, entity.TrxStatusCancelled).
+		Update("status", to).Error
+}
+
+func (r *paketImpl) ListSellerOrders(ctx context.Context, filter *entity.SellerOrderFilter) ([]entity.SellerOrder, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("paket_toko").
+		Joins("JOIN toko ON toko.id = paket_toko.id_toko").
+		Joins("JOIN trx ON trx.id = paket_toko.id_trx").
+		Joins("LEFT JOIN pengiriman ON pengiriman.id_paket = paket_toko.id").
+		Where("toko.id_user = ?", filter.IDUser)
+
+	if filter.Status != "" {
+		query = query.Where("paket_toko.status = ?", filter.Status)
+	}
+	if filter.Dari != nil {
+		query = query.Where("paket_toko.created_at >= ?", *filter.Dari)
+	}
+	if filter.Sampai != nil {
+		query = query.Where("paket_toko.created_at < ?", *filter.Sampai)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []entity.SellerOrder
+	err := query.
+		Select("paket_toko.*, trx.kode_invoice AS kode_invoice_trx, trx.metode_bayar, trx.id_user AS id_pembeli, trx.alamat_pengiriman, COALESCE(pengiriman.no_resi, '') AS no_resi, trx.dropship, trx.nama_pengirim, trx.telp_pengirim").
+		Order("paket_toko.created_at DESC, paket_toko.id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&orders).Error
+
+	return orders, total, err
+}
+
+// ListSellerOrderItems mengambil baris produk milik toko user untuk sekumpulan trx sekaligus
+func (r *paketImpl) ListSellerOrderItems(ctx context.Context, userID int, trxIDs []int) ([]entity.SellerOrderItem, error) {
+	var items []entity.SellerOrderItem
+	if len(trxIDs) == 0 {
+		return items, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("detail_trx").
+		Select("detail_trx.id_trx, detail_trx.id_toko, log_produk.nama_produk, detail_trx.kuantitas, detail_trx.harga_total").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Joins("JOIN toko ON toko.id = detail_trx.id_toko").
+		Where("toko.id_user = ? AND detail_trx.id_trx IN ?", userID, trxIDs).
+		Order("detail_trx.id ASC").
+		Find(&items).Error
+
+	return items, err
+}
+
+// AddRefund menambah total refund paket, mengurangi hak toko atas paket tersebut
+func (r *paketImpl) AddRefund(ctx context.Context, tx *gorm.DB, trxID int, tokoID int, jumlah money.Rupiah) error {
+	return tx.WithContext(ctx).
+		Model(&entity.PaketToko{}).
+		Where("id_trx = ? AND id_toko = ?", trxID, tokoID).
+		Update("total_refund", gorm.Expr("total_refund + ?", jumlah)).Error
+}
+
+// TotalBarang total harga baris detail_trx milik satu paket toko setelah diskon, tanpa ongkir
+func (r *paketImpl) TotalBarang(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) (money.Rupiah, error) {
+	var total money.Rupiah
+
+	err := tx.WithContext(ctx).
+		Table("detail_trx").
+		Select("COALESCE(SUM(harga_total), 0)").
+		Where("id_trx = ? AND id_toko = ?", trxID, tokoID).
+		Scan(&total).Error
+
+	return total, err
+}
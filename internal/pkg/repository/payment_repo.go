@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, payment *entity.Payment) error
+	UpdateIntent(ctx context.Context, tx *gorm.DB, paymentID int, paymentURL string, instruksi string) error
+	FindPendingByTrx(ctx context.Context, trxID int) (*entity.Payment, error)
+	FindByReferensi(ctx context.Context, referensi string) (*entity.Payment, error)
+	FindByReferensiForUpdate(ctx context.Context, tx *gorm.DB, referensi string) (*entity.Payment, error)
+	UpdateStatus(ctx context.Context, tx *gorm.DB, paymentID int, status string) error
+	FlagRefund(ctx context.Context, tx *gorm.DB, paymentID int, catatan string) error
+	FlagRefundByTrx(ctx context.Context, tx *gorm.DB, trxID int, catatan string) error
+}
+
+type paymentImpl struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepo(db *gorm.DB) PaymentRepository {
+	return &paymentImpl{
+		db: db,
+	}
+}
+
+func (r *paymentImpl) Create(ctx context.Context, tx *gorm.DB, payment *entity.Payment) error {
+	return tx.WithContext(ctx).Create(payment).Error
+}
+
+func (r *paymentImpl) UpdateIntent(ctx context.Context, tx *gorm.DB, paymentID int, paymentURL string, instruksi string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ?", paymentID).
+		Updates(map[string]interface{}{
+			"payment_url": paymentURL,
+			"instruksi":   instruksi,
+		}).Error
+}
+
+func (r *paymentImpl) FindPendingByTrx(ctx context.Context, trxID int) (*entity.Payment, error) {
+	var payment entity.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("id_trx = ? AND status = ?", trxID, entity.PaymentStatusPending).
+		Order("id DESC").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentImpl) FindByReferensi(ctx context.Context, referensi string) (*entity.Payment, error) {
+	var payment entity.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("referensi = ?", referensi).
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentImpl) FindByReferensiForUpdate(ctx context.Context, tx *gorm.DB, referensi string) (*entity.Payment, error) {
+	var payment entity.Payment
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("referensi = ?", referensi).
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentImpl) UpdateStatus(ctx context.Context, tx *gorm.DB, paymentID int, status string) error {
+	updates := map[string]interface{}{
+		"status": status,
+	}
+	if status == entity.PaymentStatusPaid {
+		updates["dibayar_pada"] = time.Now()
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ?", paymentID).
+		Updates(updates).Error
+}
+
+func (r *paymentImpl) FlagRefund(ctx context.Context, tx *gorm.DB, paymentID int, catatan string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ?", paymentID).
+		Updates(map[string]interface{}{
+			"perlu_refund":   true,
+			"catatan_refund": catatan,
+		}).Error
+}
+
+// FlagRefundByTrx menandai pembayaran trx yang sudah diterima agar dananya dikembalikan
+func (r *paymentImpl) FlagRefundByTrx(ctx context.Context, tx *gorm.DB, trxID int, catatan string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id_trx = ? AND status = ?", trxID, entity.PaymentStatusPaid).
+		Updates(map[string]interface{}{
+			"perlu_refund":   true,
+			"catatan_refund": catatan,
+		}).Error
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PengirimanRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, pengiriman *entity.Pengiriman) error
+	ListByTrx(ctx context.Context, trxID int) ([]entity.Pengiriman, error)
+	ListEvents(ctx context.Context, pengirimanIDs []int) ([]entity.PengirimanEvent, error)
+	ListDue(ctx context.Context, before time.Time, limit int) ([]entity.Pengiriman, error)
+	AddEvents(ctx context.Context, tx *gorm.DB, events []entity.PengirimanEvent) error
+	MarkTracked(ctx context.Context, pengirimanID int, at time.Time) error
+	MarkDelivered(ctx context.Context, tx *gorm.DB, pengirimanID int, at time.Time) error
+}
+
+type pengirimanImpl struct {
+	db *gorm.DB
+}
+
+func NewPengirimanRepo(db *gorm.DB) PengirimanRepository {
+	return &pengirimanImpl{
+		db: db,
+	}
+}
+
+func (r *pengirimanImpl) Create(ctx context.Context, tx *gorm.DB, pengiriman *entity.Pengiriman) error {
+	return tx.WithContext(ctx).Create(pengiriman).Error
+}
+
+func (r *pengirimanImpl) ListByTrx(ctx context.Context, trxID int) ([]entity.Pengiriman, error) {
+	var pengirimans []entity.Pengiriman
+
+	err := r.db.WithContext(ctx).
+		Where("id_trx = ?", trxID).
+		Order("id ASC").
+		Find(&pengirimans).Error
+
+	return pengirimans, err
+}
+
+// ListEvents event pelacakan beberapa pengiriman sekaligus, terbaru lebih dulu
+func (r *pengirimanImpl) ListEvents(ctx context.Context, pengirimanIDs []int) ([]entity.PengirimanEvent, error) {
+	var events []entity.PengirimanEvent
+	if len(pengirimanIDs) == 0 {
+		return events, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("id_pengiriman IN ?", pengirimanIDs).
+		Order("waktu DESC, id DESC").
+		Find(&events).Error
+
+	return events, err
+}
+
+// ListDue pengiriman yang masih di jalan dan belum dilacak sejak before, paket yang dibatalkan dilewati
+func (r *pengirimanImpl) ListDue(ctx context.Context, before time.Time, limit int) ([]entity.Pengiriman, error) {
+	var pengirimans []entity.Pengiriman
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN paket_toko ON paket_toko.id = pengiriman.id_paket").
+		Where("pengiriman.status = ? AND paket_toko.status <> ?", entity.PengirimanStatusInTransit, entity.TrxStatusCancelled).
+		Where("pengiriman.terakhir_dilacak IS NULL OR pengiriman.terakhir_dilacak < ?", before).
+		Order("pengiriman.terakhir_dilacak ASC, pengiriman.id ASC").
+		Limit(limit).
+		Find(&pengirimans).Error
+
+	return pengirimans, err
+}
+
+// AddEvents event yang sudah pernah dicatat (pengiriman, waktu, status sama) dilewati
+func (r *pengirimanImpl) AddEvents(ctx context.Context, tx *gorm.DB, events []entity.PengirimanEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&events).Error
+}
+
+func (r *pengirimanImpl) MarkTracked(ctx context.Context, pengirimanID int, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Pengiriman{}).
+		Where("id = ?", pengirimanID).
+		Update("terakhir_dilacak", at).Error
+}
+
+func (r *pengirimanImpl) MarkDelivered(ctx context.Context, tx *gorm.DB, pengirimanID int, at time.Time) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Pengiriman{}).
+		Where("id = ? AND status = ?", pengirimanID, entity.PengirimanStatusInTransit).
+		Updates(map[string]interface{}{
+			"status":      entity.PengirimanStatusDelivered,
+			"sampai_pada": at,
+		}).Error
+}
@@ -5,6 +5,7 @@ import (
 	"pbi/internal/pkg/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository interface {
@@ -16,6 +17,7 @@ type ProductRepository interface {
 	GetAll(ctx context.Context, query *gorm.DB) ([]entity.Produk, error)
 	Count(ctx context.Context, query *gorm.DB) (int64, error)
 	GetByID(ctx context.Context, productID int) (*entity.Produk, error)
+	GetForUpdate(ctx context.Context, tx *gorm.DB, productID int) (*entity.Produk, error)
 }
 
 
@@ -118,4 +120,20 @@ func (p *produkrepoImpl) GetByID(ctx context.Context, productID int) (*entity.Pr
 	}
 
 	return &product, nil
-}
\ No newline at end of file
+}
+
+// GetForUpdate mengunci baris produk, dipakai untuk membandingkan harga dan stok sebelum dan sesudah diubah
+func (p *produkrepoImpl) GetForUpdate(ctx context.Context, tx *gorm.DB, productID int) (*entity.Produk, error) {
+	var produk entity.Produk
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", productID).
+		First(&produk).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &produk, nil
+}
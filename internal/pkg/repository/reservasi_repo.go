@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReservasiRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, reservasi *entity.StokReservasi) error
+	ListActiveByTrx(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.StokReservasi, error)
+	SetStatus(ctx context.Context, tx *gorm.DB, ids []int, from string, to string) error
+	AdjustProduk(ctx context.Context, tx *gorm.DB, produkID int, stok int, ditahan int) error
+	ListExpiredTrxIDs(ctx context.Context, now time.Time, limit int) ([]int, error)
+}
+
+type reservasiImpl struct {
+	db *gorm.DB
+}
+
+func NewReservasiRepo(db *gorm.DB) ReservasiRepository {
+	return &reservasiImpl{
+		db: db,
+	}
+}
+
+func (r *reservasiImpl) Create(ctx context.Context, tx *gorm.DB, reservasi *entity.StokReservasi) error {
+	return tx.WithContext(ctx).Create(reservasi).Error
+}
+
+// ListActiveByTrx tanpa lock: semua perubahan reservasi sebuah trx terjadi
+// di bawah lock baris trx, diurutkan per produk supaya urutan lock produk sama
+func (r *reservasiImpl) ListActiveByTrx(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.StokReservasi, error) {
+	var res []entity.StokReservasi
+
+	err := tx.WithContext(ctx).
+		Where("id_trx = ? AND status = ?", trxID, entity.ReservasiStatusActive).
+		Order("id_produk ASC, id ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *reservasiImpl) SetStatus(ctx context.Context, tx *gorm.DB, ids []int, from string, to string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).
+		Model(&entity.StokReservasi{}).
+		Where("id IN ? AND status = ?", ids, from).
+		Update("status", to).Error
+}
+
+// AdjustProduk menambah stok dan stok_ditahan produk, nilai negatif untuk mengurangi
+func (r *reservasiImpl) AdjustProduk(ctx context.Context, tx *gorm.DB, produkID int, stok int, ditahan int) error {
+	return tx.WithContext(ctx).Exec(
+		"UPDATE produk SET stok = stok + ?, stok_ditahan = stok_ditahan + ? WHERE id = ?",
+		stok, ditahan, produkID,
+	).Error
+}
+
+// ListExpiredTrxIDs trx yang masih punya reservasi aktif melewati batas waktunya
+func (r *reservasiImpl) ListExpiredTrxIDs(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	var ids []int
+
+	err := r.db.WithContext(ctx).
+		Model(&entity.StokReservasi{}).
+		Distinct("id_trx").
+		Where("status = ? AND expires_at <= ?", entity.ReservasiStatusActive, now).
+		Order("id_trx ASC").
+		Limit(limit).
+		Pluck("id_trx", &ids).Error
+
+	return ids, err
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReturRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, retur *entity.Retur) error
+	CreateItems(ctx context.Context, tx *gorm.DB, items []entity.ReturItem) error
+	CreatePhotos(ctx context.Context, tx *gorm.DB, photos []entity.ReturFoto) error
+	GetLinesForUpdate(ctx context.Context, tx *gorm.DB, trxID int, detailIDs []int) ([]entity.ReturLine, error)
+	FindByID(ctx context.Context, returID int) (*entity.Retur, error)
+	FindForUpdate(ctx context.Context, tx *gorm.DB, returID int) (*entity.Retur, error)
+	List(ctx context.Context, filter *entity.ReturFilter) ([]entity.Retur, error)
+	ListItems(ctx context.Context, tx *gorm.DB, returIDs []int) ([]entity.ReturItemDetail, error)
+	ListPhotos(ctx context.Context, returIDs []int) ([]entity.ReturFoto, error)
+	UpdateStatus(ctx context.Context, tx *gorm.DB, returID int, from string, to string, fields map[string]interface{}) error
+	CreateRefund(ctx context.Context, tx *gorm.DB, refund *entity.Refund) error
+	ListRefunds(ctx context.Context, returIDs []int) ([]entity.Refund, error)
+}
+
+type returImpl struct {
+	db *gorm.DB
+}
+
+func NewReturRepo(db *gorm.DB) ReturRepository {
+	return &returImpl{
+		db: db,
+	}
+}
+
+func (r *returImpl) Create(ctx context.Context, tx *gorm.DB, retur *entity.Retur) error {
+	return tx.WithContext(ctx).Create(retur).Error
+}
+
+func (r *returImpl) CreateItems(ctx context.Context, tx *gorm.DB, items []entity.ReturItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&items).Error
+}
+
+func (r *returImpl) CreatePhotos(ctx context.Context, tx *gorm.DB, photos []entity.ReturFoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&photos).Error
+}
+
+// GetLinesForUpdate mengunci baris detail_trx yang diretur supaya pengajuan paralel
+// untuk baris yang sama tidak melebihi kuantitas yang dibeli
+func (r *returImpl) GetLinesForUpdate(ctx context.Context, tx *gorm.DB, trxID int, detailIDs []int) ([]entity.ReturLine, error) {
+	var lines []entity.ReturLine
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Table("detail_trx").
+		Select(`detail_trx.id AS id_detail_trx, detail_trx.id_toko, log_produk.id_produk, log_produk.nama_produk,
+			detail_trx.kuantitas, detail_trx.harga_total,
+			COALESCE((
+				SELECT SUM(retur_item.kuantitas)
+				FROM retur_item
+				JOIN retur ON retur.id = retur_item.id_retur
+				WHERE retur_item.id_detail_trx = detail_trx.id AND retur.status <> ?
+			), 0) AS diretur`, entity.ReturStatusRejected).
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Where("detail_trx.id_trx = ? AND detail_trx.id IN ?", trxID, detailIDs).
+		Find(&lines).Error
+
+	return lines, err
+}
+
+func (r *returImpl) FindByID(ctx context.Context, returID int) (*entity.Retur, error) {
+	var retur entity.Retur
+
+	if err := r.db.WithContext(ctx).First(&retur, returID).Error; err != nil {
+		return nil, err
+	}
+
+	return &retur, nil
+}
+
+func (r *returImpl) FindForUpdate(ctx context.Context, tx *gorm.DB, returID int) (*entity.Retur, error) {
+	var retur entity.Retur
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&retur, returID).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &retur, nil
+}
+
+func (r *returImpl) List(ctx context.Context, filter *entity.ReturFilter) ([]entity.Retur, error) {
+	query := r.db.WithContext(ctx).
+		Table("retur").
+		Select("retur.*")
+
+	if filter.IDUser > 0 {
+		query = query.Where("retur.id_user = ?", filter.IDUser)
+	}
+	if filter.IDSeller > 0 {
+		query = query.
+			Joins("JOIN toko ON toko.id = retur.id_toko").
+			Where("toko.id_user = ?", filter.IDSeller)
+	}
+	if filter.Status != "" {
+		query = query.Where("retur.status = ?", filter.Status)
+	}
+
+	var returs []entity.Retur
+	err := query.
+		Order("retur.created_at DESC, retur.id DESC").
+		Find(&returs).Error
+
+	return returs, err
+}
+
+func (r *returImpl) ListItems(ctx context.Context, tx *gorm.DB, returIDs []int) ([]entity.ReturItemDetail, error) {
+	var items []entity.ReturItemDetail
+	if len(returIDs) == 0 {
+		return items, nil
+	}
+
+	err := tx.WithContext(ctx).
+		Table("retur_item").
+		Select("retur_item.*, log_produk.id_produk, log_produk.nama_produk").
+		Joins("JOIN detail_trx ON detail_trx.id = retur_item.id_detail_trx").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Where("retur_item.id_retur IN ?", returIDs).
+		Order("retur_item.id ASC").
+		Find(&items).Error
+
+	return items, err
+}
+
+func (r *returImpl) ListPhotos(ctx context.Context, returIDs []int) ([]entity.ReturFoto, error) {
+	var photos []entity.ReturFoto
+	if len(returIDs) == 0 {
+		return photos, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("id_retur IN ?", returIDs).
+		Order("id ASC").
+		Find(&photos).Error
+
+	return photos, err
+}
+
+func (r *returImpl) UpdateStatus(ctx context.Context, tx *gorm.DB, returID int, from string, to string, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+
+	res := tx.WithContext(ctx).
+		Model(&entity.Retur{}).
+		Where("id = ? AND status = ?", returID, from).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *returImpl) CreateRefund(ctx context.Context, tx *gorm.DB, refund *entity.Refund) error {
+	return tx.WithContext(ctx).Create(refund).Error
+}
+
+func (r *returImpl) ListRefunds(ctx context.Context, returIDs []int) ([]entity.Refund, error) {
+	var refunds []entity.Refund
+	if len(returIDs) == 0 {
+		return refunds, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("id_retur IN ?", returIDs).
+		Find(&refunds).Error
+
+	return refunds, err
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SaldoRepository interface {
+	LockSaldo(ctx context.Context, tx *gorm.DB, tokoIDs []int) (map[int]money.Rupiah, error)
+	SetSaldo(ctx context.Context, tx *gorm.DB, tokoID int, saldo money.Rupiah) error
+	CreateMutasi(ctx context.Context, tx *gorm.DB, mutasi *entity.MutasiSaldo) error
+	UncreditedLines(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.SaldoLine, error)
+	UnpostedRefunds(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.Refund, error)
+	ListSaldo(ctx context.Context, userID int) ([]entity.SaldoTokoOwner, error)
+	ListMutasi(ctx context.Context, filter *entity.MutasiFilter) ([]entity.MutasiSaldo, int64, error)
+	Rekonsiliasi(ctx context.Context, tokoID int) (*entity.RekonsiliasiSaldo, error)
+
+	CreateRekening(ctx context.Context, rekening *entity.RekeningToko) error
+	ListRekening(ctx context.Context, userID int, tokoID int) ([]entity.RekeningToko, error)
+	FindRekening(ctx context.Context, tx *gorm.DB, rekeningID int) (*entity.RekeningToko, error)
+
+	CreatePenarikan(ctx context.Context, tx *gorm.DB, penarikan *entity.Penarikan) error
+	FindPenarikanForUpdate(ctx context.Context, tx *gorm.DB, penarikanID int) (*entity.Penarikan, error)
+	UpdatePenarikan(ctx context.Context, tx *gorm.DB, penarikanID int, from string, to string, fields map[string]interface{}) error
+	ListPenarikan(ctx context.Context, filter *entity.PenarikanFilter) ([]entity.PenarikanDetail, int64, error)
+}
+
+type saldoImpl struct {
+	db *gorm.DB
+}
+
+func NewSaldoRepo(db *gorm.DB) SaldoRepository {
+	return &saldoImpl{
+		db: db,
+	}
+}
+
+// LockSaldo membuat baris saldo_toko yang belum ada lalu mengunci semuanya berurutan id_toko
+func (r *saldoImpl) LockSaldo(ctx context.Context, tx *gorm.DB, tokoIDs []int) (map[int]money.Rupiah, error) {
+	res := make(map[int]money.Rupiah, len(tokoIDs))
+	if len(tokoIDs) == 0 {
+		return res, nil
+	}
+
+	rows := make([]entity.SaldoToko, 0, len(tokoIDs))
+	for _, id := range tokoIDs {
+		rows = append(rows, entity.SaldoToko{IDToko: id})
+	}
+	if err := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var saldos []entity.SaldoToko
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_toko IN ?", tokoIDs).
+		Order("id_toko ASC").
+		Find(&saldos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range saldos {
+		res[s.IDToko] = s.Saldo
+	}
+	return res, nil
+}
+
+func (r *saldoImpl) SetSaldo(ctx context.Context, tx *gorm.DB, tokoID int, saldo money.Rupiah) error {
+	return tx.WithContext(ctx).
+		Model(&entity.SaldoToko{}).
+		Where("id_toko = ?", tokoID).
+		Update("saldo", saldo).Error
+}
+
+func (r *saldoImpl) CreateMutasi(ctx context.Context, tx *gorm.DB, mutasi *entity.MutasiSaldo) error {
+	return tx.WithContext(ctx).Create(mutasi).Error
+}
+
+// UncreditedLines baris detail_trx dari paket yang tidak dibatalkan dan belum punya mutasi penjualan
+func (r *saldoImpl) UncreditedLines(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.SaldoLine, error) {
+	var lines []entity.SaldoLine
+
+	err := tx.WithContext(ctx).
+		Table("detail_trx").
+		Select("detail_trx.id_trx, detail_trx.id AS id_detail_trx, detail_trx.id_toko, detail_trx.harga_total").
+		Joins("LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko").
+		Where("detail_trx.id_trx = ?", trxID).
+		Where("paket_toko.id IS NULL OR paket_toko.status <> ?", entity.TrxStatusCancelled).
+		Where("NOT EXISTS (SELECT 1 FROM mutasi_saldo_toko WHERE mutasi_saldo_toko.id_detail_trx = detail_trx.id AND mutasi_saldo_toko.jenis = ?)", entity.MutasiJenisPenjualan).
+		Order("detail_trx.id_toko ASC, detail_trx.id ASC").
+		Find(&lines).Error
+
+	return lines, err
+}
+
+// UnpostedRefunds refund retur trx yang belum dicatat, hanya untuk toko yang penjualannya sudah dikreditkan.
+// Refund paket yang ditolak tidak memotong saldo karena paketnya tidak pernah dikreditkan.
+func (r *saldoImpl) UnpostedRefunds(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.Refund, error) {
+	var refunds []entity.Refund
+
+	err := tx.WithContext(ctx).
+		Where("refund.id_trx = ? AND refund.id_retur IS NOT NULL", trxID).
+		Where("NOT EXISTS (SELECT 1 FROM mutasi_saldo_toko WHERE mutasi_saldo_toko.id_refund = refund.id)").
+		Where("EXISTS (SELECT 1 FROM mutasi_saldo_toko WHERE mutasi_saldo_toko.id_trx = refund.id_trx AND mutasi_saldo_toko.id_toko = refund.id_toko AND mutasi_saldo_toko.jenis = ?)", entity.MutasiJenisPenjualan).
+		Order("refund.id ASC").
+		Find(&refunds).Error
+
+	return refunds, err
+}
+
+// ListSaldo semua toko milik user, toko tanpa mutasi bersaldo nol
+func (r *saldoImpl) ListSaldo(ctx context.Context, userID int) ([]entity.SaldoTokoOwner, error) {
+	var res []entity.SaldoTokoOwner
+
+	err := r.db.WithContext(ctx).
+		Table("toko").
+		Select(`toko.id AS id_toko, toko.nama_toko, COALESCE(saldo_toko.saldo, 0) AS saldo,
+			(SELECT COALESCE(SUM(jumlah), 0) FROM penarikan_saldo WHERE penarikan_saldo.id_toko = toko.id AND penarikan_saldo.status IN ?) AS penarikan_proses`,
+			[]string{entity.PenarikanStatusRequested, entity.PenarikanStatusApproved}).
+		Joins("LEFT JOIN saldo_toko ON saldo_toko.id_toko = toko.id").
+		Where("toko.id_user = ?", userID).
+		Order("toko.id ASC").
+		Find(&res).Error
+
+	return res, err
+}
+
+func (r *saldoImpl) ListMutasi(ctx context.Context, filter *entity.MutasiFilter) ([]entity.MutasiSaldo, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entity.MutasiSaldo{}).
+		Where("id_toko = ?", filter.IDToko)
+
+	if filter.Jenis != "" {
+		query = query.Where("jenis = ?", filter.Jenis)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var res []entity.MutasiSaldo
+	err := query.
+		Order("id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&res).Error
+
+	return res, total, err
+}
+
+// Rekonsiliasi menghitung ulang buku saldo toko. Baris tidak sesuai adalah mutasi yang saldonya
+// bukan saldo baris sebelumnya ditambah jumlahnya. Penjualan dibandingkan dengan detail_trx
+// dari trx completed dan refund dengan tabel refund untuk toko yang sama.
+func (r *saldoImpl) Rekonsiliasi(ctx context.Context, tokoID int) (*entity.RekonsiliasiSaldo, error) {
+	var res entity.RekonsiliasiSaldo
+
+	running := r.db.WithContext(ctx).
+		Table("mutasi_saldo_toko").
+		Select("saldo, jumlah, LAG(saldo, 1, 0) OVER (ORDER BY id) AS sebelumnya").
+		Where("id_toko = ?", tokoID)
+
+	err := r.db.WithContext(ctx).Raw(`SELECT
+	COALESCE((SELECT saldo FROM saldo_toko WHERE id_toko = ?), 0) AS saldo_toko,
+	COALESCE((SELECT saldo FROM mutasi_saldo_toko WHERE id_toko = ? ORDER BY id DESC LIMIT 1), 0) AS saldo_terakhir,
+	(SELECT COALESCE(SUM(jumlah), 0) FROM mutasi_saldo_toko WHERE id_toko = ?) AS total_mutasi,
+	(SELECT COUNT(*) FROM (?) AS berjalan WHERE berjalan.saldo <> berjalan.sebelumnya + berjalan.jumlah) AS baris_tidak_sesuai,
+	(SELECT COALESCE(SUM(jumlah), 0) FROM mutasi_saldo_toko WHERE id_toko = ? AND jenis = ?) AS penjualan_buku,
+	(SELECT COALESCE(SUM(detail_trx.harga_total), 0) FROM detail_trx
+		JOIN trx ON trx.id = detail_trx.id_trx
+		LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko
+		WHERE detail_trx.id_toko = ? AND trx.status = ? AND (paket_toko.id IS NULL OR paket_toko.status <> ?)) AS penjualan_detail_trx,
+	(SELECT COALESCE(-SUM(jumlah), 0) FROM mutasi_saldo_toko WHERE id_toko = ? AND jenis = ?) AS refund_buku,
+	(SELECT COALESCE(SUM(refund.jumlah), 0) FROM refund
+		JOIN trx ON trx.id = refund.id_trx
+		WHERE refund.id_toko = ? AND refund.id_retur IS NOT NULL AND trx.status = ?) AS refund_sumber`,
+		tokoID, tokoID, tokoID, running,
+		tokoID, entity.MutasiJenisPenjualan,
+		tokoID, entity.TrxStatusCompleted, entity.TrxStatusCancelled,
+		tokoID, entity.MutasiJenisRefund,
+		tokoID, entity.TrxStatusCompleted,
+	).Scan(&res).Error
+
+	return &res, err
+}
+
+func (r *saldoImpl) CreateRekening(ctx context.Context, rekening *entity.RekeningToko) error {
+	return r.db.WithContext(ctx).Create(rekening).Error
+}
+
+// ListRekening rekening toko milik user, tokoID 0 berarti semua toko user
+func (r *saldoImpl) ListRekening(ctx context.Context, userID int, tokoID int) ([]entity.RekeningToko, error) {
+	query := r.db.WithContext(ctx).
+		Where("id_toko IN (SELECT id FROM toko WHERE id_user = ?)", userID)
+
+	if tokoID > 0 {
+		query = query.Where("id_toko = ?", tokoID)
+	}
+
+	var res []entity.RekeningToko
+	err := query.Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+func (r *saldoImpl) FindRekening(ctx context.Context, tx *gorm.DB, rekeningID int) (*entity.RekeningToko, error) {
+	var rekening entity.RekeningToko
+
+	if err := tx.WithContext(ctx).First(&rekening, rekeningID).Error; err != nil {
+		return nil, err
+	}
+
+	return &rekening, nil
+}
+
+func (r *saldoImpl) CreatePenarikan(ctx context.Context, tx *gorm.DB, penarikan *entity.Penarikan) error {
+	return tx.WithContext(ctx).Create(penarikan).Error
+}
+
+func (r *saldoImpl) FindPenarikanForUpdate(ctx context.Context, tx *gorm.DB, penarikanID int) (*entity.Penarikan, error) {
+	var penarikan entity.Penarikan
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&penarikan, penarikanID).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &penarikan, nil
+}
+
+func (r *saldoImpl) UpdatePenarikan(ctx context.Context, tx *gorm.DB, penarikanID int, from string, to string, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+
+	res := tx.WithContext(ctx).
+		Model(&entity.Penarikan{}).
+		Where("id = ? AND status = ?", penarikanID, from).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *saldoImpl) ListPenarikan(ctx context.Context, filter *entity.PenarikanFilter) ([]entity.PenarikanDetail, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("penarikan_saldo").
+		Joins("JOIN toko ON toko.id = penarikan_saldo.id_toko").
+		Joins("JOIN rekening_toko ON rekening_toko.id = penarikan_saldo.id_rekening")
+
+	if filter.IDUser > 0 {
+		query = query.Where("toko.id_user = ?", filter.IDUser)
+	}
+	if filter.IDToko > 0 {
+		query = query.Where("penarikan_saldo.id_toko = ?", filter.IDToko)
+	}
+	if filter.Status != "" {
+		query = query.Where("penarikan_saldo.status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var res []entity.PenarikanDetail
+	err := query.
+		Select("penarikan_saldo.*, toko.nama_toko, rekening_toko.nama_bank, rekening_toko.nomor_rekening, rekening_toko.atas_nama").
+		Order("penarikan_saldo.created_at DESC, penarikan_saldo.id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&res).Error
+
+	return res, total, err
+}
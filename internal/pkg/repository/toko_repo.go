@@ -55,7 +55,7 @@ func (r *tokoRepositoryImpl) Create(
 
 func (r *tokoRepositoryImpl) GetAll(ctx context.Context) ([]*entity.Toko, error) {
 	query := `
-		SELECT id, id_user, nama_toko, url_foto
+		SELECT id, id_user, nama_toko, url_foto, COALESCE(id_kota, ''), rating_rata, jumlah_ulasan
 		FROM toko
 	`
 
@@ -73,6 +73,9 @@ func (r *tokoRepositoryImpl) GetAll(ctx context.Context) ([]*entity.Toko, error)
 			&t.IDUser,
 			&t.NamaToko,
 			&t.UrlFoto,
+			&t.IDKota,
+			&t.RatingRata,
+			&t.JumlahUlasan,
 		); err != nil {
 			return nil, err
 		}
@@ -92,7 +95,7 @@ func (r *tokoRepositoryImpl) GetByUserID(
 ) ([]*entity.Toko, error) {
 
 	query := `
-		SELECT id, id_user, nama_toko, url_foto
+		SELECT id, id_user, nama_toko, url_foto, COALESCE(id_kota, ''), rating_rata, jumlah_ulasan
 		FROM toko
 		WHERE id_user = ?
 	`
@@ -111,6 +114,9 @@ func (r *tokoRepositoryImpl) GetByUserID(
 			&t.IDUser,
 			&t.NamaToko,
 			&t.UrlFoto,
+			&t.IDKota,
+			&t.RatingRata,
+			&t.JumlahUlasan,
 		); err != nil {
 			return nil, err
 		}
@@ -125,11 +131,11 @@ func (r *tokoRepositoryImpl) GetByUserID(
 }
 
 func (r *tokoRepositoryImpl) GetByID(ctx context.Context, tokoID int) (*entity.Toko, error) {
-    query := `SELECT id, id_user, nama_toko, url_foto, created_at, updated_at FROM toko WHERE id = ?`
+    query := `SELECT id, id_user, nama_toko, url_foto, COALESCE(id_kota, ''), COALESCE(prefix_invoice, ''), rating_rata, jumlah_ulasan, created_at, updated_at FROM toko WHERE id = ?`
     row := r.db.QueryRowContext(ctx, query, tokoID)
 
     var t entity.Toko
-    if err := row.Scan(&t.ID, &t.IDUser, &t.NamaToko, &t.UrlFoto, &t.CreatedAt, &t.UpdatedAt); err != nil {
+    if err := row.Scan(&t.ID, &t.IDUser, &t.NamaToko, &t.UrlFoto, &t.IDKota, &t.PrefixInvoice, &t.RatingRata, &t.JumlahUlasan, &t.CreatedAt, &t.UpdatedAt); err != nil {
         return nil, err
     }
 
@@ -140,9 +146,11 @@ func (r *tokoRepositoryImpl) GetByID(ctx context.Context, tokoID int) (*entity.T
 func (r *tokoRepositoryImpl) Update(ctx context.Context, tokoID int, data *entity.Toko) (*entity.Toko, error) {
 	query := `UPDATE toko 
           SET nama_toko = COALESCE(?, nama_toko), 
-              url_foto = COALESCE(?, url_foto) 
+              url_foto = COALESCE(?, url_foto), 
+              id_kota = NULLIF(?, ''), 
+              prefix_invoice = NULLIF(?, '') 
           WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, data.NamaToko, data.UrlFoto, tokoID)
+	_, err := r.db.ExecContext(ctx, query, data.NamaToko, data.UrlFoto, data.IDKota, data.PrefixInvoice, tokoID)
 	if err != nil {
 		return nil, err
 	}
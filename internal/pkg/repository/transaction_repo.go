@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"pbi/internal/pkg/entity"
+	"pbi/internal/utils/money"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -12,13 +15,28 @@ type TransactionRepository interface {
 	CreateTransaction(ctx context.Context, tx *gorm.DB, trx *entity.Transaction) error
 	CreateLogProduk(ctx context.Context, tx *gorm.DB, log *entity.LogProduk) error
 	CreateDetailTransaction(ctx context.Context, tx *gorm.DB, detail *entity.DetailTransaction) error
-	UpdateTotalHarga(ctx context.Context,tx *gorm.DB,trxID int,total float64,) error
-	UpdateStokProduk(ctx context.Context, tx *gorm.DB, produkID int, qty int) error
-	GetProdukByID(ctx context.Context, tx *gorm.DB, productID int) (*entity.ProdukWithOwner, error)
-	GetTransactionsByUser(ctx context.Context, userID int) ([]entity.Transaction, error)
+	UpdateTotalHarga(ctx context.Context,tx *gorm.DB,trxID int,total money.Rupiah,) error
+	GetProdukForUpdate(ctx context.Context, tx *gorm.DB, produkIDs []int) ([]entity.ProdukWithOwner, error)
+	ListTransactions(ctx context.Context, filter *entity.TrxFilter) ([]entity.Transaction, int64, error)
+	StreamTransactionLines(ctx context.Context, filter *entity.TrxFilter, fn func(*entity.TrxExportLine) error) error
 	GetTransactionDetails(ctx context.Context, trxID int) ([]entity.DetailTrxWithJoin, error)
-	GetProductPhotosByLogProdukID(ctx context.Context, logProdukID int) ([]entity.ProductPhoto, error)
+	GetTransactionDetailsByTrxIDs(ctx context.Context, trxIDs []int) ([]entity.DetailTrxWithJoin, error)
+	GetProductPhotosByLogProdukIDs(ctx context.Context, logProdukIDs []int) ([]entity.LogProdukPhoto, error)
 	GetTransactionByID(ctx context.Context, trxID int, userID int) (*entity.Transaction, error)
+	FindTransactionByID(ctx context.Context, trxID int) (*entity.Transaction, error)
+	GetTransactionForUpdate(ctx context.Context, tx *gorm.DB, trxID int) (*entity.Transaction, error)
+	UpdateStatus(ctx context.Context, tx *gorm.DB, trxID int, from string, to string) error
+	CreateStatusHistory(ctx context.Context, tx *gorm.DB, history *entity.TrxStatusHistory) error
+	GetStatusHistory(ctx context.Context, trxID int) ([]entity.TrxStatusHistory, error)
+	IsSellerOfTransaction(ctx context.Context, tx *gorm.DB, trxID int, userID int) (bool, error)
+	GetSellerTokoIDs(ctx context.Context, trxID int, userID int) ([]int, error)
+	GetStockItemsByTrx(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.TrxStockItem, error)
+	GetStockItemsByToko(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) ([]entity.TrxStockItem, error)
+	RestoreStokProduk(ctx context.Context, tx *gorm.DB, produkID int, qty int) error
+	MarkCancelled(ctx context.Context, tx *gorm.DB, trxID int, userID int, alasan string) error
+	SetVoucher(ctx context.Context, tx *gorm.DB, trxID int, voucherID int, diskon money.Rupiah) error
+	SetOngkosKirim(ctx context.Context, tx *gorm.DB, trxID int, ongkir money.Rupiah) error
+	SetKodeInvoice(ctx context.Context, tx *gorm.DB, trxID int, kode string) error
 }
 
 type transactionImpl struct {
@@ -31,7 +49,11 @@ func NewTransactionRepo(db *gorm.DB) TransactionRepository {
 	}
 }
 
+// CreateTransaction tanpa kode_invoice dibiarkan NULL, nomor diisi belakangan lewat SetKodeInvoice
 func (t *transactionImpl) CreateTransaction(ctx context.Context, tx *gorm.DB, trx *entity.Transaction) error{
+	if trx.KodeInvoice == "" {
+		return tx.WithContext(ctx).Omit("kode_invoice").Create(trx).Error
+	}
 	return tx.WithContext(ctx).Create(trx).Error
 }
 
@@ -57,101 +79,249 @@ func (r *transactionImpl) CreateDetailTransaction(ctx context.Context,tx *gorm.D
 	return tx.WithContext(ctx).Create(detail).Error
 }
 
-func (r *transactionImpl) UpdateTotalHarga(ctx context.Context,tx *gorm.DB,trxID int,total float64,) error {
+func (r *transactionImpl) UpdateTotalHarga(ctx context.Context,tx *gorm.DB,trxID int,total money.Rupiah,) error {
 	return tx.WithContext(ctx).
 		Model(&entity.Transaction{}).
 		Where("id = ?", trxID).
 		Update("harga_total", total).Error
 }
 
-func (r *transactionImpl) GetProdukByID(ctx context.Context,tx *gorm.DB,produkID int,) (*entity.ProdukWithOwner, error) {
+// tokoOwner data toko yang ikut disalin ke ProdukWithOwner
+type tokoOwner struct {
+	ID            int    `gorm:"column:id"`
+	IDUser        int    `gorm:"column:id_user"`
+	IDKota        string `gorm:"column:id_kota"`
+	NamaToko      string `gorm:"column:nama_toko"`
+	PrefixInvoice string `gorm:"column:prefix_invoice"`
+}
 
-	var res entity.ProdukWithOwner
+// GetProdukForUpdate mengunci semua produk checkout dalam satu query berurutan id, jadi
+// checkout paralel dengan produk yang sama selalu mengambil lock dengan urutan yang sama.
+// Toko hanya dibaca tanpa lock supaya tidak ikut menahan baris toko.
+func (r *transactionImpl) GetProdukForUpdate(ctx context.Context, tx *gorm.DB, produkIDs []int) ([]entity.ProdukWithOwner, error) {
+	var produks []entity.Produk
 
 	err := tx.WithContext(ctx).
 		Clauses(clause.Locking{Strength: "UPDATE"}).
-		Table("produk").
-		Select("produk.*, toko.id_user").
-		Joins("JOIN toko ON toko.id = produk.id_toko").
-		Where("produk.id = ?", produkID).
-		First(&res).Error
+		Where("id IN ?", produkIDs).
+		Order("id ASC").
+		Find(&produks).Error
+	if err != nil || len(produks) == 0 {
+		return nil, err
+	}
+
+	tokoIDs := make([]int, 0, len(produks))
+	for _, p := range produks {
+		tokoIDs = append(tokoIDs, p.IDToko)
+	}
 
+	var tokos []tokoOwner
+	err = tx.WithContext(ctx).
+		Table("toko").
+		Select("id, id_user, COALESCE(id_kota, '') AS id_kota, nama_toko, COALESCE(prefix_invoice, '') AS prefix_invoice").
+		Where("id IN ?", tokoIDs).
+		Scan(&tokos).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return &res, nil
+	byToko := make(map[int]tokoOwner, len(tokos))
+	for _, t := range tokos {
+		byToko[t.ID] = t
+	}
+
+	res := make([]entity.ProdukWithOwner, 0, len(produks))
+	for _, p := range produks {
+		owner := byToko[p.IDToko]
+		res = append(res, entity.ProdukWithOwner{
+			Produk:            p,
+			IDUser:            owner.IDUser,
+			IDKotaToko:        owner.IDKota,
+			NamaToko:          owner.NamaToko,
+			PrefixInvoiceToko: owner.PrefixInvoice,
+		})
+	}
+
+	return res, nil
 }
 
-func (r *transactionImpl) UpdateStokProduk(ctx context.Context,tx *gorm.DB,produkID int,qty int,) error {
-	return tx.WithContext(ctx).
-		Model(&entity.Produk{}).
-		Where("id = ?", produkID).
-		Update("stok", gorm.Expr("stok - ?", qty)).
-		Error
+// trxSortColumns kolom urutan yang boleh dipakai, di luar ini ditolak usecase
+var trxSortColumns = map[string]string{
+	"tanggal": "trx.created_at",
+	"harga":   "trx.harga_total",
 }
 
-func (r *transactionImpl) GetTransactionsByUser(ctx context.Context,userID int,) ([]entity.Transaction, error) {
+// trxFilterScope menerapkan filter riwayat transaksi yang sama untuk daftar dan export
+func trxFilterScope(query *gorm.DB, filter *entity.TrxFilter) *gorm.DB {
+	if filter.IDUser > 0 {
+		query = query.Where("trx.id_user = ?", filter.IDUser)
+	}
+	if filter.IDPenjual > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM detail_trx JOIN toko ON toko.id = detail_trx.id_toko WHERE detail_trx.id_trx = trx.id AND toko.id_user = ?)", filter.IDPenjual)
+	}
+	if filter.Dari != nil {
+		query = query.Where("trx.created_at >= ?", *filter.Dari)
+	}
+	if filter.Sampai != nil {
+		query = query.Where("trx.created_at < ?", *filter.Sampai)
+	}
+	if filter.MetodeBayar != "" {
+		query = query.Where("trx.metode_bayar = ?", filter.MetodeBayar)
+	}
+	if filter.Status != "" {
+		query = query.Where("trx.status = ?", filter.Status)
+	}
+	if filter.IDToko > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM detail_trx WHERE detail_trx.id_trx = trx.id AND detail_trx.id_toko = ?)", filter.IDToko)
+	}
+	if filter.KodeInvoice != "" {
+		query = query.Where("trx.kode_invoice LIKE ? ESCAPE '!'", likeContains(filter.KodeInvoice))
+	}
+	return query
+}
 
-	var trxs []entity.Transaction
+// likeContains pola LIKE "mengandung" untuk input pengguna: % dan _ dicari apa adanya,
+// bukan sebagai wildcard. Dipasangkan dengan ESCAPE '!' supaya tidak bergantung sql_mode.
+func likeContains(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
 
-	err := r.db.WithContext(ctx).
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// trxOrder urutan trx sesuai sort dan order filter, trx.id sebagai penentu urutan yang stabil
+func trxOrder(filter *entity.TrxFilter) string {
+	column, ok := trxSortColumns[filter.Sort]
+	if !ok {
+		column = trxSortColumns["tanggal"]
+	}
+	direction := "DESC"
+	if filter.Order == "asc" {
+		direction = "ASC"
+	}
+	return column + " " + direction + ", trx.id " + direction
+}
+
+func (r *transactionImpl) ListTransactions(ctx context.Context, filter *entity.TrxFilter) ([]entity.Transaction, int64, error) {
+	query := trxFilterScope(r.db.WithContext(ctx).Model(&entity.Transaction{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var trxs []entity.Transaction
+	err := query.
 		Preload("AlamatKirim").
-		Where("id_user = ?", userID).
-		Order("id DESC").
+		Order(trxOrder(filter)).
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
 		Find(&trxs).Error
 
-	return trxs, err
+	return trxs, total, err
+}
+
+// StreamTransactionLines membaca baris export satu per satu dari cursor database dan
+// memanggil fn untuk tiap baris. Penjual hanya mendapat baris toko miliknya.
+func (r *transactionImpl) StreamTransactionLines(ctx context.Context, filter *entity.TrxFilter, fn func(*entity.TrxExportLine) error) error {
+	query := trxFilterScope(r.db.WithContext(ctx).Table("trx"), filter).
+		Select(`trx.id AS id_trx, COALESCE(trx.kode_invoice, '') AS kode_invoice, trx.created_at AS tanggal,
+			trx.status, COALESCE(trx.metode_bayar, '') AS metode_bayar, trx.id_user AS id_pembeli,
+			detail_trx.id_toko, COALESCE(toko.nama_toko, '') AS nama_toko,
+			COALESCE(paket_toko.kode_invoice, '') AS kode_invoice_paket, COALESCE(paket_toko.kurir, '') AS kurir,
+			COALESCE(paket_toko.ongkir, 0) AS ongkir_paket,
+			detail_trx.id AS id_detail, log_produk.id_produk, log_produk.nama_produk, log_produk.slug,
+			COALESCE(category.nama_category, '') AS nama_category,
+			log_produk.harga_konsumen, log_produk.harga_reseller,
+			detail_trx.kuantitas, detail_trx.diskon, detail_trx.harga_total`).
+		Joins("JOIN detail_trx ON detail_trx.id_trx = trx.id").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Joins("LEFT JOIN toko ON toko.id = detail_trx.id_toko").
+		Joins("LEFT JOIN category ON category.id = log_produk.id_category").
+		Joins("LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko")
+
+	if filter.IDPenjual > 0 {
+		query = query.Where("detail_trx.id_toko IN (SELECT id FROM toko WHERE id_user = ?)", filter.IDPenjual)
+		if filter.IDToko > 0 {
+			query = query.Where("detail_trx.id_toko = ?", filter.IDToko)
+		}
+	}
+
+	rows, err := query.Order(trxOrder(filter) + ", detail_trx.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.TrxExportLine
+		if err := r.db.ScanRows(rows, &line); err != nil {
+			return err
+		}
+		if err := fn(&line); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
 }
 
 func (r *transactionImpl) GetTransactionDetails(ctx context.Context, trxID int) ([]entity.DetailTrxWithJoin, error) {
-    var res []entity.DetailTrxWithJoin
+	return r.GetTransactionDetailsByTrxIDs(ctx, []int{trxID})
+}
 
-    err := r.db.WithContext(ctx).
-        Table("detail_trx").
-        Select(`
-            detail_trx.id_trx,
-            detail_trx.kuantitas,
-            detail_trx.harga_total,
-            log_produk.id as id_log_produk,
-            log_produk.nama_produk,
-            log_produk.slug,
-            log_produk.harga_reseller,
-            log_produk.harga_konsumen,
-            log_produk.deskripsi,
-            log_produk.id_category,
-            toko.id as id_toko,
-            toko.nama_toko,
-            toko.url_foto,
-            category.nama_category
-        `).
-        Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
-        Joins("JOIN toko ON toko.id = detail_trx.id_toko").
-        Joins("LEFT JOIN category ON category.id = log_produk.id_category"). // Tambahkan join category
-        Where("detail_trx.id_trx = ?", trxID).
-        Find(&res).Error
-
-    return res, err
-}
-
-func (r *transactionImpl) GetProductPhotosByLogProdukID(ctx context.Context, logProdukID int) ([]entity.ProductPhoto, error) {
-    var photos []entity.ProductPhoto
-    
-    // Ambil id_produk dari log_produk
-    var logProduk entity.LogProduk
-    if err := r.db.WithContext(ctx).
-        Select("id_produk").
-        Where("id = ?", logProdukID).
-        First(&logProduk).Error; err != nil {
-        return nil, err
-    }
-    
-    // Ambil photos berdasarkan id_produk
-    err := r.db.WithContext(ctx).
-        Where("id_produk = ?", logProduk.IDProduk).
-        Find(&photos).Error
-    
-    return photos, err
+// GetTransactionDetailsByTrxIDs mengambil detail banyak transaksi dalam satu query,
+// diurutkan per trx lalu per baris detail
+func (r *transactionImpl) GetTransactionDetailsByTrxIDs(ctx context.Context, trxIDs []int) ([]entity.DetailTrxWithJoin, error) {
+	var res []entity.DetailTrxWithJoin
+	if len(trxIDs) == 0 {
+		return res, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("detail_trx").
+		Select(`
+			detail_trx.id_trx,
+			detail_trx.kuantitas,
+			detail_trx.harga_total,
+			detail_trx.diskon,
+			log_produk.id as id_log_produk,
+			log_produk.nama_produk,
+			log_produk.slug,
+			log_produk.harga_reseller,
+			log_produk.harga_konsumen,
+			log_produk.deskripsi,
+			log_produk.id_category,
+			toko.id as id_toko,
+			toko.nama_toko,
+			toko.url_foto,
+			category.nama_category
+		`).
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Joins("JOIN toko ON toko.id = detail_trx.id_toko").
+		Joins("LEFT JOIN category ON category.id = log_produk.id_category").
+		Where("detail_trx.id_trx IN ?", trxIDs).
+		Order("detail_trx.id_trx, detail_trx.id").
+		Find(&res).Error
+
+	return res, err
+}
+
+// GetProductPhotosByLogProdukIDs mengambil foto produk untuk banyak log_produk sekaligus
+func (r *transactionImpl) GetProductPhotosByLogProdukIDs(ctx context.Context, logProdukIDs []int) ([]entity.LogProdukPhoto, error) {
+	var photos []entity.LogProdukPhoto
+	if len(logProdukIDs) == 0 {
+		return photos, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("foto_produk").
+		Select("foto_produk.*, log_produk.id AS id_log_produk").
+		Joins("JOIN log_produk ON log_produk.id_produk = foto_produk.id_produk").
+		Where("log_produk.id IN ?", logProdukIDs).
+		Order("foto_produk.id").
+		Find(&photos).Error
+
+	return photos, err
 }
 
 func (r *transactionImpl) GetTransactionByID(ctx context.Context, trxID int, userID int) (*entity.Transaction, error) {
@@ -167,4 +337,169 @@ func (r *transactionImpl) GetTransactionByID(ctx context.Context, trxID int, use
     }
     
     return &trx, nil
-}
\ No newline at end of file
+}
+
+func (r *transactionImpl) FindTransactionByID(ctx context.Context, trxID int) (*entity.Transaction, error) {
+	var trx entity.Transaction
+
+	err := r.db.WithContext(ctx).
+		Preload("AlamatKirim").
+		Where("id = ?", trxID).
+		First(&trx).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &trx, nil
+}
+
+func (r *transactionImpl) GetTransactionForUpdate(ctx context.Context, tx *gorm.DB, trxID int) (*entity.Transaction, error) {
+	var trx entity.Transaction
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", trxID).
+		First(&trx).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &trx, nil
+}
+
+func (r *transactionImpl) UpdateStatus(ctx context.Context, tx *gorm.DB, trxID int, from string, to string) error {
+	res := tx.WithContext(ctx).
+		Model(&entity.Transaction{}).
+		Where("id = ? AND status = ?", trxID, from).
+		Update("status", to)
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *transactionImpl) CreateStatusHistory(ctx context.Context, tx *gorm.DB, history *entity.TrxStatusHistory) error {
+	return tx.WithContext(ctx).Create(history).Error
+}
+
+func (r *transactionImpl) GetStatusHistory(ctx context.Context, trxID int) ([]entity.TrxStatusHistory, error) {
+	var histories []entity.TrxStatusHistory
+
+	err := r.db.WithContext(ctx).
+		Where("id_trx = ?", trxID).
+		Order("id ASC").
+		Find(&histories).Error
+
+	return histories, err
+}
+
+// IsSellerOfTransaction cek apakah user pemilik salah satu toko di detail_trx
+func (r *transactionImpl) IsSellerOfTransaction(ctx context.Context, tx *gorm.DB, trxID int, userID int) (bool, error) {
+	var count int64
+
+	err := tx.WithContext(ctx).
+		Table("detail_trx").
+		Joins("JOIN toko ON toko.id = detail_trx.id_toko").
+		Where("detail_trx.id_trx = ? AND toko.id_user = ?", trxID, userID).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// GetSellerTokoIDs toko milik user yang ikut dalam transaksi
+func (r *transactionImpl) GetSellerTokoIDs(ctx context.Context, trxID int, userID int) ([]int, error) {
+	var ids []int
+
+	err := r.db.WithContext(ctx).
+		Table("detail_trx").
+		Distinct().
+		Joins("JOIN toko ON toko.id = detail_trx.id_toko").
+		Where("detail_trx.id_trx = ? AND toko.id_user = ?", trxID, userID).
+		Pluck("detail_trx.id_toko", &ids).Error
+
+	return ids, err
+}
+
+// GetStockItemsByTrx mengembalikan kuantitas per produk, diurutkan berdasarkan id_produk
+// supaya urutan lock baris produk selalu sama. Baris dari paket yang sudah dibatalkan
+// dilewati karena stoknya sudah dikembalikan saat paket ditolak.
+func (r *transactionImpl) GetStockItemsByTrx(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.TrxStockItem, error) {
+	var items []entity.TrxStockItem
+
+	err := stockItemsQuery(ctx, tx, trxID).
+		Joins("LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko").
+		Where("(paket_toko.id IS NULL OR paket_toko.status <> ?)", entity.TrxStatusCancelled).
+		Find(&items).Error
+
+	return items, err
+}
+
+// GetStockItemsByToko sama seperti GetStockItemsByTrx untuk satu paket toko
+func (r *transactionImpl) GetStockItemsByToko(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) ([]entity.TrxStockItem, error) {
+	var items []entity.TrxStockItem
+
+	err := stockItemsQuery(ctx, tx, trxID).
+		Where("detail_trx.id_toko = ?", tokoID).
+		Find(&items).Error
+
+	return items, err
+}
+
+func stockItemsQuery(ctx context.Context, tx *gorm.DB, trxID int) *gorm.DB {
+	return tx.WithContext(ctx).
+		Table("detail_trx").
+		Select("log_produk.id_produk, SUM(detail_trx.kuantitas) AS kuantitas").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Where("detail_trx.id_trx = ?", trxID).
+		Group("log_produk.id_produk").
+		Order("log_produk.id_produk ASC")
+}
+
+func (r *transactionImpl) RestoreStokProduk(ctx context.Context, tx *gorm.DB, produkID int, qty int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Produk{}).
+		Where("id = ?", produkID).
+		Update("stok", gorm.Expr("stok + ?", qty)).
+		Error
+}
+
+func (r *transactionImpl) MarkCancelled(ctx context.Context, tx *gorm.DB, trxID int, userID int, alasan string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Transaction{}).
+		Where("id = ?", trxID).
+		Updates(map[string]interface{}{
+			"dibatalkan_oleh":   userID,
+			"alasan_pembatalan": alasan,
+			"dibatalkan_pada":   time.Now(),
+		}).Error
+}
+
+func (r *transactionImpl) SetVoucher(ctx context.Context, tx *gorm.DB, trxID int, voucherID int, diskon money.Rupiah) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Transaction{}).
+		Where("id = ?", trxID).
+		Updates(map[string]interface{}{
+			"id_voucher": voucherID,
+			"diskon":     diskon,
+		}).Error
+}
+
+func (r *transactionImpl) SetOngkosKirim(ctx context.Context, tx *gorm.DB, trxID int, ongkir money.Rupiah) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Transaction{}).
+		Where("id = ?", trxID).
+		Update("ongkos_kirim", ongkir).Error
+}
+
+func (r *transactionImpl) SetKodeInvoice(ctx context.Context, tx *gorm.DB, trxID int, kode string) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Transaction{}).
+		Where("id = ?", trxID).
+		Update("kode_invoice", kode).Error
+}
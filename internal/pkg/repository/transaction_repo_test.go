@@ -0,0 +1,22 @@
+package repository
+
+import "testing"
+
+func TestLikeContains(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"INV/20261018", "%INV/20261018%"},
+		{"100%", "%100!%%"},
+		{"PBI_01", "%PBI!_01%"},
+		{"a!b", "%a!!b%"},
+		{"!%_", "%!!!%!_%"},
+	}
+
+	for _, tt := range tests {
+		if got := likeContains(tt.in); got != tt.want {
+			t.Errorf("likeContains(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UlasanRepository interface {
+	FindLine(ctx context.Context, detailTrxID int) (*entity.UlasanLine, error)
+	Create(ctx context.Context, tx *gorm.DB, ulasan *entity.Ulasan) error
+	CreatePhotos(ctx context.Context, tx *gorm.DB, photos []entity.UlasanFoto) error
+	RefreshRating(ctx context.Context, tx *gorm.DB, produkID int, tokoID int) error
+	FindForUpdate(ctx context.Context, tx *gorm.DB, ulasanID int) (*entity.Ulasan, error)
+	Reply(ctx context.Context, tx *gorm.DB, ulasanID int, balasan string) error
+	List(ctx context.Context, filter *entity.UlasanFilter) ([]entity.UlasanDetail, int64, error)
+	ListPhotos(ctx context.Context, ulasanIDs []int) ([]entity.UlasanFoto, error)
+}
+
+type ulasanImpl struct {
+	db *gorm.DB
+}
+
+func NewUlasanRepo(db *gorm.DB) UlasanRepository {
+	return &ulasanImpl{
+		db: db,
+	}
+}
+
+// FindLine baris detail_trx beserta produk asal (lewat log_produk), pembeli dan status paket tokonya
+func (r *ulasanImpl) FindLine(ctx context.Context, detailTrxID int) (*entity.UlasanLine, error) {
+	var line entity.UlasanLine
+
+	err := r.db.WithContext(ctx).
+		Table("detail_trx").
+		Select("detail_trx.id AS id_detail_trx, detail_trx.id_trx, log_produk.id_produk, detail_trx.id_toko, trx.id_user, trx.status AS status_trx, COALESCE(paket_toko.status, '') AS status_paket").
+		Joins("JOIN trx ON trx.id = detail_trx.id_trx").
+		Joins("JOIN log_produk ON log_produk.id = detail_trx.id_log_produk").
+		Joins("LEFT JOIN paket_toko ON paket_toko.id_trx = detail_trx.id_trx AND paket_toko.id_toko = detail_trx.id_toko").
+		Where("detail_trx.id = ?", detailTrxID).
+		Take(&line).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &line, nil
+}
+
+func (r *ulasanImpl) Create(ctx context.Context, tx *gorm.DB, ulasan *entity.Ulasan) error {
+	return tx.WithContext(ctx).Create(ulasan).Error
+}
+
+func (r *ulasanImpl) CreatePhotos(ctx context.Context, tx *gorm.DB, photos []entity.UlasanFoto) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&photos).Error
+}
+
+// RefreshRating menghitung ulang rating rata-rata dan jumlah ulasan produk dan toko dari tabel ulasan
+func (r *ulasanImpl) RefreshRating(ctx context.Context, tx *gorm.DB, produkID int, tokoID int) error {
+	err := tx.WithContext(ctx).Exec(`
+		UPDATE produk SET
+			rating_rata = (SELECT COALESCE(ROUND(AVG(rating), 2), 0) FROM ulasan WHERE id_produk = ?),
+			jumlah_ulasan = (SELECT COUNT(*) FROM ulasan WHERE id_produk = ?)
+		WHERE id = ?`, produkID, produkID, produkID).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.WithContext(ctx).Exec(`
+		UPDATE toko SET
+			rating_rata = (SELECT COALESCE(ROUND(AVG(rating), 2), 0) FROM ulasan WHERE id_toko = ?),
+			jumlah_ulasan = (SELECT COUNT(*) FROM ulasan WHERE id_toko = ?)
+		WHERE id = ?`, tokoID, tokoID, tokoID).Error
+}
+
+func (r *ulasanImpl) FindForUpdate(ctx context.Context, tx *gorm.DB, ulasanID int) (*entity.Ulasan, error) {
+	var ulasan entity.Ulasan
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", ulasanID).
+		Take(&ulasan).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &ulasan, nil
+}
+
+// Reply hanya mengisi balasan yang masih kosong
+func (r *ulasanImpl) Reply(ctx context.Context, tx *gorm.DB, ulasanID int, balasan string) error {
+	res := tx.WithContext(ctx).
+		Model(&entity.Ulasan{}).
+		Where("id = ? AND balasan IS NULL", ulasanID).
+		Updates(map[string]interface{}{
+			"balasan":      balasan,
+			"dibalas_pada": gorm.Expr("NOW()"),
+		})
+
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *ulasanImpl) List(ctx context.Context, filter *entity.UlasanFilter) ([]entity.UlasanDetail, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("ulasan").
+		Joins("JOIN user ON user.id = ulasan.id_user").
+		Joins("JOIN produk ON produk.id = ulasan.id_produk")
+
+	if filter.IDProduk > 0 {
+		query = query.Where("ulasan.id_produk = ?", filter.IDProduk)
+	}
+	if filter.IDToko > 0 {
+		query = query.Where("ulasan.id_toko = ?", filter.IDToko)
+	}
+	if filter.Rating > 0 {
+		query = query.Where("ulasan.rating = ?", filter.Rating)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ulasans []entity.UlasanDetail
+	err := query.
+		Select("ulasan.*, user.nama AS nama_pembeli, produk.nama_produk").
+		Order("ulasan.created_at DESC, ulasan.id DESC").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&ulasans).Error
+
+	return ulasans, total, err
+}
+
+func (r *ulasanImpl) ListPhotos(ctx context.Context, ulasanIDs []int) ([]entity.UlasanFoto, error) {
+	var photos []entity.UlasanFoto
+	if len(ulasanIDs) == 0 {
+		return photos, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("id_ulasan IN ?", ulasanIDs).
+		Order("id ASC").
+		Find(&photos).Error
+
+	return photos, err
+}
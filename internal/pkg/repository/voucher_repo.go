@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoucherRepository interface {
+	Create(ctx context.Context, voucher *entity.Voucher) error
+	FindByID(ctx context.Context, voucherID int) (*entity.Voucher, error)
+	FindByKodeForUpdate(ctx context.Context, tx *gorm.DB, kode string) (*entity.Voucher, error)
+	ListByCreator(ctx context.Context, userID int) ([]entity.Voucher, error)
+	ListAll(ctx context.Context) ([]entity.Voucher, error)
+	Deactivate(ctx context.Context, voucherID int) error
+	CountUsageByUser(ctx context.Context, tx *gorm.DB, voucherID int, userID int) (int64, error)
+	IncrementTerpakai(ctx context.Context, tx *gorm.DB, voucherID int) error
+	DecrementTerpakai(ctx context.Context, tx *gorm.DB, voucherID int) error
+	CreateUsage(ctx context.Context, tx *gorm.DB, usage *entity.VoucherUsage) error
+	DeleteUsageByTrx(ctx context.Context, tx *gorm.DB, trxID int) (int64, error)
+}
+
+type voucherImpl struct {
+	db *gorm.DB
+}
+
+func NewVoucherRepo(db *gorm.DB) VoucherRepository {
+	return &voucherImpl{
+		db: db,
+	}
+}
+
+func (r *voucherImpl) Create(ctx context.Context, voucher *entity.Voucher) error {
+	return r.db.WithContext(ctx).Create(voucher).Error
+}
+
+func (r *voucherImpl) FindByID(ctx context.Context, voucherID int) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+
+	err := r.db.WithContext(ctx).
+		Where("id = ?", voucherID).
+		First(&voucher).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+// FindByKodeForUpdate mengunci baris voucher supaya kuota tidak terpakai ganda
+func (r *voucherImpl) FindByKodeForUpdate(ctx context.Context, tx *gorm.DB, kode string) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("kode = ?", kode).
+		First(&voucher).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+func (r *voucherImpl) ListByCreator(ctx context.Context, userID int) ([]entity.Voucher, error) {
+	var vouchers []entity.Voucher
+
+	err := r.db.WithContext(ctx).
+		Where("dibuat_oleh = ?", userID).
+		Order("id DESC").
+		Find(&vouchers).Error
+
+	return vouchers, err
+}
+
+func (r *voucherImpl) ListAll(ctx context.Context) ([]entity.Voucher, error) {
+	var vouchers []entity.Voucher
+
+	err := r.db.WithContext(ctx).
+		Order("id DESC").
+		Find(&vouchers).Error
+
+	return vouchers, err
+}
+
+func (r *voucherImpl) Deactivate(ctx context.Context, voucherID int) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Voucher{}).
+		Where("id = ?", voucherID).
+		Update("is_active", false).Error
+}
+
+func (r *voucherImpl) CountUsageByUser(ctx context.Context, tx *gorm.DB, voucherID int, userID int) (int64, error) {
+	var count int64
+
+	err := tx.WithContext(ctx).
+		Model(&entity.VoucherUsage{}).
+		Where("id_voucher = ? AND id_user = ?", voucherID, userID).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (r *voucherImpl) IncrementTerpakai(ctx context.Context, tx *gorm.DB, voucherID int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Voucher{}).
+		Where("id = ?", voucherID).
+		Update("terpakai", gorm.Expr("terpakai + 1")).Error
+}
+
+func (r *voucherImpl) DecrementTerpakai(ctx context.Context, tx *gorm.DB, voucherID int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Voucher{}).
+		Where("id = ? AND terpakai > 0", voucherID).
+		Update("terpakai", gorm.Expr("terpakai - 1")).Error
+}
+
+func (r *voucherImpl) CreateUsage(ctx context.Context, tx *gorm.DB, usage *entity.VoucherUsage) error {
+	return tx.WithContext(ctx).Create(usage).Error
+}
+
+func (r *voucherImpl) DeleteUsageByTrx(ctx context.Context, tx *gorm.DB, trxID int) (int64, error) {
+	res := tx.WithContext(ctx).
+		Where("id_trx = ?", trxID).
+		Delete(&entity.VoucherUsage{})
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+	"strconv"
+)
+
+var ErrReportNotFound = errors.New("laporan tidak ditemukan")
+
+type AdminReportUsecase interface {
+	GMV(ctx context.Context, filter *entity.AdminReportFilter) (*models.AdminGMVResponse, *helper.ErrorStruct)
+	OrderStatus(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminStatusCountResponse, *helper.ErrorStruct)
+	Signups(ctx context.Context, filter *entity.AdminReportFilter) (*models.AdminSignupResponse, *helper.ErrorStruct)
+	TopCategories(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminCategoryResponse, *helper.ErrorStruct)
+	TopTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminTokoResponse, *helper.ErrorStruct)
+	PaymentMethods(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AnalyticsPaymentMethodResponse, *helper.ErrorStruct)
+	ResellerKomisi(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminResellerKomisiResponse, *helper.ErrorStruct)
+	CSV(ctx context.Context, report string, filter *entity.AdminReportFilter) (*models.ReportFile, *helper.ErrorStruct)
+}
+
+type adminReportImpl struct {
+	repo repository.AdminReportRepository
+}
+
+func NewAdminReportUsecase(repo repository.AdminReportRepository) AdminReportUsecase {
+	return &adminReportImpl{
+		repo: repo,
+	}
+}
+
+// prepare mengisi rentang tanggal, periode dan limit default seperti analitik toko
+func (a *adminReportImpl) prepare(filter *entity.AdminReportFilter) *helper.ErrorStruct {
+	if err := analyticsPeriode(&filter.Periode); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if err := analyticsRange(&filter.Dari, &filter.Sampai); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxAnalyticsTopLimit {
+		filter.Limit = maxAnalyticsTopLimit
+	}
+
+	return nil
+}
+
+// GMV nilai pesanan terbayar per periode, periode tanpa pesanan diisi nol
+func (a *adminReportImpl) GMV(ctx context.Context, filter *entity.AdminReportFilter) (*models.AdminGMVResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	points, err := a.repo.GMVSeries(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	byPeriode := make(map[string]entity.GMVPoint, len(points))
+	for _, p := range points {
+		byPeriode[p.Periode] = p
+	}
+
+	res := &models.AdminGMVResponse{
+		Dari:    filter.Dari.Format(analyticsDateLayout),
+		Sampai:  filter.Sampai.Format(analyticsDateLayout),
+		Periode: filter.Periode,
+		Data:    make([]models.AdminGMVPoint, 0),
+	}
+
+	for t := periodeStart(filter.Dari, filter.Periode); !t.After(filter.Sampai); t = periodeNext(t, filter.Periode) {
+		key := t.Format(analyticsDateLayout)
+		p := byPeriode[key]
+		res.Data = append(res.Data, models.AdminGMVPoint{
+			Periode: key,
+			Pesanan: p.Pesanan,
+			GMV:     p.GMV,
+		})
+	}
+
+	return res, nil
+}
+
+// OrderStatus jumlah pesanan per status untuk pesanan yang dibuat dalam rentang tanggal
+func (a *adminReportImpl) OrderStatus(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminStatusCountResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	counts, err := a.repo.OrderStatusCounts(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.AdminStatusCountResponse, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, models.AdminStatusCountResponse{
+			Status: c.Status,
+			Jumlah: c.Jumlah,
+		})
+	}
+
+	return res, nil
+}
+
+// Signups user dan toko baru per periode
+func (a *adminReportImpl) Signups(ctx context.Context, filter *entity.AdminReportFilter) (*models.AdminSignupResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	users, err := a.repo.NewUsers(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	tokos, err := a.repo.NewTokos(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	userByPeriode := make(map[string]int64, len(users))
+	for _, u := range users {
+		userByPeriode[u.Periode] = u.Jumlah
+	}
+	tokoByPeriode := make(map[string]int64, len(tokos))
+	for _, t := range tokos {
+		tokoByPeriode[t.Periode] = t.Jumlah
+	}
+
+	res := &models.AdminSignupResponse{
+		Dari:    filter.Dari.Format(analyticsDateLayout),
+		Sampai:  filter.Sampai.Format(analyticsDateLayout),
+		Periode: filter.Periode,
+		Data:    make([]models.AdminSignupPoint, 0),
+	}
+
+	for t := periodeStart(filter.Dari, filter.Periode); !t.After(filter.Sampai); t = periodeNext(t, filter.Periode) {
+		key := t.Format(analyticsDateLayout)
+		res.Data = append(res.Data, models.AdminSignupPoint{
+			Periode:  key,
+			UserBaru: userByPeriode[key],
+			TokoBaru: tokoByPeriode[key],
+		})
+	}
+
+	return res, nil
+}
+
+func (a *adminReportImpl) TopCategories(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminCategoryResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	categories, err := a.repo.TopCategories(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.AdminCategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		res = append(res, models.AdminCategoryResponse{
+			IDCategory:   c.IDCategory,
+			NamaCategory: c.NamaCategory,
+			Unit:         c.Unit,
+			Pendapatan:   c.Pendapatan,
+		})
+	}
+
+	return res, nil
+}
+
+func (a *adminReportImpl) TopTokos(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminTokoResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	tokos, err := a.repo.TopTokos(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.AdminTokoResponse, 0, len(tokos))
+	for _, t := range tokos {
+		res = append(res, models.AdminTokoResponse{
+			IDToko:     t.IDToko,
+			NamaToko:   t.NamaToko,
+			Pesanan:    t.Pesanan,
+			Unit:       t.Unit,
+			Pendapatan: t.Pendapatan,
+		})
+	}
+
+	return res, nil
+}
+
+// PaymentMethods jumlah dan nilai pesanan terbayar per metode bayar
+func (a *adminReportImpl) PaymentMethods(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AnalyticsPaymentMethodResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	methods, err := a.repo.PaymentMethods(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return paymentMethodShares(methods), nil
+}
+
+// ResellerKomisi reseller dengan komisi bersih terbesar dalam rentang tanggal
+func (a *adminReportImpl) ResellerKomisi(ctx context.Context, filter *entity.AdminReportFilter) ([]models.AdminResellerKomisiResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(filter); herr != nil {
+		return nil, herr
+	}
+
+	resellers, err := a.repo.ResellerKomisi(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.AdminResellerKomisiResponse, 0, len(resellers))
+	for _, r := range resellers {
+		res = append(res, models.AdminResellerKomisiResponse{
+			IDUser:   r.IDUser,
+			Nama:     r.Nama,
+			Pesanan:  r.Pesanan,
+			Unit:     r.Unit,
+			Margin:   r.Margin,
+			Tertahan: r.Tertahan,
+			Tersedia: r.Tersedia,
+		})
+	}
+
+	return res, nil
+}
+
+// CSV tabel laporan yang sama dengan versi JSON-nya. Nominal ditulis sebagai angka rupiah polos.
+func (a *adminReportImpl) CSV(ctx context.Context, report string, filter *entity.AdminReportFilter) (*models.ReportFile, *helper.ErrorStruct) {
+	var (
+		header []string
+		rows   [][]string
+	)
+
+	switch report {
+	case "gmv":
+		res, herr := a.GMV(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"periode", "pesanan", "gmv"}
+		for _, p := range res.Data {
+			rows = append(rows, []string{p.Periode, itoa64(p.Pesanan), rupiahCell(p.GMV)})
+		}
+	case "order-status":
+		res, herr := a.OrderStatus(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"status", "jumlah"}
+		for _, c := range res {
+			rows = append(rows, []string{c.Status, itoa64(c.Jumlah)})
+		}
+	case "signups":
+		res, herr := a.Signups(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"periode", "user_baru", "toko_baru"}
+		for _, p := range res.Data {
+			rows = append(rows, []string{p.Periode, itoa64(p.UserBaru), itoa64(p.TokoBaru)})
+		}
+	case "top-categories":
+		res, herr := a.TopCategories(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"id_category", "nama_category", "unit", "pendapatan"}
+		for _, c := range res {
+			rows = append(rows, []string{strconv.Itoa(c.IDCategory), c.NamaCategory, itoa64(c.Unit), rupiahCell(c.Pendapatan)})
+		}
+	case "top-tokos":
+		res, herr := a.TopTokos(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"id_toko", "nama_toko", "pesanan", "unit", "pendapatan"}
+		for _, t := range res {
+			rows = append(rows, []string{strconv.Itoa(t.IDToko), t.NamaToko, itoa64(t.Pesanan), itoa64(t.Unit), rupiahCell(t.Pendapatan)})
+		}
+	case "payment-methods":
+		res, herr := a.PaymentMethods(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"metode_bayar", "pesanan", "pendapatan", "persentase"}
+		for _, m := range res {
+			rows = append(rows, []string{m.MetodeBayar, itoa64(m.Pesanan), rupiahCell(m.Pendapatan), strconv.FormatFloat(m.Persentase, 'f', -1, 64)})
+		}
+	case "reseller-commissions":
+		res, herr := a.ResellerKomisi(ctx, filter)
+		if herr != nil {
+			return nil, herr
+		}
+		header = []string{"id_user", "nama", "pesanan", "unit", "margin", "tertahan", "tersedia"}
+		for _, r := range res {
+			rows = append(rows, []string{strconv.Itoa(r.IDUser), r.Nama, itoa64(r.Pesanan), itoa64(r.Unit), rupiahCell(r.Margin), rupiahCell(r.Tertahan), rupiahCell(r.Tersedia)})
+		}
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrReportNotFound, Code: 404}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	name := report + "-" + filter.Dari.Format(analyticsDateLayout) + "-" + filter.Sampai.Format(analyticsDateLayout) + ".csv"
+	return &models.ReportFile{FileName: name, ContentType: "text/csv; charset=utf-8", Body: buf.Bytes()}, nil
+}
+
+func itoa64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func rupiahCell(v money.Rupiah) string {
+	return strconv.FormatInt(int64(v), 10)
+}
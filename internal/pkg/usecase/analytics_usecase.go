@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAnalyticsRange   = errors.New("rentang tanggal tidak valid, maksimal 2 tahun")
+	ErrAnalyticsPeriode = errors.New("periode harus day, week atau month")
+	ErrAnalyticsSort    = errors.New("sort harus unit atau pendapatan")
+)
+
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 731
+	maxAnalyticsTopLimit = 100
+	analyticsDateLayout  = "2006-01-02"
+)
+
+type AnalyticsUsecase interface {
+	Summary(ctx context.Context, filter *entity.AnalyticsFilter) (*models.AnalyticsSummaryResponse, *helper.ErrorStruct)
+	Sales(ctx context.Context, filter *entity.AnalyticsFilter) (*models.AnalyticsSalesResponse, *helper.ErrorStruct)
+	TopProducts(ctx context.Context, filter *entity.AnalyticsFilter) ([]models.AnalyticsProductResponse, *helper.ErrorStruct)
+	PaymentMethods(ctx context.Context, filter *entity.AnalyticsFilter) ([]models.AnalyticsPaymentMethodResponse, *helper.ErrorStruct)
+}
+
+type analyticsImpl struct {
+	repo     repository.AnalyticsRepository
+	tokoRepo repository.TokoRepository
+}
+
+func NewAnalyticsUsecase(repo repository.AnalyticsRepository, tokoRepo repository.TokoRepository) AnalyticsUsecase {
+	return &analyticsImpl{
+		repo:     repo,
+		tokoRepo: tokoRepo,
+	}
+}
+
+// analyticsRange mengisi rentang default 30 hari terakhir dan membatasi panjangnya
+func analyticsRange(dari *time.Time, sampai *time.Time) error {
+	if sampai.IsZero() {
+		now := time.Now()
+		*sampai = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if dari.IsZero() {
+		*dari = sampai.AddDate(0, 0, 1-defaultAnalyticsDays)
+	}
+
+	if dari.After(*sampai) || sampai.Sub(*dari) > maxAnalyticsDays*24*time.Hour {
+		return ErrAnalyticsRange
+	}
+	return nil
+}
+
+// analyticsPeriode memakai day bila periode kosong
+func analyticsPeriode(periode *string) error {
+	switch *periode {
+	case "":
+		*periode = entity.AnalyticsPeriodeDay
+	case entity.AnalyticsPeriodeDay, entity.AnalyticsPeriodeWeek, entity.AnalyticsPeriodeMonth:
+	default:
+		return ErrAnalyticsPeriode
+	}
+	return nil
+}
+
+// prepare mengisi rentang tanggal dan memastikan id_toko milik user
+func (a *analyticsImpl) prepare(ctx context.Context, filter *entity.AnalyticsFilter) *helper.ErrorStruct {
+	if err := analyticsRange(&filter.Dari, &filter.Sampai); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if filter.IDToko > 0 {
+		toko, err := a.tokoRepo.GetByID(ctx, filter.IDToko)
+		if err != nil || toko.IDUser != filter.IDUser {
+			return &helper.ErrorStruct{Err: errors.New("akses ditolak"), Code: 403}
+		}
+	}
+
+	return nil
+}
+
+// Summary total penjualan, rata-rata nilai pesanan dan rasio pembeli berulang
+func (a *analyticsImpl) Summary(ctx context.Context, filter *entity.AnalyticsFilter) (*models.AnalyticsSummaryResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(ctx, filter); herr != nil {
+		return nil, herr
+	}
+
+	total, err := a.repo.SalesTotal(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	buyers, err := a.repo.BuyerStats(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := &models.AnalyticsSummaryResponse{
+		Dari:            filter.Dari.Format(analyticsDateLayout),
+		Sampai:          filter.Sampai.Format(analyticsDateLayout),
+		Pesanan:         total.Pesanan,
+		Unit:            total.Unit,
+		Pendapatan:      total.Pendapatan,
+		Pembeli:         buyers.Pembeli,
+		PembeliBerulang: buyers.PembeliBerulang,
+	}
+
+	if total.Pesanan > 0 {
+		res.RataRataPesanan = total.Pendapatan.MulDiv(1, total.Pesanan)
+	}
+	if buyers.Pembeli > 0 {
+		res.RasioPembeliBerulang = roundRatio(float64(buyers.PembeliBerulang) / float64(buyers.Pembeli))
+	}
+
+	return res, nil
+}
+
+// Sales pendapatan dan unit per periode, periode tanpa penjualan diisi nol
+func (a *analyticsImpl) Sales(ctx context.Context, filter *entity.AnalyticsFilter) (*models.AnalyticsSalesResponse, *helper.ErrorStruct) {
+	if err := analyticsPeriode(&filter.Periode); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if herr := a.prepare(ctx, filter); herr != nil {
+		return nil, herr
+	}
+
+	points, err := a.repo.SalesSeries(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	byPeriode := make(map[string]entity.SalesPoint, len(points))
+	for _, p := range points {
+		byPeriode[p.Periode] = p
+	}
+
+	res := &models.AnalyticsSalesResponse{
+		Dari:    filter.Dari.Format(analyticsDateLayout),
+		Sampai:  filter.Sampai.Format(analyticsDateLayout),
+		Periode: filter.Periode,
+		Data:    make([]models.AnalyticsSalesPoint, 0),
+	}
+
+	for t := periodeStart(filter.Dari, filter.Periode); !t.After(filter.Sampai); t = periodeNext(t, filter.Periode) {
+		key := t.Format(analyticsDateLayout)
+		p := byPeriode[key]
+		res.Data = append(res.Data, models.AnalyticsSalesPoint{
+			Periode:    key,
+			Pesanan:    p.Pesanan,
+			Unit:       p.Unit,
+			Pendapatan: p.Pendapatan,
+		})
+	}
+
+	return res, nil
+}
+
+// TopProducts produk terlaris berdasarkan pendapatan (default) atau unit
+func (a *analyticsImpl) TopProducts(ctx context.Context, filter *entity.AnalyticsFilter) ([]models.AnalyticsProductResponse, *helper.ErrorStruct) {
+	switch filter.Sort {
+	case "", "unit", "pendapatan":
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrAnalyticsSort, Code: 400}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxAnalyticsTopLimit {
+		filter.Limit = maxAnalyticsTopLimit
+	}
+
+	if herr := a.prepare(ctx, filter); herr != nil {
+		return nil, herr
+	}
+
+	products, err := a.repo.TopProducts(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.AnalyticsProductResponse, 0, len(products))
+	for _, p := range products {
+		res = append(res, models.AnalyticsProductResponse{
+			IDProduk:   p.IDProduk,
+			NamaProduk: p.NamaProduk,
+			Unit:       p.Unit,
+			Pendapatan: p.Pendapatan,
+		})
+	}
+
+	return res, nil
+}
+
+// PaymentMethods pesanan dan pendapatan per metode bayar beserta persentase pendapatannya
+func (a *analyticsImpl) PaymentMethods(ctx context.Context, filter *entity.AnalyticsFilter) ([]models.AnalyticsPaymentMethodResponse, *helper.ErrorStruct) {
+	if herr := a.prepare(ctx, filter); herr != nil {
+		return nil, herr
+	}
+
+	methods, err := a.repo.PaymentMethods(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return paymentMethodShares(methods), nil
+}
+
+// salesRecorder menjaga tabel rekap penjualan tetap sama dengan pesanan yang sudah dibayar
+type salesRecorder struct {
+	repo repository.AnalyticsRepository
+}
+
+func newSalesRecorder(repo repository.AnalyticsRepository) *salesRecorder {
+	return &salesRecorder{
+		repo: repo,
+	}
+}
+
+// paid mencatat seluruh paket trx ketika pembayaran diterima
+func (s *salesRecorder) paid(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return s.repo.RecordTrx(ctx, tx, trxID, 1)
+}
+
+// cancelled mengurangi paket trx yang belum dibatalkan, dipanggil sebelum status paket disamakan
+func (s *salesRecorder) cancelled(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return s.repo.RecordTrx(ctx, tx, trxID, -1)
+}
+
+// rejected mengurangi satu paket toko yang ditolak penjual
+func (s *salesRecorder) rejected(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) error {
+	return s.repo.RecordToko(ctx, tx, trxID, tokoID, -1)
+}
+
+// periodeStart awal periode yang memuat t, minggu dimulai hari Senin
+func periodeStart(t time.Time, periode string) time.Time {
+	switch periode {
+	case entity.AnalyticsPeriodeWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case entity.AnalyticsPeriodeMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+func periodeNext(t time.Time, periode string) time.Time {
+	switch periode {
+	case entity.AnalyticsPeriodeWeek:
+		return t.AddDate(0, 0, 7)
+	case entity.AnalyticsPeriodeMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// paymentMethodShares menambahkan persentase pendapatan tiap metode bayar terhadap totalnya
+func paymentMethodShares(methods []entity.PaymentMethodSales) []models.AnalyticsPaymentMethodResponse {
+	var total float64
+	for _, m := range methods {
+		total += float64(m.Pendapatan)
+	}
+
+	res := make([]models.AnalyticsPaymentMethodResponse, 0, len(methods))
+	for _, m := range methods {
+		item := models.AnalyticsPaymentMethodResponse{
+			MetodeBayar: m.MetodeBayar,
+			Pesanan:     m.Pesanan,
+			Pendapatan:  m.Pendapatan,
+		}
+		if total > 0 {
+			item.Persentase = roundRatio(float64(m.Pendapatan) / total * 100)
+		}
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// roundRatio membulatkan rasio dan persentase ke empat desimal
+func roundRatio(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
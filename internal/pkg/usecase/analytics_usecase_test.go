@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"testing"
+
+	"pbi/internal/pkg/entity"
+)
+
+func TestPaymentMethodShares(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []entity.PaymentMethodSales
+		want    []float64
+	}{
+		{
+			"dibagi terhadap total pendapatan",
+			[]entity.PaymentMethodSales{
+				{MetodeBayar: "ovo", Pesanan: 2, Pendapatan: 100000},
+				{MetodeBayar: "cod", Pesanan: 1, Pendapatan: 200000},
+			},
+			[]float64{33.3333, 66.6667},
+		},
+		{
+			"total nol tidak membagi",
+			[]entity.PaymentMethodSales{{MetodeBayar: "ovo", Pesanan: 1}},
+			[]float64{0},
+		},
+		{"tanpa data", nil, []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := paymentMethodShares(tt.methods)
+			if len(res) != len(tt.want) {
+				t.Fatalf("len = %d, want %d", len(res), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if res[i].Persentase != want {
+					t.Errorf("%s persentase = %v, want %v", res[i].MetodeBayar, res[i].Persentase, want)
+				}
+				if res[i].Pendapatan != tt.methods[i].Pendapatan || res[i].Pesanan != tt.methods[i].Pesanan {
+					t.Errorf("%s = %+v, want %+v", res[i].MetodeBayar, res[i], tt.methods[i])
+				}
+			}
+		})
+	}
+}
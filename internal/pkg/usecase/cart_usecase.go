@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCartItemNotFound = errors.New("item keranjang tidak ditemukan")
+	ErrOwnProduct       = errors.New("tidak boleh membeli produk milik sendiri")
+	ErrStockNotEnough   = errors.New("stok produk tidak mencukupi")
+)
+
+// CartIssueError daftar masalah (harga berubah / stok kurang) yang menahan checkout
+type CartIssueError struct {
+	Issues []string
+}
+
+func (e *CartIssueError) Error() string {
+	return strings.Join(e.Issues, "; ")
+}
+
+type CartUsecase interface {
+	Add(ctx context.Context, userID int, req *models.CartAddRequest) *helper.ErrorStruct
+	UpdateKuantitas(ctx context.Context, userID int, cartID int, req *models.CartUpdateRequest) *helper.ErrorStruct
+	Remove(ctx context.Context, userID int, cartID int) *helper.ErrorStruct
+	List(ctx context.Context, userID int) (*models.CartResponse, *helper.ErrorStruct)
+	Checkout(ctx context.Context, userID int, idemKey string, req *models.CartCheckoutRequest) (*models.CartCheckoutResponse, *helper.ErrorStruct)
+}
+
+type cartImpl struct {
+	repo   repository.CartRepository
+	prepo  repository.ProductRepository
+	trxUsc TransactionUsecase
+}
+
+func NewCartUsecase(repo repository.CartRepository, prepo repository.ProductRepository, trxUsc TransactionUsecase) CartUsecase {
+	return &cartImpl{
+		repo:   repo,
+		prepo:  prepo,
+		trxUsc: trxUsc,
+	}
+}
+
+func (c *cartImpl) Add(ctx context.Context, userID int, req *models.CartAddRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	// kuantitas dijumlahkan dengan baris yang sudah ada, jadi totalnya yang dicek ke stok tersedia
+	existing, err := c.repo.KuantitasProduk(ctx, userID, req.ProductID)
+	if err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	produk, herr := c.checkProduk(ctx, userID, req.ProductID, existing+req.Kuantitas)
+	if herr != nil {
+		return herr
+	}
+
+	item := &entity.CartItem{
+		IDUser:            userID,
+		IDProduk:          produk.ID,
+		Kuantitas:         req.Kuantitas,
+		HargaSaatDitambah: produk.HargaKonsumen,
+	}
+
+	if err := c.repo.Add(ctx, item); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return nil
+}
+
+func (c *cartImpl) UpdateKuantitas(ctx context.Context, userID int, cartID int, req *models.CartUpdateRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	items, err := c.repo.ListByUser(ctx, userID, []int{cartID})
+	if err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+	if len(items) == 0 {
+		return &helper.ErrorStruct{Err: ErrCartItemNotFound, Code: 404}
+	}
+
+	produk, herr := c.checkProduk(ctx, userID, items[0].IDProduk, req.Kuantitas)
+	if herr != nil {
+		return herr
+	}
+
+	// harga snapshot ikut diperbarui karena user sudah melihat harga terbaru
+	if err := c.repo.UpdateKuantitas(ctx, cartID, userID, req.Kuantitas, produk.HargaKonsumen); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &helper.ErrorStruct{Err: ErrCartItemNotFound, Code: 404}
+		}
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return nil
+}
+
+func (c *cartImpl) Remove(ctx context.Context, userID int, cartID int) *helper.ErrorStruct {
+	if err := c.repo.Delete(ctx, cartID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &helper.ErrorStruct{Err: ErrCartItemNotFound, Code: 404}
+		}
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return nil
+}
+
+func (c *cartImpl) List(ctx context.Context, userID int) (*models.CartResponse, *helper.ErrorStruct) {
+	items, err := c.repo.ListByUser(ctx, userID, nil)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := &models.CartResponse{
+		Items: make([]models.CartItemResponse, 0, len(items)),
+	}
+
+	for _, item := range items {
+		subtotal := item.HargaKonsumen.Times(item.Kuantitas)
+
+		res.Items = append(res.Items, models.CartItemResponse{
+			ID:         item.ID,
+			ProductID:  item.IDProduk,
+			NamaProduk: item.NamaProduk,
+			Toko: models.TokoInfo{
+				ID:       item.IDToko,
+				NamaToko: item.NamaToko,
+			},
+			Kuantitas:         item.Kuantitas,
+			HargaSaatDitambah: item.HargaSaatDitambah,
+			HargaKonsumen:     item.HargaKonsumen,
+			Subtotal:          subtotal,
+			Stok:              item.Stok,
+			HargaBerubah:      item.HargaKonsumen != item.HargaSaatDitambah,
+			StokKurang:        item.Stok < item.Kuantitas,
+		})
+		res.TotalHarga += subtotal
+	}
+
+	return res, nil
+}
+
+// Checkout mengubah baris keranjang terpilih menjadi transaksi lewat CreateTransaction.
+// Harga yang berubah (tanpa konfirmasi) dan stok yang kurang dilaporkan sebelum transaksi dibuat.
+func (c *cartImpl) Checkout(ctx context.Context, userID int, idemKey string, req *models.CartCheckoutRequest) (*models.CartCheckoutResponse, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	// key dicocokkan dengan body keranjang sebelum baris keranjang dibaca: setelah checkout
+	// berhasil barisnya sudah dihapus, retry dengan key yang sama harus mendapat trx yang sama
+	var requestHash string
+	if idemKey != "" {
+		hash, err := hashCheckoutRequest(req)
+		if err != nil {
+			return nil, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+		requestHash = hash
+
+		if trxID, herr, found := c.trxUsc.ReplayCheckout(ctx, userID, idemKey, requestHash); found {
+			if herr != nil {
+				return nil, herr
+			}
+			return &models.CartCheckoutResponse{TrxID: trxID}, nil
+		}
+	}
+
+	items, err := c.repo.ListByUser(ctx, userID, req.CartIDs)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+	if len(items) != len(uniqueInts(req.CartIDs)) {
+		return nil, &helper.ErrorStruct{Err: ErrCartItemNotFound, Code: 404}
+	}
+
+	var issues []string
+	trxReq := &models.CreateTrxRequest{
+		MethodBayar: req.MethodBayar,
+		AlamatKirim: req.AlamatKirim,
+		Dropship:    req.Dropship,
+		KodeVoucher: req.KodeVoucher,
+		Pengiriman:  req.Pengiriman,
+	}
+
+	for _, item := range items {
+		if item.Stok < item.Kuantitas {
+			issues = append(issues, fmt.Sprintf("stok %s tersisa %d", item.NamaProduk, item.Stok))
+		}
+		if item.HargaKonsumen != item.HargaSaatDitambah && !req.KonfirmasiHarga {
+			issues = append(issues, fmt.Sprintf("harga %s berubah dari %s menjadi %s", item.NamaProduk, item.HargaSaatDitambah, item.HargaKonsumen))
+		}
+
+		trxReq.DetailTrx = append(trxReq.DetailTrx, models.CreateTrxItemRequest{
+			ProductID: item.IDProduk,
+			Kuantitas: item.Kuantitas,
+		})
+	}
+
+	if len(issues) > 0 {
+		return nil, &helper.ErrorStruct{Err: &CartIssueError{Issues: issues}, Code: 409}
+	}
+
+	trxID, herr := c.trxUsc.CreateTransactionWithHash(ctx, userID, idemKey, requestHash, trxReq)
+	if herr != nil {
+		return nil, herr
+	}
+
+	// transaksi sudah tersimpan, kegagalan membersihkan keranjang cukup dicatat
+	cartIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		cartIDs = append(cartIDs, item.ID)
+	}
+	if err := c.repo.DeleteByIDs(ctx, userID, cartIDs); err != nil {
+		helper.LogError(err)
+	}
+
+	return &models.CartCheckoutResponse{TrxID: trxID}, nil
+}
+
+func (c *cartImpl) checkProduk(ctx context.Context, userID int, productID int, qty int) (*entity.Produk, *helper.ErrorStruct) {
+	produk, err := c.prepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &helper.ErrorStruct{Err: ErrProductNotFound, Code: 404}
+		}
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	if produk.Toko != nil && produk.Toko.IDUser == userID {
+		return nil, &helper.ErrorStruct{Err: ErrOwnProduct, Code: 400}
+	}
+
+	if produk.StokTersedia() < qty {
+		return nil, &helper.ErrorStruct{Err: ErrStockNotEnough, Code: 400}
+	}
+
+	return produk, nil
+}
+
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	res := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+)
+
+// fakeCartRepo keranjang satu user di memori
+type fakeCartRepo struct {
+	repository.CartRepository
+	items []entity.CartItemWithProduk
+	added []entity.CartItem
+}
+
+func (f *fakeCartRepo) Add(ctx context.Context, item *entity.CartItem) error {
+	f.added = append(f.added, *item)
+	return nil
+}
+
+func (f *fakeCartRepo) KuantitasProduk(ctx context.Context, userID int, produkID int) (int, error) {
+	qty := 0
+	for _, item := range f.items {
+		if item.IDProduk == produkID {
+			qty += item.Kuantitas
+		}
+	}
+	return qty, nil
+}
+
+func (f *fakeCartRepo) ListByUser(ctx context.Context, userID int, cartIDs []int) ([]entity.CartItemWithProduk, error) {
+	var res []entity.CartItemWithProduk
+	for _, item := range f.items {
+		for _, id := range cartIDs {
+			if item.ID == id {
+				res = append(res, item)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeCartRepo) DeleteByIDs(ctx context.Context, userID int, cartIDs []int) error {
+	var rest []entity.CartItemWithProduk
+	for _, item := range f.items {
+		if !containsInt(cartIDs, item.ID) {
+			rest = append(rest, item)
+		}
+	}
+	f.items = rest
+	return nil
+}
+
+type fakeProdukRepo struct {
+	repository.ProductRepository
+	produk *entity.Produk
+}
+
+func (f *fakeProdukRepo) GetByID(ctx context.Context, productID int) (*entity.Produk, error) {
+	return f.produk, nil
+}
+
+// fakeCheckoutUsecase menyimpan hasil checkout per Idempotency-Key seperti idempotency_key
+type fakeCheckoutUsecase struct {
+	TransactionUsecase
+	keys    map[string]string
+	trxIDs  map[string]int
+	created int
+}
+
+func (f *fakeCheckoutUsecase) ReplayCheckout(ctx context.Context, userID int, idemKey string, requestHash string) (int, *helper.ErrorStruct, bool) {
+	hash, ok := f.keys[idemKey]
+	if !ok {
+		return 0, nil, false
+	}
+	if hash != requestHash {
+		return 0, &helper.ErrorStruct{Err: ErrIdempotencyKeyReused, Code: 409}, true
+	}
+	return f.trxIDs[idemKey], nil, true
+}
+
+func (f *fakeCheckoutUsecase) CreateTransactionWithHash(ctx context.Context, userID int, idemKey string, requestHash string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct) {
+	f.created++
+	if idemKey != "" {
+		f.keys[idemKey] = requestHash
+		f.trxIDs[idemKey] = 100 + f.created
+	}
+	return 100 + f.created, nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCartAddStock(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing int
+		qty      int
+		want     error
+	}{
+		{"keranjang kosong", 0, 3, nil},
+		{"total pas stok tersedia", 2, 3, nil},
+		{"total melebihi stok tersedia", 3, 3, ErrStockNotEnough},
+		{"tambahan saja sudah melebihi", 0, 6, ErrStockNotEnough},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCartRepo{}
+			if tt.existing > 0 {
+				repo.items = []entity.CartItemWithProduk{{CartItem: entity.CartItem{ID: 1, IDUser: 7, IDProduk: 5, Kuantitas: tt.existing}}}
+			}
+			// stok fisik 8, 3 ditahan pesanan lain: tersedia 5
+			prepo := &fakeProdukRepo{produk: &entity.Produk{ID: 5, Stok: 8, StokDitahan: 3}}
+			c := NewCartUsecase(repo, prepo, nil)
+
+			herr := c.Add(context.Background(), 7, &models.CartAddRequest{ProductID: 5, Kuantitas: tt.qty})
+			if tt.want == nil {
+				if herr != nil {
+					t.Fatalf("Add = %v, want nil", herr.Err)
+				}
+				if len(repo.added) != 1 {
+					t.Errorf("baris ditambahkan = %d, want 1", len(repo.added))
+				}
+				return
+			}
+
+			if herr == nil || !errors.Is(herr.Err, tt.want) || herr.Code != 400 {
+				t.Fatalf("Add = %+v, want %v (400)", herr, tt.want)
+			}
+			if len(repo.added) != 0 {
+				t.Errorf("baris ditambahkan = %d, want 0", len(repo.added))
+			}
+		})
+	}
+}
+
+func TestCartCheckoutReplay(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeCartRepo{items: []entity.CartItemWithProduk{
+		{CartItem: entity.CartItem{ID: 1, IDUser: 7, IDProduk: 5, Kuantitas: 2, HargaSaatDitambah: 10000}, HargaKonsumen: 10000, Stok: 5},
+	}}
+	trxUsc := &fakeCheckoutUsecase{keys: map[string]string{}, trxIDs: map[string]int{}}
+	c := NewCartUsecase(repo, nil, trxUsc)
+
+	req := &models.CartCheckoutRequest{
+		CartIDs:     []int{1},
+		MethodBayar: "ovo",
+		AlamatKirim: 3,
+		Pengiriman:  []models.CreateTrxShippingRequest{{IDToko: 1, Kurir: "jne", Layanan: "REG"}},
+	}
+
+	first, herr := c.Checkout(ctx, 7, "key-1", req)
+	if herr != nil {
+		t.Fatalf("checkout pertama: %v", herr.Err)
+	}
+	if len(repo.items) != 0 {
+		t.Fatalf("keranjang setelah checkout = %d baris, want 0", len(repo.items))
+	}
+
+	// baris keranjang sudah dihapus, retry dengan key yang sama tetap mendapat trx yang sama
+	retry, herr := c.Checkout(ctx, 7, "key-1", req)
+	if herr != nil {
+		t.Fatalf("retry: %v (code %d)", herr.Err, herr.Code)
+	}
+	if retry.TrxID != first.TrxID || trxUsc.created != 1 {
+		t.Errorf("retry trx = %d (dibuat %d kali), want %d sekali", retry.TrxID, trxUsc.created, first.TrxID)
+	}
+
+	other := *req
+	other.MethodBayar = "dana"
+	if _, herr := c.Checkout(ctx, 7, "key-1", &other); herr == nil || !errors.Is(herr.Err, ErrIdempotencyKeyReused) {
+		t.Errorf("body berbeda = %+v, want %v", herr, ErrIdempotencyKeyReused)
+	}
+
+	// tanpa key, keranjang yang sudah kosong tetap 404
+	if _, herr := c.Checkout(ctx, 7, "", req); herr == nil || herr.Code != 404 {
+		t.Errorf("tanpa key = %+v, want 404", herr)
+	}
+}
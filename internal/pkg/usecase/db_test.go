@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB membuka database MySQL uji dari TEST_DB_DSN (format go-sql-driver, contoh
+// user:pass@tcp(127.0.0.1:3306)/pbi_test?parseTime=true&loc=Local). Database harus sudah
+// dimigrasi penuh dan boleh dikotori, setiap test membuat datanya sendiri.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN kosong, test database dilewati")
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+// testSeed pembuat data uji dengan nomor telepon unik per test
+type testSeed struct {
+	t      *testing.T
+	db     *gorm.DB
+	suffix string
+	n      int
+}
+
+func newTestSeed(t *testing.T, db *gorm.DB) *testSeed {
+	return &testSeed{t: t, db: db, suffix: fmt.Sprintf("%d", time.Now().UnixNano())}
+}
+
+func (s *testSeed) exec(query string, args ...interface{}) int {
+	s.t.Helper()
+
+	// LAST_INSERT_ID berlaku per koneksi, transaksi menjaga keduanya di koneksi yang sama
+	var id int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(query, args...).Error; err != nil {
+			return err
+		}
+		return tx.Raw("SELECT LAST_INSERT_ID()").Scan(&id).Error
+	})
+	if err != nil {
+		s.t.Fatalf("seed: %v", err)
+	}
+	return id
+}
+
+func (s *testSeed) user() int {
+	s.n++
+	return s.exec("INSERT INTO user (nama, notelp, email) VALUES (?, ?, ?)",
+		"user uji", fmt.Sprintf("t%s%d", s.suffix, s.n), fmt.Sprintf("t%s%d@test.local", s.suffix, s.n))
+}
+
+func (s *testSeed) toko(userID int, idKota string) int {
+	return s.exec("INSERT INTO toko (id_user, nama_toko, id_kota) VALUES (?, ?, ?)", userID, "toko uji", idKota)
+}
+
+func (s *testSeed) produk(tokoID int, hargaReseller int64, hargaKonsumen int64, stok int) int {
+	s.n++
+	categoryID := s.exec("INSERT INTO category (nama_category) VALUES (?)", "kategori uji")
+	return s.exec(`INSERT INTO produk (id_toko, id_category, nama_produk, slug, harga_reseller, harga_konsumen, stok, berat)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, tokoID, categoryID, "produk uji", fmt.Sprintf("produk-uji-%s-%d", s.suffix, s.n),
+		hargaReseller, hargaKonsumen, stok, 1000)
+}
@@ -37,6 +37,7 @@ func (d *destinationImpl) Create(ctx context.Context, userID int, req *models.De
 		NamaPenerima:  req.NamaPenerima,
 		NoTelp:        req.NoTelp,
 		DetailAlamat: req.DetailAlamat,
+		IDKota:       req.IDKota,
 	}
 
 	if err := d.repo.Create(ctx, dest); err != nil {
@@ -52,6 +53,7 @@ func (d *destinationImpl) Create(ctx context.Context, userID int, req *models.De
 		NamaPenerima: dest.NamaPenerima,
 		NoTelp:       dest.NoTelp,
 		DetailAlamat: dest.DetailAlamat,
+		IDKota:       dest.IDKota,
 	}
 
 	return res, nil
@@ -83,6 +85,9 @@ func (d *destinationImpl) Update(ctx context.Context,id int,userID int,req *mode
 	if req.DetailAlamat != "" {
 		existing.DetailAlamat = req.DetailAlamat
 	}
+	if req.IDKota != "" {
+		existing.IDKota = req.IDKota
+	}
 
 	if err := d.repo.Update(ctx, existing); err != nil {
 		return nil, &helper.ErrorStruct{
@@ -97,6 +102,7 @@ func (d *destinationImpl) Update(ctx context.Context,id int,userID int,req *mode
 		NamaPenerima: existing.NamaPenerima,
 		NoTelp:       existing.NoTelp,
 		DetailAlamat: existing.DetailAlamat,
+		IDKota:       existing.IDKota,
 	}
 
 	return res, nil
@@ -144,6 +150,7 @@ func (d *destinationImpl) GetByID(ctx context.Context,id int,userID int,) (*mode
 		NamaPenerima: dest.NamaPenerima,
 		NoTelp:       dest.NoTelp,
 		DetailAlamat: dest.DetailAlamat,
+		IDKota:       dest.IDKota,
 	}, nil
 }
 
@@ -165,6 +172,7 @@ func (d *destinationImpl) GetAll(ctx context.Context,userID int,) ([]models.Dest
 			NamaPenerima: dest.NamaPenerima,
 			NoTelp:       dest.NoTelp,
 			DetailAlamat: dest.DetailAlamat,
+			IDKota:       dest.IDKota,
 		})
 	}
 
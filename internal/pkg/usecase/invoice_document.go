@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/utils/money"
+	"pbi/internal/utils/pdf"
+	"strconv"
+	"strings"
+)
+
+var ErrInvoiceFormat = errors.New("format invoice harus pdf atau html")
+
+// GetInvoice membuat dokumen invoice dari trx, detail_trx dan snapshot log_produk.
+// Pembeli dan admin mendapat invoice lengkap, penjual hanya paket tokonya sendiri.
+func (t *transactionImpl) GetInvoice(ctx context.Context, trxID int, userID int, isAdmin bool, format string) (*models.InvoiceFile, *helper.ErrorStruct) {
+	if format == "" {
+		format = "pdf"
+	}
+	if format != "pdf" && format != "html" {
+		return nil, &helper.ErrorStruct{Err: ErrInvoiceFormat, Code: 400}
+	}
+
+	trx, err := t.repo.FindTransactionByID(ctx, trxID)
+	if err != nil {
+		return nil, trxError(err)
+	}
+
+	actor, err := t.resolveActor(ctx, t.db, trx, userID, isAdmin)
+	if err != nil {
+		return nil, trxError(err)
+	}
+
+	var tokoIDs []int
+	if actor == entity.TrxActorSeller {
+		tokoIDs, err = t.repo.GetSellerTokoIDs(ctx, trx.ID, userID)
+		if err != nil {
+			return nil, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+	}
+
+	doc, err := t.buildInvoice(ctx, trx, tokoIDs)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	name := strings.ReplaceAll(doc.KodeInvoice, "/", "-")
+	if name == "" {
+		name = "invoice-" + strconv.Itoa(trx.ID)
+	}
+
+	if format == "html" {
+		body, err := renderInvoiceHTML(doc)
+		if err != nil {
+			return nil, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+		return &models.InvoiceFile{FileName: name + ".html", ContentType: "text/html; charset=utf-8", Body: body}, nil
+	}
+
+	return &models.InvoiceFile{FileName: name + ".pdf", ContentType: "application/pdf", Body: renderInvoicePDF(doc)}, nil
+}
+
+// buildInvoice menyusun data invoice, tokoIDs nil berarti semua toko.
+// Harga reseller hanya dicetak untuk pesanan dropship yang memintanya lewat tampilkan_harga_reseller.
+func (t *transactionImpl) buildInvoice(ctx context.Context, trx *entity.Transaction, tokoIDs []int) (*models.InvoiceDocument, error) {
+	details, err := t.repo.GetTransactionDetails(ctx, trx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pakets, err := t.paketRepo.ListByTrx(ctx, trx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dests, err := t.destRepo.FindByIDs(ctx, []int{trx.AlamatPengiriman})
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed map[int]bool
+	if tokoIDs != nil {
+		allowed = make(map[int]bool, len(tokoIDs))
+		for _, id := range tokoIDs {
+			allowed[id] = true
+		}
+	}
+
+	doc := &models.InvoiceDocument{
+		KodeInvoice: trx.KodeInvoice,
+		Tanggal:     trx.CreatedAt,
+		Status:      trx.Status,
+		MetodeBayar: trx.MetodeBayar,
+	}
+
+	if trx.Dropship {
+		doc.Dropship = true
+		doc.NamaPengirim = trx.NamaPengirim
+		doc.TelpPengirim = trx.TelpPengirim
+		doc.TampilkanHargaReseller = trx.TampilkanHargaReseller
+	}
+
+	if len(dests) > 0 {
+		doc.NamaPenerima = dests[0].NamaPenerima
+		doc.NoTelp = dests[0].NoTelp
+		doc.DetailAlamat = dests[0].DetailAlamat
+	}
+
+	var (
+		order  []int
+		byToko = make(map[int]*models.InvoiceToko)
+	)
+	for _, d := range details {
+		if allowed != nil && !allowed[d.IDToko] {
+			continue
+		}
+
+		toko, ok := byToko[d.IDToko]
+		if !ok {
+			toko = &models.InvoiceToko{NamaToko: d.NamaToko}
+			byToko[d.IDToko] = toko
+			order = append(order, d.IDToko)
+		}
+
+		harga := d.HargaKonsumen
+		item := models.InvoiceItem{
+			NamaProduk:  d.NamaProduk,
+			Kuantitas:   d.Kuantitas,
+			HargaSatuan: harga,
+			Diskon:      d.Diskon,
+			Total:       d.HargaTotal,
+		}
+		if doc.TampilkanHargaReseller {
+			item.HargaReseller = d.HargaReseller
+		}
+		toko.Items = append(toko.Items, item)
+
+		doc.Subtotal += harga.Times(d.Kuantitas)
+		doc.Diskon += d.Diskon
+	}
+
+	for _, p := range pakets {
+		toko, ok := byToko[p.IDToko]
+		if !ok {
+			continue
+		}
+		toko.KodeInvoice = p.KodeInvoice
+		toko.Kurir = p.Kurir
+		toko.Layanan = p.Layanan
+		toko.Ongkir = p.Ongkir
+		doc.OngkosKirim += p.Ongkir
+	}
+
+	for _, id := range order {
+		doc.Toko = append(doc.Toko, *byToko[id])
+	}
+
+	if allowed == nil {
+		doc.Total = trx.HargaTotal
+		return doc, nil
+	}
+
+	// invoice penjual memakai nomor paket tokonya
+	doc.Total = doc.Subtotal - doc.Diskon + doc.OngkosKirim
+	if len(doc.Toko) == 1 && doc.Toko[0].KodeInvoice != "" {
+		doc.KodeInvoice = doc.Toko[0].KodeInvoice
+	}
+
+	return doc, nil
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"rupiah": money.Rupiah.String,
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.KodeInvoice}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; max-width: 800px; margin: 32px auto; }
+h1 { margin: 0; font-size: 26px; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 6px 4px; text-align: left; }
+.num { text-align: right; white-space: nowrap; }
+.head td { vertical-align: top; }
+.toko { margin-top: 24px; }
+.toko h3 { background: #eee; padding: 6px; margin: 0; font-size: 14px; }
+.toko h3 small { float: right; font-weight: normal; }
+.items th { border-bottom: 1px solid #999; font-size: 12px; }
+.ongkir td { border-top: 1px dashed #ccc; color: #555; }
+.total { margin-top: 24px; width: 50%; margin-left: auto; }
+.total tr:last-child td { border-top: 1px solid #999; font-weight: bold; font-size: 15px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<table class="head">
+<tr>
+<td><h1>INVOICE</h1></td>
+<td class="num"><strong>{{.KodeInvoice}}</strong><br>{{.Tanggal.Format "02 Jan 2006 15:04"}}</td>
+</tr>
+<tr>
+<td><strong>Dikirim ke</strong><br>{{.NamaPenerima}} ({{.NoTelp}})<br>{{.DetailAlamat}}{{if .Dropship}}<br><br><strong>Pengirim</strong><br>{{.NamaPengirim}} ({{.TelpPengirim}}){{end}}</td>
+<td class="num"><strong>Pembayaran</strong><br>Metode: {{.MetodeBayar}}<br>Status: {{.Status}}</td>
+</tr>
+</table>
+{{range .Toko}}
+<div class="toko">
+<h3>{{.NamaToko}}{{if .KodeInvoice}} <small>{{.KodeInvoice}}</small>{{end}}</h3>
+<table class="items">
+<thead><tr><th>Produk</th><th class="num">Qty</th>{{if $.TampilkanHargaReseller}}<th class="num">Harga reseller</th>{{end}}<th class="num">Harga</th><th class="num">Diskon</th><th class="num">Total</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.NamaProduk}}</td><td class="num">{{.Kuantitas}}</td>{{if $.TampilkanHargaReseller}}<td class="num">{{rupiah .HargaReseller}}</td>{{end}}<td class="num">{{rupiah .HargaSatuan}}</td><td class="num">{{if .Diskon}}-{{rupiah .Diskon}}{{end}}</td><td class="num">{{rupiah .Total}}</td></tr>
+{{end}}{{if .Kurir}}<tr class="ongkir"><td colspan="{{if $.TampilkanHargaReseller}}5{{else}}4{{end}}">Ongkos kirim {{.Kurir}} {{.Layanan}}</td><td class="num">{{rupiah .Ongkir}}</td></tr>{{end}}
+</tbody>
+</table>
+</div>
+{{end}}
+<table class="total">
+<tr><td>Subtotal produk</td><td class="num">{{rupiah .Subtotal}}</td></tr>
+<tr><td>Diskon</td><td class="num">-{{rupiah .Diskon}}</td></tr>
+<tr><td>Ongkos kirim</td><td class="num">{{rupiah .OngkosKirim}}</td></tr>
+<tr><td>Total</td><td class="num">{{rupiah .Total}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+func renderInvoiceHTML(doc *models.InvoiceDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderInvoicePDF(doc *models.InvoiceDocument) []byte {
+	const (
+		left        = 40.0
+		right       = pdf.PageWidth - 40
+		colReseller = 340.0
+		colHarga    = 410.0
+		colDisk     = 480.0
+	)
+
+	// kolom harga reseller mengambil tempat dari kolom produk
+	colQty, lebarProduk := 330.0, 240.0
+	if doc.TampilkanHargaReseller {
+		colQty, lebarProduk = 260.0, 170.0
+	}
+
+	p := pdf.New()
+	y := 60.0
+
+	// pindah halaman jika sisa ruang kurang dari h
+	ensure := func(h float64) {
+		if y+h > pdf.PageHeight-50 {
+			p.AddPage()
+			y = 50
+		}
+	}
+
+	p.Text(left, y, 22, true, "INVOICE")
+	p.TextRight(right, y-8, 10, true, doc.KodeInvoice)
+	p.TextRight(right, y+6, 9, false, doc.Tanggal.Format("02 Jan 2006 15:04"))
+	y += 36
+
+	p.Text(left, y, 9, true, "Dikirim ke")
+	p.Text(330, y, 9, true, "Pembayaran")
+	y += 14
+	p.Text(left, y, 9, false, pdf.Fit(doc.NamaPenerima+" ("+doc.NoTelp+")", 280, 9, false))
+	p.Text(330, y, 9, false, "Metode: "+doc.MetodeBayar)
+	y += 12
+	p.Text(left, y, 9, false, pdf.Fit(doc.DetailAlamat, 280, 9, false))
+	p.Text(330, y, 9, false, "Status: "+doc.Status)
+	if doc.Dropship {
+		y += 20
+		p.Text(left, y, 9, true, "Pengirim")
+		y += 14
+		p.Text(left, y, 9, false, pdf.Fit(doc.NamaPengirim+" ("+doc.TelpPengirim+")", 280, 9, false))
+	}
+	y += 30
+
+	for _, toko := range doc.Toko {
+		ensure(60)
+		p.FillRect(left, y-12, right-left, 17, 0.92)
+		p.Text(left+4, y, 10, true, pdf.Fit(toko.NamaToko, 300, 10, true))
+		if toko.KodeInvoice != "" {
+			p.TextRight(right-4, y, 8, false, toko.KodeInvoice)
+		}
+		y += 20
+
+		p.Text(left, y, 8, true, "Produk")
+		p.TextRight(colQty, y, 8, true, "Qty")
+		if doc.TampilkanHargaReseller {
+			p.TextRight(colReseller, y, 8, true, "Harga reseller")
+		}
+		p.TextRight(colHarga, y, 8, true, "Harga")
+		p.TextRight(colDisk, y, 8, true, "Diskon")
+		p.TextRight(right, y, 8, true, "Total")
+		y += 5
+		p.Line(left, y, right, y)
+		y += 13
+
+		for _, item := range toko.Items {
+			ensure(14)
+			p.Text(left, y, 9, false, pdf.Fit(item.NamaProduk, lebarProduk, 9, false))
+			p.TextRight(colQty, y, 9, false, strconv.Itoa(item.Kuantitas))
+			if doc.TampilkanHargaReseller {
+				p.TextRight(colReseller, y, 9, false, item.HargaReseller.String())
+			}
+			p.TextRight(colHarga, y, 9, false, item.HargaSatuan.String())
+			if item.Diskon > 0 {
+				p.TextRight(colDisk, y, 9, false, "-"+item.Diskon.String())
+			}
+			p.TextRight(right, y, 9, false, item.Total.String())
+			y += 14
+		}
+
+		if toko.Kurir != "" {
+			ensure(14)
+			p.Text(left, y, 9, false, "Ongkos kirim "+toko.Kurir+" "+toko.Layanan)
+			p.TextRight(right, y, 9, false, toko.Ongkir.String())
+			y += 14
+		}
+		y += 12
+	}
+
+	ensure(80)
+	p.Line(330, y, right, y)
+	y += 16
+
+	rows := [][2]string{
+		{"Subtotal produk", doc.Subtotal.String()},
+		{"Diskon", "-" + doc.Diskon.String()},
+		{"Ongkos kirim", doc.OngkosKirim.String()},
+	}
+	for _, row := range rows {
+		p.Text(330, y, 9, false, row[0])
+		p.TextRight(right, y, 9, false, row[1])
+		y += 14
+	}
+
+	p.Line(330, y-6, right, y-6)
+	y += 8
+	p.Text(330, y, 11, true, "Total")
+	p.TextRight(right, y, 11, true, doc.Total.String())
+
+	return p.Bytes()
+}
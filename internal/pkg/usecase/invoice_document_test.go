@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+)
+
+// fakeInvoiceRepo mengembalikan satu baris pesanan dengan margin reseller
+type fakeInvoiceRepo struct {
+	repository.TransactionRepository
+}
+
+func (f *fakeInvoiceRepo) GetTransactionDetails(ctx context.Context, trxID int) ([]entity.DetailTrxWithJoin, error) {
+	return []entity.DetailTrxWithJoin{
+		{IDTrx: trxID, Kuantitas: 1, HargaTotal: 150000, NamaProduk: "Gamis", HargaReseller: 120000, HargaKonsumen: 150000, IDToko: 1, NamaToko: "Toko"},
+	}, nil
+}
+
+type fakeInvoicePaketRepo struct {
+	repository.PaketRepository
+}
+
+func (f *fakeInvoicePaketRepo) ListByTrx(ctx context.Context, trxID int) ([]entity.PaketToko, error) {
+	return nil, nil
+}
+
+type fakeInvoiceDestRepo struct {
+	repository.DestinationRepository
+}
+
+func (f *fakeInvoiceDestRepo) FindByIDs(ctx context.Context, ids []int) ([]entity.Destination, error) {
+	return nil, nil
+}
+
+func TestBuildInvoiceHargaReseller(t *testing.T) {
+	tests := []struct {
+		name string
+		trx  entity.Transaction
+		want bool
+	}{
+		{"pesanan biasa", entity.Transaction{ID: 1}, false},
+		{"dropship tanpa permintaan", entity.Transaction{ID: 1, Dropship: true}, false},
+		{"dropship meminta harga reseller", entity.Transaction{ID: 1, Dropship: true, TampilkanHargaReseller: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &transactionImpl{
+				repo:      &fakeInvoiceRepo{},
+				paketRepo: &fakeInvoicePaketRepo{},
+				destRepo:  &fakeInvoiceDestRepo{},
+			}
+
+			doc, err := uc.buildInvoice(context.Background(), &tt.trx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if doc.TampilkanHargaReseller != tt.want {
+				t.Errorf("TampilkanHargaReseller = %v, want %v", doc.TampilkanHargaReseller, tt.want)
+			}
+			if got := doc.Toko[0].Items[0].HargaReseller; (got != 0) != tt.want {
+				t.Errorf("HargaReseller = %s, want tercetak %v", got, tt.want)
+			}
+
+			var html bytes.Buffer
+			if err := invoiceTemplate.Execute(&html, doc); err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Contains(html.Bytes(), []byte("Harga reseller")); got != tt.want {
+				t.Errorf("kolom harga reseller di html = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"pbi/internal/config"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// invoiceCodePattern format prefix_invoice toko, juga dipakai saat toko mengubah prefixnya
+var invoiceCodePattern = regexp.MustCompile(`^[A-Z0-9]{2,16}$`)
+
+// invoiceNumberer membuat nomor invoice <prefix>/<periode>/<kode>/<urutan>.
+// Urutan diambil dari invoice_sequence per scope <prefix>/<periode>/<kode> di dalam db transaction
+// checkout, sehingga rollback ikut membatalkan nomor dan tidak ada nomor yang terlewat.
+type invoiceNumberer struct {
+	repo repository.InvoiceRepository
+	cfg  config.InvoiceConfig
+}
+
+func newInvoiceNumberer(repo repository.InvoiceRepository, cfg config.InvoiceConfig) *invoiceNumberer {
+	return &invoiceNumberer{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+func (n *invoiceNumberer) scope(now time.Time, kode string) string {
+	layout := "20060102"
+	if n.cfg.Period == "monthly" {
+		layout = "200601"
+	}
+	return fmt.Sprintf("%s/%s/%s", n.cfg.Prefix, now.Format(layout), kode)
+}
+
+// assign memberi nomor untuk trx (kode platform) dan setiap paket (kode toko).
+// Scope dikunci berurutan secara alfabetis supaya dua checkout tidak saling menunggu (deadlock).
+func (n *invoiceNumberer) assign(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, pakets []*entity.PaketToko, lines []*checkoutLine) error {
+	now := time.Now()
+
+	kodeToko := make(map[int]string)
+	for _, line := range lines {
+		kodeToko[line.Produk.IDToko] = tokoInvoiceCode(line.Produk.PrefixInvoiceToko, line.Produk.NamaToko, line.Produk.IDToko)
+	}
+
+	type target struct {
+		scope string
+		set   func(string)
+	}
+
+	targets := []target{{
+		scope: n.scope(now, n.cfg.PlatformCode),
+		set:   func(kode string) { trx.KodeInvoice = kode },
+	}}
+	for _, paket := range pakets {
+		targets = append(targets, target{
+			scope: n.scope(now, kodeToko[paket.IDToko]),
+			set:   func(kode string) { paket.KodeInvoice = kode },
+		})
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].scope < targets[j].scope
+	})
+
+	for _, t := range targets {
+		nomor, err := n.repo.NextSequence(ctx, tx, t.scope)
+		if err != nil {
+			return err
+		}
+		t.set(fmt.Sprintf("%s/%06d", t.scope, nomor))
+	}
+
+	return nil
+}
+
+// tokoInvoiceCode memakai prefix_invoice toko, jika kosong diambil dari huruf/angka nama toko
+func tokoInvoiceCode(prefix string, namaToko string, tokoID int) string {
+	if prefix != "" {
+		return prefix
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToUpper(namaToko) {
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+		if b.Len() == 8 {
+			break
+		}
+	}
+
+	if b.Len() < 2 {
+		return fmt.Sprintf("TOKO%d", tokoID)
+	}
+	return b.String()
+}
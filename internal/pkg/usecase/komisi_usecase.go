@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrKomisiStatus = errors.New("status harus tertahan atau tersedia")
+	ErrKomisiJenis  = errors.New("jenis harus penjualan, pembatalan atau retur")
+)
+
+const maxKomisiPageLimit = 100
+
+type KomisiUsecase interface {
+	Saldo(ctx context.Context, userID int) (*models.KomisiSaldoResponse, *helper.ErrorStruct)
+	Riwayat(ctx context.Context, filter *entity.KomisiFilter) (*models.KomisiListResponse, *helper.ErrorStruct)
+}
+
+type komisiImpl struct {
+	repo repository.KomisiRepository
+}
+
+func NewKomisiUsecase(repo repository.KomisiRepository) KomisiUsecase {
+	return &komisiImpl{
+		repo: repo,
+	}
+}
+
+// Saldo komisi reseller, hanya bagian tersedia yang boleh ditarik
+func (k *komisiImpl) Saldo(ctx context.Context, userID int) (*models.KomisiSaldoResponse, *helper.ErrorStruct) {
+	saldo, err := k.repo.Saldo(ctx, userID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return &models.KomisiSaldoResponse{
+		Tertahan: saldo.Tertahan,
+		Tersedia: saldo.Tersedia,
+		Total:    saldo.Tertahan + saldo.Tersedia,
+	}, nil
+}
+
+// Riwayat entri buku komisi terbaru lebih dulu, margin per baris detail_trx
+func (k *komisiImpl) Riwayat(ctx context.Context, filter *entity.KomisiFilter) (*models.KomisiListResponse, *helper.ErrorStruct) {
+	switch filter.Status {
+	case "", entity.KomisiStatusTertahan, entity.KomisiStatusTersedia:
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrKomisiStatus, Code: 400}
+	}
+
+	switch filter.Jenis {
+	case "", entity.KomisiJenisPenjualan, entity.KomisiJenisPembatalan, entity.KomisiJenisRetur:
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrKomisiJenis, Code: 400}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxKomisiPageLimit {
+		filter.Limit = maxKomisiPageLimit
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	entries, total, err := k.repo.List(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := &models.KomisiListResponse{
+		Data:       make([]models.KomisiEntryResponse, 0, len(entries)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+	}
+
+	for _, e := range entries {
+		res.Data = append(res.Data, models.KomisiEntryResponse{
+			ID:            e.ID,
+			IDTrx:         e.IDTrx,
+			KodeInvoice:   e.KodeInvoice,
+			IDDetailTrx:   e.IDDetailTrx,
+			IDToko:        e.IDToko,
+			IDProduk:      e.IDProduk,
+			NamaProduk:    e.NamaProduk,
+			IDRetur:       e.IDRetur,
+			Jenis:         e.Jenis,
+			Kuantitas:     e.Kuantitas,
+			HargaReseller: e.HargaReseller,
+			HargaKonsumen: e.HargaKonsumen,
+			Margin:        e.Margin,
+			Status:        e.Status,
+			TersediaPada:  e.TersediaPada,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// komisiLedger menulis buku komisi reseller mengikuti perubahan status pesanan.
+// Reseller adalah pembeli trx, margin diambil dari snapshot log_produk.
+type komisiLedger struct {
+	repo repository.KomisiRepository
+}
+
+func newKomisiLedger(repo repository.KomisiRepository) *komisiLedger {
+	return &komisiLedger{
+		repo: repo,
+	}
+}
+
+// paid mencatat margin setiap baris pesanan, masih tertahan
+func (k *komisiLedger) paid(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return k.repo.PostSales(ctx, tx, trxID)
+}
+
+// cancelled membalik margin pesanan yang dibatalkan setelah dibayar
+func (k *komisiLedger) cancelled(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return k.repo.Reverse(ctx, tx, trxID, 0)
+}
+
+// rejected membalik margin baris milik paket toko yang ditolak penjual
+func (k *komisiLedger) rejected(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) error {
+	return k.repo.Reverse(ctx, tx, trxID, tokoID)
+}
+
+// completed membuat komisi pesanan bisa ditarik
+func (k *komisiLedger) completed(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return k.repo.Release(ctx, tx, trxID)
+}
+
+// retur mengurangi margin item yang returnya disetujui
+func (k *komisiLedger) retur(ctx context.Context, tx *gorm.DB, returID int) error {
+	return k.repo.PostRetur(ctx, tx, returID)
+}
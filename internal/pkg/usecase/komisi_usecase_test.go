@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strings"
+	"testing"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestKomisiLedgerStatements memastikan setiap langkah buku komisi mengirim query dan argumen
+// yang benar tanpa database; perhitungan saldonya diuji TestKomisiLedger lewat make test-db.
+func TestKomisiLedgerStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		step  func(l *komisiLedger, tx *gorm.DB) error
+		query string
+		args  []interface{}
+	}{
+		{
+			"paid mencatat margin tertahan yang tidak negatif",
+			func(l *komisiLedger, tx *gorm.DB) error { return l.paid(context.Background(), tx, 9) },
+			"INSERT INTO komisi_reseller .*GREATEST(log_produk.harga_konsumen - log_produk.harga_reseller, 0) * detail_trx.kuantitas",
+			[]interface{}{entity.KomisiJenisPenjualan, entity.KomisiStatusTertahan, 9},
+		},
+		{
+			"rejected hanya membalik baris toko",
+			func(l *komisiLedger, tx *gorm.DB) error { return l.rejected(context.Background(), tx, 9, 4) },
+			"FROM komisi_reseller WHERE id_trx = ? AND id_toko = ? GROUP BY",
+			[]interface{}{entity.KomisiJenisPembatalan, entity.KomisiStatusTertahan, 9, 4},
+		},
+		{
+			"cancelled membalik semua toko",
+			func(l *komisiLedger, tx *gorm.DB) error { return l.cancelled(context.Background(), tx, 9) },
+			"FROM komisi_reseller WHERE id_trx = ? AND 1 = 1 GROUP BY",
+			[]interface{}{entity.KomisiJenisPembatalan, entity.KomisiStatusTertahan, 9},
+		},
+		{
+			"completed melepas entri tertahan",
+			func(l *komisiLedger, tx *gorm.DB) error { return l.completed(context.Background(), tx, 9) },
+			"UPDATE `komisi_reseller` SET `status`=?,`tersedia_pada`=NOW() WHERE id_trx = ? AND status = ?",
+			[]interface{}{entity.KomisiStatusTersedia, 9, entity.KomisiStatusTertahan},
+		},
+		{
+			"retur langsung tersedia bila trx completed",
+			func(l *komisiLedger, tx *gorm.DB) error { return l.retur(context.Background(), tx, 11) },
+			"INSERT INTO komisi_reseller .*IF(trx.status = ?, ?, ?)",
+			[]interface{}{entity.KomisiJenisRetur, entity.TrxStatusCompleted, entity.KomisiStatusTersedia, entity.KomisiStatusTertahan, entity.TrxStatusCompleted, 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlDB.Close()
+
+			db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+				Logger:                 logger.Default.LogMode(logger.Silent),
+				SkipDefaultTransaction: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// ".*" di pola memisahkan potongan query yang dicocokkan apa adanya
+			parts := strings.Split(tt.query, ".*")
+			for i := range parts {
+				parts[i] = regexp.QuoteMeta(parts[i])
+			}
+			args := make([]driver.Value, len(tt.args))
+			for i, a := range tt.args {
+				args[i] = a
+			}
+			mock.ExpectExec(strings.Join(parts, ".*")).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			if err := tt.step(newKomisiLedger(repository.NewKomisiRepo(db)), db); err != nil {
+				t.Fatal(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestKomisiLedger menjalankan buku komisi reseller lewat query aslinya: margin per unit dari
+// snapshot harga (tidak pernah negatif), pembalikan per toko yang idempoten, retur dan pelepasan.
+func TestKomisiLedger(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	seed := newTestSeed(t, db)
+
+	tokoA := seed.toko(seed.user(), "3171")
+	tokoB := seed.toko(seed.user(), "3171")
+	// margin 2000, margin negatif dianggap 0, margin 2500
+	produkA := seed.produk(tokoA, 8000, 10000, 10)
+	produkB := seed.produk(tokoB, 12000, 11000, 10)
+	produkC := seed.produk(tokoB, 5000, 7500, 10)
+
+	trxUsc, _ := newTestTransactionUsecase(db)
+	komisiRepo := repository.NewKomisiRepo(db)
+	ledger := newKomisiLedger(komisiRepo)
+	komisiUsc := NewKomisiUsecase(komisiRepo)
+
+	checkout := func(t *testing.T, resellerID int) int {
+		t.Helper()
+
+		id, herr := trxUsc.CreateTransaction(ctx, resellerID, "", &models.CreateTrxRequest{
+			MethodBayar: "ovo",
+			Dropship: &models.DropshipRequest{
+				NamaPenerima: "penerima",
+				NoTelp:       "0811",
+				DetailAlamat: "jalan uji",
+				IDKota:       "3171",
+				NamaPengirim: "reseller",
+				TelpPengirim: "0812",
+			},
+			DetailTrx: []models.CreateTrxItemRequest{
+				{ProductID: produkA, Kuantitas: 3},
+				{ProductID: produkB, Kuantitas: 2},
+				{ProductID: produkC, Kuantitas: 1},
+			},
+			Pengiriman: []models.CreateTrxShippingRequest{
+				{IDToko: tokoA, Kurir: "sicepat", Layanan: "REG"},
+				{IDToko: tokoB, Kurir: "sicepat", Layanan: "REG"},
+			},
+		})
+		if herr != nil {
+			t.Fatalf("checkout: %v", herr.Err)
+		}
+		return id
+	}
+
+	step := func(t *testing.T, name string, fn func(tx *gorm.DB) error) {
+		t.Helper()
+		if err := db.Transaction(fn); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	expectSaldo := func(t *testing.T, name string, resellerID int, tertahan, tersedia money.Rupiah) {
+		t.Helper()
+
+		saldo, herr := komisiUsc.Saldo(ctx, resellerID)
+		if herr != nil {
+			t.Fatalf("%s: saldo: %v", name, herr.Err)
+		}
+		if saldo.Tertahan != tertahan || saldo.Tersedia != tersedia || saldo.Total != tertahan+tersedia {
+			t.Fatalf("%s: saldo = %+v, want tertahan %d tersedia %d", name, *saldo, tertahan, tersedia)
+		}
+	}
+
+	t.Run("ditolak, diretur lalu selesai", func(t *testing.T) {
+		resellerID := seed.user()
+		trxID := checkout(t, resellerID)
+
+		step(t, "paid", func(tx *gorm.DB) error { return ledger.paid(ctx, tx, trxID) })
+		expectSaldo(t, "paid", resellerID, 3*2000+0+2500, 0)
+
+		step(t, "rejected", func(tx *gorm.DB) error { return ledger.rejected(ctx, tx, trxID, tokoB) })
+		expectSaldo(t, "rejected", resellerID, 3*2000, 0)
+
+		step(t, "rejected ulang", func(tx *gorm.DB) error { return ledger.rejected(ctx, tx, trxID, tokoB) })
+		expectSaldo(t, "rejected ulang", resellerID, 3*2000, 0)
+
+		var detailA int
+		err := db.Raw(`SELECT detail_trx.id FROM detail_trx
+			JOIN log_produk ON log_produk.id = detail_trx.id_log_produk
+			WHERE detail_trx.id_trx = ? AND log_produk.id_produk = ?`, trxID, produkA).Scan(&detailA).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		returID := seed.exec("INSERT INTO retur (id_trx, id_toko, id_user, alasan, status) VALUES (?, ?, ?, ?, ?)",
+			trxID, tokoA, resellerID, "rusak", "approved")
+		seed.exec("INSERT INTO retur_item (id_retur, id_detail_trx, kuantitas, jumlah) VALUES (?, ?, ?, ?)",
+			returID, detailA, 1, 8000)
+
+		step(t, "retur", func(tx *gorm.DB) error { return ledger.retur(ctx, tx, returID) })
+		expectSaldo(t, "retur", resellerID, 2*2000, 0)
+
+		step(t, "completed", func(tx *gorm.DB) error { return ledger.completed(ctx, tx, trxID) })
+		expectSaldo(t, "completed", resellerID, 0, 2*2000)
+	})
+
+	t.Run("dibatalkan setelah dibayar", func(t *testing.T) {
+		resellerID := seed.user()
+		trxID := checkout(t, resellerID)
+
+		step(t, "paid", func(tx *gorm.DB) error { return ledger.paid(ctx, tx, trxID) })
+		step(t, "rejected", func(tx *gorm.DB) error { return ledger.rejected(ctx, tx, trxID, tokoA) })
+		step(t, "cancelled", func(tx *gorm.DB) error { return ledger.cancelled(ctx, tx, trxID) })
+		expectSaldo(t, "cancelled", resellerID, 0, 0)
+
+		// toko A sudah nol sebelum pembatalan, hanya baris toko B yang dibalik
+		var pembatalan []entity.KomisiReseller
+		err := db.Where("id_trx = ? AND jenis = ?", trxID, entity.KomisiJenisPembatalan).Order("id").Find(&pembatalan).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pembatalan) != 3 {
+			t.Fatalf("entri pembatalan = %d, want 3", len(pembatalan))
+		}
+		for _, e := range pembatalan[1:] {
+			if e.IDToko != tokoB {
+				t.Errorf("pembatalan membalik toko %d, want %d", e.IDToko, tokoB)
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+)
+
+// EventLogSubscriber nama subscriber jejak audit, tercatat di outbox_terkirim
+const EventLogSubscriber = "event-log"
+
+// LogEvent mencatat setiap event domain ke log aplikasi sebagai jejak audit.
+// Payload tidak ikut dicatat karena bisa berisi data pembeli.
+func LogEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	helper.LogInfo(fmt.Sprintf("event %d %s %s/%d", event.ID, event.Jenis, event.Agregat, event.IDAgregat))
+	return nil
+}
@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOutboxReplayFilter = errors.New("isi minimal satu filter replay")
+	ErrOutboxStatus       = errors.New("status harus pending, delivered atau failed")
+	ErrOutboxJenis        = errors.New("jenis event tidak dikenal")
+)
+
+const (
+	// outboxLease lama event yang sedang dikirim tidak diambil dispatcher lain
+	outboxLease = time.Minute
+	// jeda percobaan ulang berlipat dua dari outboxRetryBase sampai outboxRetryMax
+	outboxRetryBase = 10 * time.Second
+	outboxRetryMax  = time.Hour
+	// outboxPurgeBatch jumlah event delivered yang dihapus per transaksi retensi
+	outboxPurgeBatch = 500
+)
+
+var outboxJenis = map[string]bool{
+	entity.EventTransactionCreated:       true,
+	entity.EventTransactionStatusChanged: true,
+	entity.EventProductStockChanged:      true,
+	entity.EventProductPriceChanged:      true,
+}
+
+// EventHandler subscriber in-process. Event bisa diterima lebih dari sekali sehingga
+// handler harus idempoten, error membuat event dicoba ulang untuk subscriber ini saja.
+type EventHandler func(ctx context.Context, event *entity.OutboxEvent) error
+
+type OutboxUsecase interface {
+	Subscribe(name string, handler EventHandler, jenis ...string)
+	HasSubscribers() bool
+	Dispatch(ctx context.Context, limit int) (int, *helper.ErrorStruct)
+	Purge(ctx context.Context) (int64, *helper.ErrorStruct)
+	Replay(ctx context.Context, filter *entity.OutboxReplayFilter) (int64, *helper.ErrorStruct)
+}
+
+type outboxSubscriber struct {
+	name    string
+	jenis   map[string]bool
+	handler EventHandler
+}
+
+// handles jenis kosong berarti semua event
+func (s *outboxSubscriber) handles(jenis string) bool {
+	return len(s.jenis) == 0 || s.jenis[jenis]
+}
+
+func (s *outboxSubscriber) call(ctx context.Context, event *entity.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handler(ctx, event)
+}
+
+type outboxImpl struct {
+	db          *gorm.DB
+	repo        repository.OutboxRepository
+	maxAttempts int
+	retention   time.Duration
+
+	mu          sync.RWMutex
+	subscribers []*outboxSubscriber
+}
+
+// NewOutboxUsecase maxAttempts batas percobaan sebelum event ditandai failed,
+// retention lama event delivered disimpan sebelum dihapus Purge
+func NewOutboxUsecase(db *gorm.DB, repo repository.OutboxRepository, maxAttempts int, retention time.Duration) OutboxUsecase {
+	return &outboxImpl{
+		db:          db,
+		repo:        repo,
+		maxAttempts: maxAttempts,
+		retention:   retention,
+	}
+}
+
+// Subscribe mendaftarkan subscriber untuk jenis event tertentu, tanpa jenis berarti semua.
+// Nama dicatat per event yang berhasil dikirim sehingga harus tetap sama antar deploy.
+func (o *outboxImpl) Subscribe(name string, handler EventHandler, jenis ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, s := range o.subscribers {
+		if s.name == name {
+			panic("outbox: subscriber " + name + " sudah terdaftar")
+		}
+	}
+
+	sub := &outboxSubscriber{name: name, handler: handler, jenis: make(map[string]bool, len(jenis))}
+	for _, j := range jenis {
+		sub.jenis[j] = true
+	}
+	o.subscribers = append(o.subscribers, sub)
+}
+
+func (o *outboxImpl) HasSubscribers() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return len(o.subscribers) > 0
+}
+
+// subscribedJenis jenis event yang punya subscriber, all true bila ada subscriber untuk semua jenis
+func subscribedJenis(subs []*outboxSubscriber) (jenis []string, all bool) {
+	seen := make(map[string]bool)
+	for _, s := range subs {
+		if len(s.jenis) == 0 {
+			return nil, true
+		}
+		for j := range s.jenis {
+			if !seen[j] {
+				seen[j] = true
+				jenis = append(jenis, j)
+			}
+		}
+	}
+	sort.Strings(jenis)
+	return jenis, false
+}
+
+// Dispatch mengirim event pending yang sudah waktunya ke subscriber, dipanggil berkala oleh worker.
+// Mengembalikan jumlah event yang diambil. Event yang gagal tidak menghentikan event lain,
+// error pertama dikembalikan. Event tanpa subscriber untuk jenisnya tidak diambil dan tetap pending.
+func (o *outboxImpl) Dispatch(ctx context.Context, limit int) (int, *helper.ErrorStruct) {
+	o.mu.RLock()
+	subs := append([]*outboxSubscriber(nil), o.subscribers...)
+	o.mu.RUnlock()
+
+	jenis, all := subscribedJenis(subs)
+	if !all && len(jenis) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+
+	var events []entity.OutboxEvent
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		events, err = o.repo.ListDueForUpdate(ctx, tx, jenis, now, limit)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return o.repo.Lease(ctx, tx, ids, now.Add(outboxLease))
+	})
+	if err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+
+	terkirim, err := o.repo.ListTerkirim(ctx, ids)
+	if err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	done := make(map[int64]map[string]bool, len(events))
+	for _, t := range terkirim {
+		if done[t.IDEvent] == nil {
+			done[t.IDEvent] = make(map[string]bool)
+		}
+		done[t.IDEvent][t.Subscriber] = true
+	}
+
+	var firstErr error
+	for i := range events {
+		if err := o.deliver(ctx, &events[i], subs, done[events[i].ID]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return len(events), &helper.ErrorStruct{Err: firstErr, Code: 500}
+	}
+	return len(events), nil
+}
+
+// deliver memanggil subscriber yang belum menerima event. Event selesai setelah semua berhasil,
+// selain itu dijadwalkan ulang dengan jeda berlipat atau ditandai failed bila batas percobaan habis.
+// Event tanpa subscriber dibiarkan pending dan diambil lagi setelah sewanya habis.
+func (o *outboxImpl) deliver(ctx context.Context, event *entity.OutboxEvent, subs []*outboxSubscriber, done map[string]bool) error {
+	var handled int
+	var failed []string
+	for _, s := range subs {
+		if !s.handles(event.Jenis) {
+			continue
+		}
+		handled++
+		if done[s.name] {
+			continue
+		}
+		if err := s.call(ctx, event); err != nil {
+			failed = append(failed, s.name+": "+err.Error())
+			continue
+		}
+		if err := o.repo.AddTerkirim(ctx, event.ID, s.name); err != nil {
+			return err
+		}
+	}
+
+	if handled == 0 {
+		return nil
+	}
+
+	if len(failed) == 0 {
+		return o.repo.MarkDelivered(ctx, event.ID, time.Now())
+	}
+
+	percobaan := event.Percobaan + 1
+	status := entity.OutboxStatusPending
+	if percobaan >= o.maxAttempts {
+		status = entity.OutboxStatusFailed
+	}
+
+	msg := strings.Join(failed, "; ")
+	if err := o.repo.MarkRetry(ctx, event.ID, percobaan, status, msg, time.Now().Add(outboxBackoff(percobaan))); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("event %d %s: %s", event.ID, event.Jenis, msg)
+}
+
+func outboxBackoff(percobaan int) time.Duration {
+	d := outboxRetryBase
+	for i := 1; i < percobaan && d < outboxRetryMax; i++ {
+		d *= 2
+	}
+	if d > outboxRetryMax {
+		d = outboxRetryMax
+	}
+	return d
+}
+
+// Purge menghapus event delivered yang lebih lama dari masa retensi beserta tanda terkirimnya,
+// per batch sampai habis. Event failed tetap disimpan untuk replay.
+func (o *outboxImpl) Purge(ctx context.Context) (int64, *helper.ErrorStruct) {
+	before := time.Now().Add(-o.retention)
+
+	var total int64
+	for ctx.Err() == nil {
+		var n int64
+		err := o.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			n, err = o.repo.DeleteDelivered(ctx, tx, before, outboxPurgeBatch)
+			return err
+		})
+		if err != nil {
+			return total, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+
+		total += n
+		if n < outboxPurgeBatch {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// Replay mengembalikan event ke pending supaya dikirim lagi. Dengan filter Subscriber hanya
+// subscriber tersebut yang menerima ulang, subscriber lain tetap tercatat sudah menerima.
+func (o *outboxImpl) Replay(ctx context.Context, filter *entity.OutboxReplayFilter) (int64, *helper.ErrorStruct) {
+	if filter.DariID == 0 && filter.SampaiID == 0 && filter.Jenis == "" && filter.Status == "" &&
+		filter.Subscriber == "" && filter.Dari == nil && filter.Sampai == nil {
+		return 0, &helper.ErrorStruct{Err: ErrOutboxReplayFilter, Code: 400}
+	}
+
+	switch filter.Status {
+	case "", entity.OutboxStatusPending, entity.OutboxStatusDelivered, entity.OutboxStatusFailed:
+	default:
+		return 0, &helper.ErrorStruct{Err: ErrOutboxStatus, Code: 400}
+	}
+
+	if filter.Jenis != "" && !outboxJenis[filter.Jenis] {
+		return 0, &helper.ErrorStruct{Err: ErrOutboxJenis, Code: 400}
+	}
+
+	var n int64
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		// tanda terkirim dihapus lebih dulu, Reset mengubah status yang ikut difilter
+		if err := o.repo.DeleteTerkirim(ctx, tx, filter); err != nil {
+			return err
+		}
+
+		var err error
+		n, err = o.repo.Reset(ctx, tx, filter, time.Now())
+		return err
+	})
+
+	if err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return n, nil
+}
+
+// eventOutbox menulis event domain ke outbox di dalam db transaction pemanggil,
+// sehingga event hanya ada bila perubahannya ikut di-commit
+type eventOutbox struct {
+	repo repository.OutboxRepository
+}
+
+func newEventOutbox(repo repository.OutboxRepository) *eventOutbox {
+	return &eventOutbox{
+		repo: repo,
+	}
+}
+
+func (e *eventOutbox) publish(ctx context.Context, tx *gorm.DB, jenis string, agregat string, id int, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return e.repo.Create(ctx, tx, &entity.OutboxEvent{
+		Jenis:        jenis,
+		Agregat:      agregat,
+		IDAgregat:    id,
+		Payload:      string(b),
+		Status:       entity.OutboxStatusPending,
+		TersediaPada: time.Now(),
+	})
+}
+
+func (e *eventOutbox) trxCreated(ctx context.Context, tx *gorm.DB, payload *entity.TransactionCreatedPayload) error {
+	return e.publish(ctx, tx, entity.EventTransactionCreated, entity.OutboxAgregatTrx, payload.IDTrx, payload)
+}
+
+func (e *eventOutbox) trxStatusChanged(ctx context.Context, tx *gorm.DB, history *entity.TrxStatusHistory) error {
+	return e.publish(ctx, tx, entity.EventTransactionStatusChanged, entity.OutboxAgregatTrx, history.IDTrx, &entity.TransactionStatusChangedPayload{
+		IDTrx:      history.IDTrx,
+		DariStatus: history.DariStatus,
+		KeStatus:   history.KeStatus,
+		Peran:      history.Peran,
+		DiubahOleh: history.DiubahOleh,
+		Catatan:    history.Catatan,
+	})
+}
+
+// stockChanged dipanggil setelah stok atau stok_ditahan produk berubah, trxID 0 bila bukan dari pesanan
+func (e *eventOutbox) stockChanged(ctx context.Context, tx *gorm.DB, produkID int, sebab string, trxID int) error {
+	stok, err := e.repo.StokProduk(ctx, tx, produkID)
+	if err != nil {
+		return err
+	}
+
+	return e.publish(ctx, tx, entity.EventProductStockChanged, entity.OutboxAgregatProduk, produkID, &entity.ProductStockChangedPayload{
+		IDProduk:     stok.ID,
+		Stok:         stok.Stok,
+		StokDitahan:  stok.StokDitahan,
+		StokTersedia: stok.Stok - stok.StokDitahan,
+		Sebab:        sebab,
+		IDTrx:        trxID,
+	})
+}
+
+// priceChanged hanya menulis event bila harga reseller atau konsumen berbeda
+func (e *eventOutbox) priceChanged(ctx context.Context, tx *gorm.DB, before *entity.Produk, after *entity.Produk) error {
+	if before.HargaReseller == after.HargaReseller && before.HargaKonsumen == after.HargaKonsumen {
+		return nil
+	}
+
+	return e.publish(ctx, tx, entity.EventProductPriceChanged, entity.OutboxAgregatProduk, after.ID, &entity.ProductPriceChangedPayload{
+		IDProduk:          after.ID,
+		HargaResellerLama: before.HargaReseller,
+		HargaReseller:     after.HargaReseller,
+		HargaKonsumenLama: before.HargaKonsumen,
+		HargaKonsumen:     after.HargaKonsumen,
+	})
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		percobaan int
+		want      time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{6, 320 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.percobaan); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.percobaan, got, tt.want)
+		}
+	}
+}
+
+func TestSubscribedJenis(t *testing.T) {
+	sub := func(jenis ...string) *outboxSubscriber {
+		s := &outboxSubscriber{jenis: make(map[string]bool)}
+		for _, j := range jenis {
+			s.jenis[j] = true
+		}
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		subs      []*outboxSubscriber
+		wantJenis []string
+		wantAll   bool
+	}{
+		{"tanpa subscriber", nil, nil, false},
+		{"satu jenis", []*outboxSubscriber{sub(entity.EventTransactionCreated)}, []string{entity.EventTransactionCreated}, false},
+		{
+			"gabungan tanpa duplikat",
+			[]*outboxSubscriber{sub(entity.EventProductStockChanged, entity.EventTransactionCreated), sub(entity.EventTransactionCreated)},
+			[]string{entity.EventProductStockChanged, entity.EventTransactionCreated},
+			false,
+		},
+		{"subscriber semua jenis", []*outboxSubscriber{sub(entity.EventTransactionCreated), sub()}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jenis, all := subscribedJenis(tt.subs)
+			if all != tt.wantAll || !reflect.DeepEqual(jenis, tt.wantJenis) {
+				t.Errorf("subscribedJenis = %v, %v, want %v, %v", jenis, all, tt.wantJenis, tt.wantAll)
+			}
+		})
+	}
+}
+
+// fakeOutboxRepo mencatat perubahan status event dari deliver
+type fakeOutboxRepo struct {
+	repository.OutboxRepository
+	terkirim  []string
+	delivered []int64
+	retry     map[int64]string
+	created   []string
+}
+
+func (f *fakeOutboxRepo) Create(ctx context.Context, tx *gorm.DB, event *entity.OutboxEvent) error {
+	f.created = append(f.created, event.Jenis)
+	return nil
+}
+
+func (f *fakeOutboxRepo) AddTerkirim(ctx context.Context, eventID int64, subscriber string) error {
+	f.terkirim = append(f.terkirim, subscriber)
+	return nil
+}
+
+func (f *fakeOutboxRepo) MarkDelivered(ctx context.Context, eventID int64, at time.Time) error {
+	f.delivered = append(f.delivered, eventID)
+	return nil
+}
+
+func (f *fakeOutboxRepo) MarkRetry(ctx context.Context, eventID int64, percobaan int, status string, errMsg string, next time.Time) error {
+	if f.retry == nil {
+		f.retry = make(map[int64]string)
+	}
+	f.retry[eventID] = status
+	return nil
+}
+
+func TestOutboxDeliver(t *testing.T) {
+	ok := func(context.Context, *entity.OutboxEvent) error { return nil }
+	gagal := func(context.Context, *entity.OutboxEvent) error { return errors.New("gagal") }
+
+	tests := []struct {
+		name          string
+		subs          map[string]EventHandler
+		jenis         map[string][]string
+		percobaan     int
+		done          map[string]bool
+		wantTerkirim  []string
+		wantDelivered bool
+		wantRetry     string
+	}{
+		{
+			name: "tanpa subscriber tetap pending",
+		},
+		{
+			name:  "subscriber jenis lain tetap pending",
+			subs:  map[string]EventHandler{"stok": ok},
+			jenis: map[string][]string{"stok": {entity.EventProductStockChanged}},
+		},
+		{
+			name:          "semua subscriber berhasil",
+			subs:          map[string]EventHandler{"a": ok},
+			wantTerkirim:  []string{"a"},
+			wantDelivered: true,
+		},
+		{
+			name:          "subscriber yang sudah menerima dilewati",
+			subs:          map[string]EventHandler{"a": gagal},
+			done:          map[string]bool{"a": true},
+			wantDelivered: true,
+		},
+		{
+			name:      "gagal dijadwalkan ulang",
+			subs:      map[string]EventHandler{"a": gagal},
+			wantRetry: entity.OutboxStatusPending,
+		},
+		{
+			name:      "gagal di percobaan terakhir",
+			subs:      map[string]EventHandler{"a": gagal},
+			percobaan: 2,
+			wantRetry: entity.OutboxStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepo{}
+			o := &outboxImpl{repo: repo, maxAttempts: 3}
+			for name, h := range tt.subs {
+				o.Subscribe(name, h, tt.jenis[name]...)
+			}
+
+			event := &entity.OutboxEvent{ID: 1, Jenis: entity.EventTransactionCreated, Percobaan: tt.percobaan}
+			err := o.deliver(context.Background(), event, o.subscribers, tt.done)
+			if (err != nil) != (tt.wantRetry != "") {
+				t.Fatalf("deliver error = %v", err)
+			}
+
+			if !reflect.DeepEqual(repo.terkirim, tt.wantTerkirim) {
+				t.Errorf("terkirim = %v, want %v", repo.terkirim, tt.wantTerkirim)
+			}
+			if got := len(repo.delivered) > 0; got != tt.wantDelivered {
+				t.Errorf("delivered = %v, want %v", got, tt.wantDelivered)
+			}
+			if got := repo.retry[event.ID]; got != tt.wantRetry {
+				t.Errorf("retry status = %q, want %q", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+// TestEventLogSubscriber subscriber yang didaftarkan container menerima semua jenis event,
+// sehingga tidak ada event yang tertahan pending
+func TestEventLogSubscriber(t *testing.T) {
+	for jenis := range outboxJenis {
+		t.Run(jenis, func(t *testing.T) {
+			repo := &fakeOutboxRepo{}
+			o := &outboxImpl{repo: repo, maxAttempts: 3}
+			o.Subscribe(EventLogSubscriber, LogEvent)
+
+			event := &entity.OutboxEvent{ID: 7, Jenis: jenis, Agregat: entity.OutboxAgregatTrx, IDAgregat: 3}
+			if err := o.deliver(context.Background(), event, o.subscribers, nil); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(repo.terkirim, []string{EventLogSubscriber}) || len(repo.delivered) != 1 {
+				t.Errorf("terkirim = %v delivered = %v, want event-log dan delivered", repo.terkirim, repo.delivered)
+			}
+		})
+	}
+}
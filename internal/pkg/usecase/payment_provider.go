@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"pbi/internal/config"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"strconv"
+)
+
+var ErrInvalidSignature = errors.New("signature callback tidak valid")
+
+// PaymentIntent hasil pembuatan pembayaran di sisi provider
+type PaymentIntent struct {
+	PaymentURL string
+	Instruksi  string
+}
+
+// PaymentProvider satu implementasi per metode bayar online (ovo, dana, gopay).
+// COD tidak memakai provider, pesanannya dikonfirmasi saat checkout.
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, payment *entity.Payment) (*PaymentIntent, error)
+	ParseCallback(body []byte, signature string) (*models.PaymentCallbackRequest, error)
+}
+
+// NewPaymentProviders membuat registry provider berdasarkan metode bayar.
+// Dengan PAYMENT_MODE=mock semua metode diarahkan ke provider lokal. Di luar mode mock hanya
+// provider yang URL dan secret-nya diisi yang didaftarkan, metode lain ditolak saat Start.
+func NewPaymentProviders(cfg config.PaymentConfig) map[string]PaymentProvider {
+	methods := []string{"ovo", "dana", "gopay"}
+
+	if cfg.Mode == config.PaymentModeMock {
+		providers := make(map[string]PaymentProvider, len(methods))
+		for _, m := range methods {
+			providers[m] = NewMockPaymentProvider(m, cfg.PublicURL, cfg.MockSecret)
+		}
+		return providers
+	}
+
+	providers := make(map[string]PaymentProvider, len(methods))
+	if cfg.OvoURL != "" && cfg.OvoSecret != "" {
+		providers["ovo"] = NewOvoProvider(cfg.OvoURL, cfg.OvoSecret)
+	}
+	if cfg.DanaURL != "" && cfg.DanaSecret != "" {
+		providers["dana"] = NewDanaProvider(cfg.DanaURL, cfg.DanaSecret)
+	}
+	if cfg.GopayURL != "" && cfg.GopaySecret != "" {
+		providers["gopay"] = NewGopayProvider(cfg.GopayURL, cfg.GopaySecret)
+	}
+	return providers
+}
+
+// callbackVerifier memverifikasi webhook yang ditandatangani HMAC-SHA256 (hex)
+type callbackVerifier struct {
+	secret string
+}
+
+func (v callbackVerifier) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(v.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (v callbackVerifier) ParseCallback(body []byte, signature string) (*models.PaymentCallbackRequest, error) {
+	if v.secret == "" || !hmac.Equal([]byte(v.sign(body)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var cb models.PaymentCallbackRequest
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, err
+	}
+
+	if cb.Referensi == "" {
+		return nil, errors.New("referensi pembayaran kosong")
+	}
+
+	return &cb, nil
+}
+
+func checkoutURL(base string, payment *entity.Payment) string {
+	q := url.Values{}
+	q.Set("ref", payment.Referensi)
+	q.Set("amount", strconv.FormatInt(int64(payment.Jumlah), 10))
+	return base + "?" + q.Encode()
+}
+
+// Provider wallet di bawah ini masih stub: belum memanggil API wallet, hanya mengarahkan pembeli
+// ke halaman checkout di *_URL dengan referensi dan jumlah, lalu menerima callback bertanda tangan.
+
+// OVO (stub): pembayaran dikonfirmasi lewat notifikasi di aplikasi OVO
+type ovoProvider struct {
+	callbackVerifier
+	baseURL string
+}
+
+func NewOvoProvider(baseURL string, secret string) PaymentProvider {
+	return &ovoProvider{callbackVerifier: callbackVerifier{secret: secret}, baseURL: baseURL}
+}
+
+func (p *ovoProvider) Name() string { return "ovo" }
+
+func (p *ovoProvider) CreateIntent(ctx context.Context, payment *entity.Payment) (*PaymentIntent, error) {
+	return &PaymentIntent{
+		PaymentURL: checkoutURL(p.baseURL, payment),
+		Instruksi:  "Buka aplikasi OVO dan konfirmasi notifikasi pembayaran " + payment.Referensi,
+	}, nil
+}
+
+// DANA (stub): pembeli diarahkan ke halaman checkout DANA
+type danaProvider struct {
+	callbackVerifier
+	baseURL string
+}
+
+func NewDanaProvider(baseURL string, secret string) PaymentProvider {
+	return &danaProvider{callbackVerifier: callbackVerifier{secret: secret}, baseURL: baseURL}
+}
+
+func (p *danaProvider) Name() string { return "dana" }
+
+func (p *danaProvider) CreateIntent(ctx context.Context, payment *entity.Payment) (*PaymentIntent, error) {
+	return &PaymentIntent{
+		PaymentURL: checkoutURL(p.baseURL, payment),
+	}, nil
+}
+
+// GoPay (stub): deeplink ke aplikasi Gojek / QRIS
+type gopayProvider struct {
+	callbackVerifier
+	baseURL string
+}
+
+func NewGopayProvider(baseURL string, secret string) PaymentProvider {
+	return &gopayProvider{callbackVerifier: callbackVerifier{secret: secret}, baseURL: baseURL}
+}
+
+func (p *gopayProvider) Name() string { return "gopay" }
+
+func (p *gopayProvider) CreateIntent(ctx context.Context, payment *entity.Payment) (*PaymentIntent, error) {
+	return &PaymentIntent{
+		PaymentURL: checkoutURL(p.baseURL, payment),
+		Instruksi:  "Scan QR atau buka tautan untuk membayar dengan GoPay",
+	}, nil
+}
+
+// MockPaymentProvider provider lokal untuk menjalankan alur bayar end-to-end
+type MockPaymentProvider struct {
+	callbackVerifier
+	name      string
+	publicURL string
+}
+
+func NewMockPaymentProvider(name string, publicURL string, secret string) *MockPaymentProvider {
+	return &MockPaymentProvider{
+		callbackVerifier: callbackVerifier{secret: secret},
+		name:             name,
+		publicURL:        publicURL,
+	}
+}
+
+func (p *MockPaymentProvider) Name() string { return p.name }
+
+func (p *MockPaymentProvider) CreateIntent(ctx context.Context, payment *entity.Payment) (*PaymentIntent, error) {
+	return &PaymentIntent{
+		PaymentURL: fmt.Sprintf("%s/api/v1/payments/mock/%s/pay", p.publicURL, payment.Referensi),
+		Instruksi:  "Mock payment: POST ke payment_url untuk mensimulasikan pembayaran " + p.name,
+	}, nil
+}
+
+// SignCallback membuat signature seperti yang dikirim provider asli
+func (p *MockPaymentProvider) SignCallback(body []byte) string {
+	return p.sign(body)
+}
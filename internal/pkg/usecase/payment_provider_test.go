@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"pbi/internal/config"
+)
+
+func TestNewPaymentProviders(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.PaymentConfig
+		want []string
+	}{
+		{"mock", config.PaymentConfig{Mode: config.PaymentModeMock}, []string{"dana", "gopay", "ovo"}},
+		{"live tanpa konfigurasi", config.PaymentConfig{Mode: "live"}, []string{}},
+		{"live url tanpa secret", config.PaymentConfig{Mode: "live", OvoURL: "https://ovo.test"}, []string{}},
+		{
+			"live sebagian",
+			config.PaymentConfig{Mode: "live", OvoURL: "https://ovo.test", OvoSecret: "s", GopayURL: "https://gopay.test", GopaySecret: "s"},
+			[]string{"gopay", "ovo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for name, p := range NewPaymentProviders(tt.cfg) {
+				if p.Name() != name {
+					t.Errorf("provider %s bernama %s", name, p.Name())
+				}
+				got = append(got, name)
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("provider = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPaymentReference(t *testing.T) {
+	a, err := newPaymentReference(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newPaymentReference(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a) != len("PAY-12-")+8 || a[:7] != "PAY-12-" {
+		t.Errorf("referensi = %q", a)
+	}
+	if a == b {
+		t.Errorf("referensi berulang: %q", a)
+	}
+}
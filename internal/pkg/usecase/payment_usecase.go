@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound       = errors.New("pembayaran tidak ditemukan")
+	ErrPaymentProvider       = errors.New("provider pembayaran tidak dikenal")
+	ErrPaymentAmountMismatch = errors.New("jumlah pembayaran tidak sesuai")
+	ErrTrxNotAwaitingPayment = errors.New("transaksi tidak sedang menunggu pembayaran")
+)
+
+type PaymentUsecase interface {
+	Start(ctx context.Context, trxID int, userID int) (*models.PaymentResponse, *helper.ErrorStruct)
+	HandleCallback(ctx context.Context, provider string, body []byte, signature string) *helper.ErrorStruct
+	SimulateMock(ctx context.Context, referensi string, status string) *helper.ErrorStruct
+}
+
+type paymentImpl struct {
+	db        *gorm.DB
+	repo      repository.PaymentRepository
+	trxRepo   repository.TransactionRepository
+	providers map[string]PaymentProvider
+	status    *TrxStatusMachine
+}
+
+func NewPaymentUsecase(db *gorm.DB, repo repository.PaymentRepository, trxRepo repository.TransactionRepository, status *TrxStatusMachine, providers map[string]PaymentProvider) PaymentUsecase {
+	return &paymentImpl{
+		db:        db,
+		repo:      repo,
+		trxRepo:   trxRepo,
+		providers: providers,
+		status:    status,
+	}
+}
+
+// Start membuat payment intent untuk transaksi milik user.
+// Jika masih ada pembayaran pending, pembayaran tersebut dikembalikan lagi.
+func (p *paymentImpl) Start(ctx context.Context, trxID int, userID int) (*models.PaymentResponse, *helper.ErrorStruct) {
+	trx, err := p.trxRepo.GetTransactionByID(ctx, trxID, userID)
+	if err != nil {
+		return nil, trxError(err)
+	}
+
+	if trx.Status != entity.TrxStatusPendingPayment {
+		return nil, &helper.ErrorStruct{Err: ErrTrxNotAwaitingPayment, Code: 409}
+	}
+
+	provider, ok := p.providers[trx.MetodeBayar]
+	if !ok {
+		return nil, &helper.ErrorStruct{Err: ErrPaymentProvider, Code: 400}
+	}
+
+	existing, err := p.repo.FindPendingByTrx(ctx, trx.ID)
+	if err == nil {
+		return toPaymentResponse(existing), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	referensi, err := newPaymentReference(trx.ID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	payment := &entity.Payment{
+		IDTrx:     trx.ID,
+		Provider:  provider.Name(),
+		Referensi: referensi,
+		Jumlah:    trx.HargaTotal,
+		Status:    entity.PaymentStatusPending,
+	}
+
+	if err := p.repo.Create(ctx, p.db, payment); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	// provider dipanggil di luar db transaction supaya koneksi tidak tertahan selama request keluar,
+	// pembayaran yang gagal dibuat intent-nya ditandai failed agar Start berikutnya membuat yang baru
+	intent, err := provider.CreateIntent(ctx, payment)
+	if err != nil {
+		if uerr := p.repo.UpdateStatus(ctx, p.db, payment.ID, entity.PaymentStatusFailed); uerr != nil {
+			return nil, &helper.ErrorStruct{Err: uerr, Code: 500}
+		}
+		return nil, &helper.ErrorStruct{Err: err, Code: 502}
+	}
+
+	payment.PaymentURL = intent.PaymentURL
+	payment.Instruksi = intent.Instruksi
+	if err := p.repo.UpdateIntent(ctx, p.db, payment.ID, intent.PaymentURL, intent.Instruksi); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return toPaymentResponse(payment), nil
+}
+
+// HandleCallback memproses webhook provider dan menandai trx sebagai paid.
+// Callback berulang untuk pembayaran yang sudah final diabaikan. Dana yang diterima untuk trx
+// yang sudah tidak menunggu pembayaran tetap dicatat paid dan ditandai perlu_refund.
+func (p *paymentImpl) HandleCallback(ctx context.Context, providerName string, body []byte, signature string) *helper.ErrorStruct {
+	provider, ok := p.providers[providerName]
+	if !ok {
+		return &helper.ErrorStruct{Err: ErrPaymentProvider, Code: 404}
+	}
+
+	cb, err := provider.ParseCallback(body, signature)
+	if err != nil {
+		if errors.Is(err, ErrInvalidSignature) {
+			return &helper.ErrorStruct{Err: err, Code: 401}
+		}
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		payment, err := p.repo.FindByReferensiForUpdate(ctx, tx, cb.Referensi)
+		if err != nil {
+			return err
+		}
+
+		if payment.Provider != provider.Name() {
+			return ErrPaymentNotFound
+		}
+
+		if payment.Status != entity.PaymentStatusPending {
+			return nil
+		}
+
+		if cb.Status != entity.PaymentStatusPaid {
+			return p.repo.UpdateStatus(ctx, tx, payment.ID, entity.PaymentStatusFailed)
+		}
+
+		if cb.Jumlah != payment.Jumlah {
+			return ErrPaymentAmountMismatch
+		}
+
+		if err := p.repo.UpdateStatus(ctx, tx, payment.ID, entity.PaymentStatusPaid); err != nil {
+			return err
+		}
+
+		trx, err := p.trxRepo.GetTransactionForUpdate(ctx, tx, payment.IDTrx)
+		if err != nil {
+			return err
+		}
+
+		// trx sudah expired/dibatalkan: menolak callback berarti dana yang sudah ditangkap tidak tercatat
+		if trx.Status != entity.TrxStatusPendingPayment {
+			return p.repo.FlagRefund(ctx, tx, payment.ID, "pembayaran diterima saat transaksi "+trx.Status)
+		}
+
+		return p.status.change(ctx, tx, trx, entity.TrxStatusPaid, entity.TrxActorSystem, 0, "pembayaran "+payment.Referensi)
+	})
+
+	if err != nil {
+		return paymentError(err)
+	}
+
+	return nil
+}
+
+// SimulateMock mensimulasikan wallet yang mengirim callback bertanda tangan
+func (p *paymentImpl) SimulateMock(ctx context.Context, referensi string, status string) *helper.ErrorStruct {
+	payment, err := p.repo.FindByReferensi(ctx, referensi)
+	if err != nil {
+		return paymentError(err)
+	}
+
+	mock, ok := p.providers[payment.Provider].(*MockPaymentProvider)
+	if !ok {
+		return &helper.ErrorStruct{Err: errors.New("mock payment tidak aktif"), Code: 404}
+	}
+
+	if status == "" {
+		status = entity.PaymentStatusPaid
+	}
+
+	body, err := json.Marshal(models.PaymentCallbackRequest{
+		Referensi: payment.Referensi,
+		Status:    status,
+		Jumlah:    payment.Jumlah,
+	})
+	if err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return p.HandleCallback(ctx, payment.Provider, body, mock.SignCallback(body))
+}
+
+func newPaymentReference(trxID int) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("membuat referensi pembayaran: %w", err)
+	}
+	return fmt.Sprintf("PAY-%d-%s", trxID, hex.EncodeToString(b)), nil
+}
+
+func toPaymentResponse(payment *entity.Payment) *models.PaymentResponse {
+	return &models.PaymentResponse{
+		IDTrx:      payment.IDTrx,
+		Provider:   payment.Provider,
+		Referensi:  payment.Referensi,
+		Jumlah:     payment.Jumlah,
+		Status:     payment.Status,
+		PaymentURL: payment.PaymentURL,
+		Instruksi:  payment.Instruksi,
+	}
+}
+
+func paymentError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrPaymentNotFound):
+		return &helper.ErrorStruct{Err: ErrPaymentNotFound, Code: 404}
+	case errors.Is(err, ErrPaymentAmountMismatch):
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+	return trxError(err)
+}
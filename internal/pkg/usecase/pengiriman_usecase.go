@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PengirimanUsecase interface {
+	ListByTrx(ctx context.Context, trxID int, userID int, isAdmin bool) ([]models.PengirimanResponse, *helper.ErrorStruct)
+	PollTracking(ctx context.Context, limit int) (int, *helper.ErrorStruct)
+}
+
+type pengirimanImpl struct {
+	db        *gorm.DB
+	repo      repository.PengirimanRepository
+	trxRepo   repository.TransactionRepository
+	paketRepo repository.PaketRepository
+	provider  TrackingProvider
+	interval  time.Duration
+	status    *TrxStatusMachine
+}
+
+// NewPengirimanUsecase interval adalah jeda minimal antara dua pelacakan resi yang sama
+func NewPengirimanUsecase(db *gorm.DB, repo repository.PengirimanRepository, trxRepo repository.TransactionRepository, paketRepo repository.PaketRepository, status *TrxStatusMachine, provider TrackingProvider, interval time.Duration) PengirimanUsecase {
+	return &pengirimanImpl{
+		db:        db,
+		repo:      repo,
+		trxRepo:   trxRepo,
+		paketRepo: paketRepo,
+		provider:  provider,
+		interval:  interval,
+		status:    status,
+	}
+}
+
+// ListByTrx pengiriman setiap paket dalam trx, untuk pembeli, penjual di trx tersebut dan admin
+func (p *pengirimanImpl) ListByTrx(ctx context.Context, trxID int, userID int, isAdmin bool) ([]models.PengirimanResponse, *helper.ErrorStruct) {
+	trx, err := p.trxRepo.FindTransactionByID(ctx, trxID)
+	if err != nil {
+		return nil, trxError(err)
+	}
+
+	if !isAdmin && trx.IDUser != userID {
+		isSeller, err := p.trxRepo.IsSellerOfTransaction(ctx, p.db, trx.ID, userID)
+		if err != nil {
+			return nil, trxError(err)
+		}
+		if !isSeller {
+			return nil, trxError(ErrTransactionNotFound)
+		}
+	}
+
+	pengirimans, err := p.repo.ListByTrx(ctx, trx.ID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	ids := make([]int, 0, len(pengirimans))
+	for _, k := range pengirimans {
+		ids = append(ids, k.ID)
+	}
+
+	events, err := p.repo.ListEvents(ctx, ids)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	eventsByID := make(map[int][]models.PengirimanEventResponse, len(pengirimans))
+	for _, e := range events {
+		eventsByID[e.IDPengiriman] = append(eventsByID[e.IDPengiriman], models.PengirimanEventResponse{
+			Status:     e.Status,
+			Keterangan: e.Keterangan,
+			Lokasi:     e.Lokasi,
+			Waktu:      e.Waktu,
+		})
+	}
+
+	res := make([]models.PengirimanResponse, 0, len(pengirimans))
+	for _, k := range pengirimans {
+		evs := eventsByID[k.ID]
+		if evs == nil {
+			evs = []models.PengirimanEventResponse{}
+		}
+		res = append(res, models.PengirimanResponse{
+			ID:              k.ID,
+			IDPaket:         k.IDPaket,
+			IDToko:          k.IDToko,
+			Kurir:           k.Kurir,
+			NoResi:          k.NoResi,
+			Status:          k.Status,
+			TerakhirDilacak: k.TerakhirDilacak,
+			SampaiPada:      k.SampaiPada,
+			Events:          evs,
+		})
+	}
+
+	return res, nil
+}
+
+// PollTracking melacak resi yang jatuh tempo dan mencatat event barunya. Dipanggil berkala
+// oleh worker, mengembalikan jumlah resi yang dilacak. Resi yang gagal tetap ditandai sudah
+// dilacak supaya tidak diulang di putaran yang sama, error pertama dikembalikan.
+func (p *pengirimanImpl) PollTracking(ctx context.Context, limit int) (int, *helper.ErrorStruct) {
+	due, err := p.repo.ListDue(ctx, time.Now().Add(-p.interval), limit)
+	if err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	var firstErr error
+	for i := range due {
+		if err := p.track(ctx, &due[i]); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("resi %s: %w", due[i].NoResi, err)
+		}
+		if err := p.repo.MarkTracked(ctx, due[i].ID, time.Now()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return len(due), paketError(firstErr)
+	}
+	return len(due), nil
+}
+
+func (p *pengirimanImpl) track(ctx context.Context, pengiriman *entity.Pengiriman) error {
+	events, err := p.provider.Track(ctx, pengiriman.Kurir, pengiriman.NoResi)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows, sampai := trackingRows(pengiriman.ID, events)
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := p.repo.AddEvents(ctx, tx, rows); err != nil {
+			return err
+		}
+
+		if sampai == nil {
+			return nil
+		}
+
+		return p.delivered(ctx, tx, pengiriman, *sampai)
+	})
+}
+
+// trackingRows memetakan riwayat kurir ke baris pengiriman_event. sampai berisi waktu event
+// delivered pertama, nil bila paket belum sampai.
+func trackingRows(pengirimanID int, events []TrackingEvent) (rows []entity.PengirimanEvent, sampai *time.Time) {
+	rows = make([]entity.PengirimanEvent, 0, len(events))
+	for i := range events {
+		e := &events[i]
+		rows = append(rows, entity.PengirimanEvent{
+			IDPengiriman: pengirimanID,
+			Status:       e.Status,
+			Keterangan:   e.Keterangan,
+			Lokasi:       e.Lokasi,
+			Waktu:        e.Waktu,
+		})
+		if e.Status == entity.PengirimanEventDelivered && sampai == nil {
+			sampai = &e.Waktu
+		}
+	}
+	return rows, sampai
+}
+
+// delivered menandai paket sampai lalu menurunkan status trx, trx menjadi delivered setelah
+// semua paket yang tidak dibatalkan sampai. Paket yang sudah dipindahkan manual cukup ditandai.
+func (p *pengirimanImpl) delivered(ctx context.Context, tx *gorm.DB, pengiriman *entity.Pengiriman, at time.Time) error {
+	trx, err := p.trxRepo.GetTransactionForUpdate(ctx, tx, pengiriman.IDTrx)
+	if err != nil {
+		return err
+	}
+
+	pakets, err := p.paketRepo.ListByTrxForUpdate(ctx, tx, trx.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.repo.MarkDelivered(ctx, tx, pengiriman.ID, at); err != nil {
+		return err
+	}
+
+	var current *entity.PaketToko
+	for i := range pakets {
+		if pakets[i].ID == pengiriman.IDPaket {
+			current = &pakets[i]
+		}
+	}
+	if current == nil || current.Status != entity.TrxStatusShipped {
+		return nil
+	}
+
+	if err := p.paketRepo.UpdateStatus(ctx, tx, current.ID, current.Status, entity.TrxStatusDelivered, nil); err != nil {
+		return err
+	}
+	current.Status = entity.TrxStatusDelivered
+
+	catatan := fmt.Sprintf("paket %d sampai menurut %s (resi %s)", current.ID, pengiriman.Kurir, pengiriman.NoResi)
+	return p.status.derive(ctx, tx, trx, pakets, 0, catatan)
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pbi/internal/pkg/entity"
+)
+
+func TestTrackingRows(t *testing.T) {
+	t1 := time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC)
+	t2 := t1.Add(6 * time.Hour)
+	t3 := t2.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		events     []TrackingEvent
+		wantStatus []string
+		wantSampai *time.Time
+	}{
+		{"tanpa riwayat", nil, []string{}, nil},
+		{
+			"masih di perjalanan",
+			[]TrackingEvent{{Status: "picked_up", Waktu: t1}, {Status: "in_transit", Waktu: t2}},
+			[]string{"picked_up", "in_transit"},
+			nil,
+		},
+		{
+			"sampai",
+			[]TrackingEvent{{Status: "in_transit", Waktu: t1}, {Status: entity.PengirimanEventDelivered, Waktu: t2}},
+			[]string{"in_transit", entity.PengirimanEventDelivered},
+			&t2,
+		},
+		{
+			"delivered ganda memakai yang pertama",
+			[]TrackingEvent{{Status: entity.PengirimanEventDelivered, Waktu: t2}, {Status: entity.PengirimanEventDelivered, Waktu: t3}},
+			[]string{entity.PengirimanEventDelivered, entity.PengirimanEventDelivered},
+			&t2,
+		},
+		{
+			"status kurir lain tidak dianggap sampai",
+			[]TrackingEvent{{Status: "DELIVERED", Waktu: t1}, {Status: "failed_delivery", Waktu: t2}},
+			[]string{"DELIVERED", "failed_delivery"},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, sampai := trackingRows(42, tt.events)
+
+			if len(rows) != len(tt.wantStatus) {
+				t.Fatalf("rows = %d, want %d", len(rows), len(tt.wantStatus))
+			}
+			for i, r := range rows {
+				if r.IDPengiriman != 42 || r.Status != tt.wantStatus[i] || !r.Waktu.Equal(tt.events[i].Waktu) {
+					t.Errorf("rows[%d] = %+v, want status %s", i, r, tt.wantStatus[i])
+				}
+			}
+
+			switch {
+			case tt.wantSampai == nil && sampai != nil:
+				t.Errorf("sampai = %v, want nil", *sampai)
+			case tt.wantSampai != nil && (sampai == nil || !sampai.Equal(*tt.wantSampai)):
+				t.Errorf("sampai = %v, want %v", sampai, *tt.wantSampai)
+			}
+		})
+	}
+}
+
+func TestFileTrackingProvider(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "tracking.json")
+	rusak := filepath.Join(dir, "rusak.json")
+
+	err := os.WriteFile(valid, []byte(`{"JP001": [
+		{"status": "in_transit", "keterangan": "diproses", "lokasi": "Jakarta", "waktu": "2026-01-02T08:00:00+07:00"},
+		{"status": "delivered", "keterangan": "diterima", "lokasi": "Bandung", "waktu": "2026-01-03T10:00:00+07:00"}
+	]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rusak, []byte(`{"JP001": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		resi    string
+		want    int
+		wantErr bool
+	}{
+		{"resi ada", valid, "JP001", 2, false},
+		{"resi belum ada", valid, "JP002", 0, false},
+		{"file belum ada", filepath.Join(dir, "kosong.json"), "JP001", 0, false},
+		{"file rusak", rusak, "JP001", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := NewFileTrackingProvider(tt.path).Track(context.Background(), "jne", tt.resi)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Track error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(events) != tt.want {
+				t.Fatalf("events = %d, want %d", len(events), tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
+	"os"
 	"path/filepath"
 	"pbi/internal/helper"
 	"pbi/internal/pkg/entity"
@@ -15,6 +17,9 @@ import (
 	"github.com/gosimple/slug"
 )
 
+// ErrStokBelowReserved stok fisik baru lebih kecil dari stok yang sedang ditahan pesanan belum dibayar
+var ErrStokBelowReserved = errors.New("stok tidak boleh kurang dari stok yang ditahan pesanan belum dibayar")
+
 type ProductUsecase interface {
 	Create(ctx context.Context, req *models.ProductCreateReq, UserID int)(int, *helper.ErrorStruct)
 	Update(ctx context.Context, productID int, req *models.ProductCreateReq, userID int) *helper.ErrorStruct
@@ -26,14 +31,16 @@ type ProductUsecase interface {
 type produkusecaseImpl struct {
 	db   *gorm.DB
 	prepo repository.ProductRepository
+	events *eventOutbox
 }
 
 
 
-func NewProductUsecase(db *gorm.DB,prepo repository.ProductRepository) ProductUsecase {
+func NewProductUsecase(db *gorm.DB,prepo repository.ProductRepository,outboxRepo repository.OutboxRepository) ProductUsecase {
 	return &produkusecaseImpl{
 		prepo: prepo,
 		db: db,
+		events: newEventOutbox(outboxRepo),
 	}
 }
 
@@ -42,6 +49,15 @@ func uploadFile(file *multipart.FileHeader) (string, error) {
 	return filename, nil
 }
 
+// deleteUploaded menghapus file hasil uploadFile yang tidak jadi dipakai, file yang tidak ada dilewati
+func deleteUploaded(urls []string) {
+	for _, url := range urls {
+		if err := os.Remove(url); err != nil && !os.IsNotExist(err) {
+			helper.LogError(err)
+		}
+	}
+}
+
 func (p *produkusecaseImpl) Create(ctx context.Context,req *models.ProductCreateReq,userID int,) (int, *helper.ErrorStruct) {
 
 	tx := p.db.Begin()
@@ -60,6 +76,12 @@ func (p *produkusecaseImpl) Create(ctx context.Context,req *models.ProductCreate
 		slugText = slug.Make(req.NamaProduk)
 	}
 
+	// berat default 1 kg jika penjual tidak mengisi
+	berat := req.Berat
+	if berat <= 0 {
+		berat = 1000
+	}
+
 	produk := &entity.Produk{
 		IDToko:        userID,
 		IDCategory:    req.IDCategory,
@@ -68,6 +90,7 @@ func (p *produkusecaseImpl) Create(ctx context.Context,req *models.ProductCreate
 		HargaReseller: req.HargaReseller,
 		HargaKonsumen: req.HargaKonsumen,
 		Stok:          req.Stok,
+		Berat:         berat,
 		Deskripsi:     req.Deskripsi,
 	}
 
@@ -128,9 +151,26 @@ func (p *produkusecaseImpl) Update(ctx context.Context,productID int,req *models
 		HargaReseller: req.HargaReseller,
 		HargaKonsumen: req.HargaKonsumen,
 		Stok:          req.Stok,
+		Berat:         req.Berat,
 		Deskripsi:     req.Deskripsi,
 	}
 
+	before, err := p.prepo.GetForUpdate(ctx, tx, productID)
+	if err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			return &helper.ErrorStruct{Err: err, Code: 404}
+		}
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	// stok yang diisi penjual adalah stok fisik (stok_fisik di response), reservasi yang sedang
+	// ditahan harus tetap tertutup supaya pembayarannya tidak membuat stok negatif
+	if req.Stok < before.StokDitahan {
+		tx.Rollback()
+		return &helper.ErrorStruct{Err: ErrStokBelowReserved, Code: 409}
+	}
+
 	if err := p.prepo.Update(ctx, tx, produk, userID); err != nil {
 		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
@@ -139,6 +179,25 @@ func (p *produkusecaseImpl) Update(ctx context.Context,productID int,req *models
 		return &helper.ErrorStruct{Err: err, Code: 500}
 	}
 
+	// event harga dan stok dibandingkan dengan nilai yang benar-benar tersimpan
+	after, err := p.prepo.GetForUpdate(ctx, tx, productID)
+	if err != nil {
+		tx.Rollback()
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	if err := p.events.priceChanged(ctx, tx, before, after); err != nil {
+		tx.Rollback()
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	if before.Stok != after.Stok {
+		if err := p.events.stockChanged(ctx, tx, productID, entity.StokSebabDiubah, 0); err != nil {
+			tx.Rollback()
+			return &helper.ErrorStruct{Err: err, Code: 500}
+		}
+	}
+
 	if len(req.Photos) > 0 {
 		if err := p.prepo.DeletePhotosByProduct(ctx, tx, productID); err != nil {
 			tx.Rollback()
@@ -255,16 +314,23 @@ func (p *produkusecaseImpl) GetAll(ctx context.Context, filter *entity.ProductFi
 			Slug:          product.Slug,
 			HargaReseller: product.HargaReseller,
 			HargaKonsumen: product.HargaKonsumen,
-			Stok:          product.Stok,
+			Stok:          product.StokTersedia(),
+			StokFisik:     product.Stok,
+			StokDitahan:   product.StokDitahan,
+			Berat:         product.Berat,
 			Deskripsi:     product.Deskripsi,
+			RatingRata:    product.RatingRata,
+			JumlahUlasan:  product.JumlahUlasan,
 		}
 
 		// Map Toko
 		if product.Toko != nil {
 			productResp.Toko = &models.TokoResponse{
-				ID:       product.Toko.ID,
-				NamaToko: product.Toko.NamaToko,
-				UrlFoto:  product.Toko.UrlFoto,
+				ID:           product.Toko.ID,
+				NamaToko:     product.Toko.NamaToko,
+				UrlFoto:      product.Toko.UrlFoto,
+				RatingRata:   product.Toko.RatingRata,
+				JumlahUlasan: product.Toko.JumlahUlasan,
 			}
 		}
 
@@ -319,16 +385,23 @@ func (p *produkusecaseImpl) GetByID(ctx context.Context, productID int) (*models
 		Slug:          product.Slug,
 		HargaReseller: product.HargaReseller,
 		HargaKonsumen: product.HargaKonsumen,
-		Stok:          product.Stok,
+		Stok:          product.StokTersedia(),
+		StokFisik:     product.Stok,
+		StokDitahan:   product.StokDitahan,
+		Berat:         product.Berat,
 		Deskripsi:     product.Deskripsi,
+		RatingRata:    product.RatingRata,
+		JumlahUlasan:  product.JumlahUlasan,
 	}
 
 	// Map Toko
 	if product.Toko != nil {
 		response.Toko = &models.TokoResponse{
-			ID:       product.Toko.ID,
-			NamaToko: product.Toko.NamaToko,
-			UrlFoto:  product.Toko.UrlFoto,
+			ID:           product.Toko.ID,
+			NamaToko:     product.Toko.NamaToko,
+			UrlFoto:      product.Toko.UrlFoto,
+			RatingRata:   product.Toko.RatingRata,
+			JumlahUlasan: product.Toko.JumlahUlasan,
 		}
 	}
 
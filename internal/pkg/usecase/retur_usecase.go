@@ -0,0 +1,570 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReturNotFound          = errors.New("retur tidak ditemukan")
+	ErrInvalidReturTransition = errors.New("perubahan status retur tidak diizinkan")
+	ErrReturNotDelivered      = errors.New("retur hanya bisa diajukan untuk pesanan yang sudah diterima")
+	ErrReturLineNotFound      = errors.New("produk yang diretur tidak ada di transaksi ini")
+	ErrReturMixedToko         = errors.New("satu retur hanya untuk produk dari satu toko")
+	ErrReturQuantity          = errors.New("kuantitas retur melebihi kuantitas yang dibeli")
+	ErrReturAlreadyDisputed   = errors.New("retur sudah pernah diajukan banding")
+)
+
+// returTransitions status asal -> status tujuan -> peran yang boleh.
+// Admin bisa langsung memutuskan retur yang masih requested.
+var returTransitions = map[string]map[string][]string{
+	entity.ReturStatusRequested: {
+		entity.ReturStatusApproved: {entity.TrxActorSeller, entity.TrxActorAdmin},
+		entity.ReturStatusRejected: {entity.TrxActorSeller, entity.TrxActorAdmin},
+	},
+	entity.ReturStatusRejected: {
+		entity.ReturStatusDisputed: {entity.TrxActorBuyer},
+	},
+	entity.ReturStatusDisputed: {
+		entity.ReturStatusApproved: {entity.TrxActorAdmin},
+		entity.ReturStatusRejected: {entity.TrxActorAdmin},
+	},
+}
+
+type ReturUsecase interface {
+	Create(ctx context.Context, userID int, req *models.ReturCreateRequest) (int, *helper.ErrorStruct)
+	List(ctx context.Context, filter *entity.ReturFilter) ([]models.ReturResponse, *helper.ErrorStruct)
+	GetByID(ctx context.Context, returID int, userID int, isAdmin bool) (*models.ReturResponse, *helper.ErrorStruct)
+	Approve(ctx context.Context, returID int, userID int, req *models.ApproveReturRequest) *helper.ErrorStruct
+	Reject(ctx context.Context, returID int, userID int, req *models.RejectReturRequest) *helper.ErrorStruct
+	Dispute(ctx context.Context, returID int, userID int, req *models.DisputeReturRequest) *helper.ErrorStruct
+	Resolve(ctx context.Context, returID int, adminID int, req *models.ResolveReturRequest) *helper.ErrorStruct
+}
+
+type returImpl struct {
+	db        *gorm.DB
+	repo      repository.ReturRepository
+	trxRepo   repository.TransactionRepository
+	paketRepo repository.PaketRepository
+	tokoRepo  repository.TokoRepository
+	status    *TrxStatusMachine
+}
+
+func NewReturUsecase(db *gorm.DB, repo repository.ReturRepository, trxRepo repository.TransactionRepository, paketRepo repository.PaketRepository, tokoRepo repository.TokoRepository, status *TrxStatusMachine) ReturUsecase {
+	return &returImpl{
+		db:        db,
+		repo:      repo,
+		trxRepo:   trxRepo,
+		paketRepo: paketRepo,
+		tokoRepo:  tokoRepo,
+		status:    status,
+	}
+}
+
+// Create pengajuan retur oleh pembeli untuk baris detail_trx dari satu toko yang paketnya sudah diterima
+func (r *returImpl) Create(ctx context.Context, userID int, req *models.ReturCreateRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	trx, err := r.trxRepo.FindTransactionByID(ctx, req.IDTrx)
+	if err != nil || trx.IDUser != userID {
+		return 0, &helper.ErrorStruct{Err: ErrTransactionNotFound, Code: 404}
+	}
+
+	qty := make(map[int]int, len(req.Items))
+	detailIDs := make([]int, 0, len(req.Items))
+	for _, item := range req.Items {
+		if _, ok := qty[item.IDDetailTrx]; !ok {
+			detailIDs = append(detailIDs, item.IDDetailTrx)
+		}
+		qty[item.IDDetailTrx] += item.Kuantitas
+	}
+
+	// foto diunggah sebelum db transaction supaya lock baris tidak tertahan selama upload,
+	// file yang sudah terunggah dihapus lagi bila pengajuan gagal
+	var (
+		photos []entity.ReturFoto
+		urls   []string
+	)
+	for _, file := range req.Photos {
+		url, err := uploadFile(file)
+		if err != nil {
+			deleteUploaded(urls)
+			return 0, &helper.ErrorStruct{Err: err, Code: 400}
+		}
+		photos = append(photos, entity.ReturFoto{Url: url})
+		urls = append(urls, url)
+	}
+
+	var returID int
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		lines, err := r.repo.GetLinesForUpdate(ctx, tx, trx.ID, detailIDs)
+		if err != nil {
+			return err
+		}
+		if len(lines) != len(detailIDs) {
+			return ErrReturLineNotFound
+		}
+
+		tokoID := lines[0].IDToko
+		for _, line := range lines {
+			if line.IDToko != tokoID {
+				return ErrReturMixedToko
+			}
+		}
+
+		if err := r.ensureDelivered(ctx, trx, tokoID); err != nil {
+			return err
+		}
+
+		items, err := returItems(lines, qty)
+		if err != nil {
+			return err
+		}
+
+		retur := &entity.Retur{
+			IDTrx:      trx.ID,
+			IDToko:     tokoID,
+			IDUser:     userID,
+			Alasan:     req.Alasan,
+			Keterangan: req.Keterangan,
+			Status:     entity.ReturStatusRequested,
+		}
+		if err := r.repo.Create(ctx, tx, retur); err != nil {
+			return err
+		}
+
+		for i := range items {
+			items[i].IDRetur = retur.ID
+		}
+		if err := r.repo.CreateItems(ctx, tx, items); err != nil {
+			return err
+		}
+
+		for i := range photos {
+			photos[i].IDRetur = retur.ID
+		}
+		if err := r.repo.CreatePhotos(ctx, tx, photos); err != nil {
+			return err
+		}
+
+		returID = retur.ID
+		return nil
+	})
+
+	if err != nil {
+		deleteUploaded(urls)
+		return 0, returError(err)
+	}
+
+	return returID, nil
+}
+
+// ensureDelivered paket toko harus sudah diterima pembeli, trx lama tanpa paket memakai status trx
+func (r *returImpl) ensureDelivered(ctx context.Context, trx *entity.Transaction, tokoID int) error {
+	pakets, err := r.paketRepo.ListByTrx(ctx, trx.ID)
+	if err != nil {
+		return err
+	}
+
+	status := trx.Status
+	for _, p := range pakets {
+		if p.IDToko == tokoID {
+			status = p.Status
+		}
+	}
+
+	if status != entity.TrxStatusDelivered && status != entity.TrxStatusCompleted {
+		return ErrReturNotDelivered
+	}
+	return nil
+}
+
+// returItems memvalidasi sisa kuantitas per baris dan menghitung nilai refund dari harga setelah diskon
+func returItems(lines []entity.ReturLine, qty map[int]int) ([]entity.ReturItem, error) {
+	items := make([]entity.ReturItem, 0, len(lines))
+	for _, line := range lines {
+		q := qty[line.IDDetailTrx]
+		if q > line.Kuantitas-line.Diretur {
+			return nil, ErrReturQuantity
+		}
+
+		jumlah := line.HargaTotal.Prorate(line.Diretur, q, line.Kuantitas)
+
+		items = append(items, entity.ReturItem{
+			IDDetailTrx: line.IDDetailTrx,
+			Kuantitas:   q,
+			Jumlah:      jumlah,
+		})
+	}
+	return items, nil
+}
+
+func (r *returImpl) List(ctx context.Context, filter *entity.ReturFilter) ([]models.ReturResponse, *helper.ErrorStruct) {
+	returs, err := r.repo.List(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res, err := r.toResponses(ctx, returs)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return res, nil
+}
+
+// GetByID bisa dilihat pembeli, pemilik toko dan admin
+func (r *returImpl) GetByID(ctx context.Context, returID int, userID int, isAdmin bool) (*models.ReturResponse, *helper.ErrorStruct) {
+	retur, err := r.repo.FindByID(ctx, returID)
+	if err != nil {
+		return nil, returError(err)
+	}
+
+	if !isAdmin && retur.IDUser != userID && !r.isSeller(ctx, retur, userID) {
+		return nil, &helper.ErrorStruct{Err: ErrReturNotFound, Code: 404}
+	}
+
+	res, err := r.toResponses(ctx, []entity.Retur{*retur})
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return &res[0], nil
+}
+
+func (r *returImpl) Approve(ctx context.Context, returID int, userID int, req *models.ApproveReturRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	return r.decide(ctx, returID, userID, entity.TrxActorSeller, entity.ReturStatusApproved, req.Restock, req.Catatan)
+}
+
+func (r *returImpl) Reject(ctx context.Context, returID int, userID int, req *models.RejectReturRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	return r.decide(ctx, returID, userID, entity.TrxActorSeller, entity.ReturStatusRejected, false, req.Catatan)
+}
+
+// Resolve keputusan admin saat pembeli dan penjual tidak sepakat
+func (r *returImpl) Resolve(ctx context.Context, returID int, adminID int, req *models.ResolveReturRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	return r.decide(ctx, returID, adminID, entity.TrxActorAdmin, req.Keputusan, req.Restock, req.Catatan)
+}
+
+// Dispute banding pembeli atas retur yang ditolak penjual, hanya sekali per retur
+func (r *returImpl) Dispute(ctx context.Context, returID int, userID int, req *models.DisputeReturRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		retur, err := r.repo.FindForUpdate(ctx, tx, returID)
+		if err != nil {
+			return err
+		}
+
+		if retur.IDUser != userID {
+			return ErrReturNotFound
+		}
+		// keputusan admin bersifat final
+		if retur.CatatanBanding != "" || retur.CatatanAdmin != "" {
+			return ErrReturAlreadyDisputed
+		}
+		if !canReturTransition(retur.Status, entity.ReturStatusDisputed, entity.TrxActorBuyer) {
+			return ErrInvalidReturTransition
+		}
+
+		// retur yang ditolak tidak dihitung, pastikan kuantitasnya belum dipakai pengajuan lain
+		items, err := r.repo.ListItems(ctx, tx, []int{retur.ID})
+		if err != nil {
+			return err
+		}
+
+		qty := make(map[int]int, len(items))
+		detailIDs := make([]int, 0, len(items))
+		for _, item := range items {
+			qty[item.IDDetailTrx] += item.Kuantitas
+			detailIDs = append(detailIDs, item.IDDetailTrx)
+		}
+
+		lines, err := r.repo.GetLinesForUpdate(ctx, tx, retur.IDTrx, detailIDs)
+		if err != nil {
+			return err
+		}
+		if _, err := returItems(lines, qty); err != nil {
+			return err
+		}
+
+		return r.repo.UpdateStatus(ctx, tx, retur.ID, retur.Status, entity.ReturStatusDisputed, map[string]interface{}{
+			"catatan_banding": req.Catatan,
+		})
+	})
+
+	if err != nil {
+		return returError(err)
+	}
+
+	return nil
+}
+
+// decide menyetujui atau menolak retur. Retur yang disetujui membuat refund,
+// mengurangi hak toko di paket_toko dan mengembalikan stok jika restock dipilih.
+func (r *returImpl) decide(ctx context.Context, returID int, userID int, actor string, to string, restock bool, catatan string) *helper.ErrorStruct {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		retur, err := r.repo.FindForUpdate(ctx, tx, returID)
+		if err != nil {
+			return err
+		}
+
+		if actor == entity.TrxActorSeller && !r.isSeller(ctx, retur, userID) {
+			return ErrReturNotFound
+		}
+
+		if !canReturTransition(retur.Status, to, actor) {
+			return ErrInvalidReturTransition
+		}
+
+		now := time.Now()
+		fields := map[string]interface{}{
+			"restock":         restock,
+			"diputuskan_oleh": userID,
+			"diputuskan_pada": now,
+		}
+		if actor == entity.TrxActorAdmin {
+			fields["catatan_admin"] = catatan
+		} else {
+			fields["catatan_penjual"] = catatan
+		}
+
+		if err := r.repo.UpdateStatus(ctx, tx, retur.ID, retur.Status, to, fields); err != nil {
+			return err
+		}
+
+		if to != entity.ReturStatusApproved {
+			return nil
+		}
+
+		items, err := r.repo.ListItems(ctx, tx, []int{retur.ID})
+		if err != nil {
+			return err
+		}
+
+		var total money.Rupiah
+		for _, item := range items {
+			total += item.Jumlah
+			if restock {
+				if err := r.trxRepo.RestoreStokProduk(ctx, tx, item.IDProduk, item.Kuantitas); err != nil {
+					return err
+				}
+				if err := r.status.events.stockChanged(ctx, tx, item.IDProduk, entity.StokSebabRetur, retur.IDTrx); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := r.repo.CreateRefund(ctx, tx, &entity.Refund{
+			IDRetur: &retur.ID,
+			IDTrx:   retur.IDTrx,
+			IDToko:  retur.IDToko,
+			Jumlah:  total,
+			Status:  entity.RefundStatusPending,
+		}); err != nil {
+			return err
+		}
+
+		if err := r.status.komisi.retur(ctx, tx, retur.ID); err != nil {
+			return err
+		}
+
+		if err := r.status.saldo.refunded(ctx, tx, retur.IDTrx); err != nil {
+			return err
+		}
+
+		return r.paketRepo.AddRefund(ctx, tx, retur.IDTrx, retur.IDToko, total)
+	})
+
+	if err != nil {
+		return returError(err)
+	}
+
+	return nil
+}
+
+func (r *returImpl) isSeller(ctx context.Context, retur *entity.Retur, userID int) bool {
+	toko, err := r.tokoRepo.GetByID(ctx, retur.IDToko)
+	return err == nil && toko.IDUser == userID
+}
+
+func (r *returImpl) toResponses(ctx context.Context, returs []entity.Retur) ([]models.ReturResponse, error) {
+	ids := make([]int, 0, len(returs))
+	for _, rt := range returs {
+		ids = append(ids, rt.ID)
+	}
+
+	items, err := r.repo.ListItems(ctx, r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := r.repo.ListPhotos(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := r.repo.ListRefunds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsByRetur := make(map[int][]entity.ReturItemDetail)
+	for _, it := range items {
+		itemsByRetur[it.IDRetur] = append(itemsByRetur[it.IDRetur], it)
+	}
+	photosByRetur := make(map[int][]string)
+	for _, p := range photos {
+		photosByRetur[p.IDRetur] = append(photosByRetur[p.IDRetur], p.Url)
+	}
+	refundByRetur := make(map[int]entity.Refund)
+	for _, rf := range refunds {
+		refundByRetur[*rf.IDRetur] = rf
+	}
+
+	res := make([]models.ReturResponse, 0, len(returs))
+	for _, rt := range returs {
+		resp := models.ReturResponse{
+			ID:             rt.ID,
+			IDTrx:          rt.IDTrx,
+			IDToko:         rt.IDToko,
+			IDUser:         rt.IDUser,
+			Alasan:         rt.Alasan,
+			Keterangan:     rt.Keterangan,
+			Status:         rt.Status,
+			Restock:        rt.Restock,
+			CatatanPenjual: rt.CatatanPenjual,
+			CatatanBanding: rt.CatatanBanding,
+			CatatanAdmin:   rt.CatatanAdmin,
+			Items:          make([]models.ReturItemResponse, 0),
+			Photos:         photosByRetur[rt.ID],
+			DiputuskanPada: rt.DiputuskanPada,
+			CreatedAt:      rt.CreatedAt,
+		}
+		if resp.Photos == nil {
+			resp.Photos = []string{}
+		}
+
+		for _, it := range itemsByRetur[rt.ID] {
+			resp.Items = append(resp.Items, models.ReturItemResponse{
+				IDDetailTrx: it.IDDetailTrx,
+				NamaProduk:  it.NamaProduk,
+				Kuantitas:   it.Kuantitas,
+				Jumlah:      it.Jumlah,
+			})
+			resp.TotalRetur += it.Jumlah
+		}
+
+		if rf, ok := refundByRetur[rt.ID]; ok {
+			resp.Refund = &models.RefundResponse{
+				ID:     rf.ID,
+				Jumlah: rf.Jumlah,
+				Status: rf.Status,
+			}
+		}
+
+		res = append(res, resp)
+	}
+
+	return res, nil
+}
+
+// refundRecorder mencatat pengembalian dana pembeli untuk paket yang batal setelah dibayar
+type refundRecorder struct {
+	repo        repository.ReturRepository
+	paketRepo   repository.PaketRepository
+	paymentRepo repository.PaymentRepository
+}
+
+func newRefundRecorder(repo repository.ReturRepository, paketRepo repository.PaketRepository, paymentRepo repository.PaymentRepository) *refundRecorder {
+	return &refundRecorder{
+		repo:        repo,
+		paketRepo:   paketRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// paket mencatat refund satu paket sebesar barang setelah diskon ditambah ongkir paket.
+// Pesanan COD belum dibayar sebelum diterima sehingga tidak ada dana yang dikembalikan.
+func (r *refundRecorder) paket(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, paket *entity.PaketToko) error {
+	if trx.MetodeBayar == entity.MetodeBayarCOD {
+		return nil
+	}
+
+	barang, err := r.paketRepo.TotalBarang(ctx, tx, paket.IDTrx, paket.IDToko)
+	if err != nil {
+		return err
+	}
+
+	return r.repo.CreateRefund(ctx, tx, &entity.Refund{
+		IDPaket: &paket.ID,
+		IDTrx:   paket.IDTrx,
+		IDToko:  paket.IDToko,
+		Jumlah:  barang + paket.Ongkir,
+		Status:  entity.RefundStatusPending,
+	})
+}
+
+// cancelled mencatat refund setiap paket yang belum dibatalkan lalu menandai pembayaran trx,
+// paket yang sudah ditolak toko sudah punya refund sendiri
+func (r *refundRecorder) cancelled(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, pakets []entity.PaketToko, catatan string) error {
+	if trx.MetodeBayar == entity.MetodeBayarCOD {
+		return nil
+	}
+
+	for i := range pakets {
+		if pakets[i].Status == entity.TrxStatusCancelled {
+			continue
+		}
+		if err := r.paket(ctx, tx, trx, &pakets[i]); err != nil {
+			return err
+		}
+	}
+
+	return r.paymentRepo.FlagRefundByTrx(ctx, tx, trx.ID, catatan)
+}
+
+func canReturTransition(from string, to string, actor string) bool {
+	for _, a := range returTransitions[from][to] {
+		if a == actor {
+			return true
+		}
+	}
+	return false
+}
+
+func returError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrReturNotFound):
+		return &helper.ErrorStruct{Err: ErrReturNotFound, Code: 404}
+	case errors.Is(err, ErrInvalidReturTransition), errors.Is(err, ErrReturNotDelivered), errors.Is(err, ErrReturAlreadyDisputed):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	case errors.Is(err, ErrReturLineNotFound), errors.Is(err, ErrReturMixedToko), errors.Is(err, ErrReturQuantity):
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+	return &helper.ErrorStruct{Err: err, Code: 500}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+)
+
+// fakeRefundRepo mencatat refund yang dibuat
+type fakeRefundRepo struct {
+	repository.ReturRepository
+	refunds []entity.Refund
+}
+
+func (f *fakeRefundRepo) CreateRefund(ctx context.Context, tx *gorm.DB, refund *entity.Refund) error {
+	f.refunds = append(f.refunds, *refund)
+	return nil
+}
+
+// fakeBarangRepo mengembalikan total barang per toko
+type fakeBarangRepo struct {
+	repository.PaketRepository
+	barang map[int]money.Rupiah
+}
+
+func (f *fakeBarangRepo) TotalBarang(ctx context.Context, tx *gorm.DB, trxID int, tokoID int) (money.Rupiah, error) {
+	return f.barang[tokoID], nil
+}
+
+// fakeFlagPaymentRepo mencatat trx yang pembayarannya ditandai refund
+type fakeFlagPaymentRepo struct {
+	repository.PaymentRepository
+	flagged []int
+}
+
+func (f *fakeFlagPaymentRepo) FlagRefundByTrx(ctx context.Context, tx *gorm.DB, trxID int, catatan string) error {
+	f.flagged = append(f.flagged, trxID)
+	return nil
+}
+
+func TestRefundCancelled(t *testing.T) {
+	tests := []struct {
+		name        string
+		metodeBayar string
+		pakets      []entity.PaketToko
+		want        map[int]money.Rupiah
+		wantFlag    []int
+	}{
+		{
+			"semua paket aktif",
+			"ovo",
+			[]entity.PaketToko{
+				{ID: 1, IDTrx: 9, IDToko: 10, Ongkir: 9000, Status: entity.TrxStatusPaid},
+				{ID: 2, IDTrx: 9, IDToko: 20, Ongkir: 12000, Status: entity.TrxStatusProcessing},
+			},
+			map[int]money.Rupiah{1: 50000 + 9000, 2: 30000 + 12000},
+			[]int{9},
+		},
+		{
+			"paket ditolak sudah direfund",
+			"dana",
+			[]entity.PaketToko{
+				{ID: 1, IDTrx: 9, IDToko: 10, Ongkir: 9000, Status: entity.TrxStatusCancelled},
+				{ID: 2, IDTrx: 9, IDToko: 20, Ongkir: 12000, Status: entity.TrxStatusProcessing},
+			},
+			map[int]money.Rupiah{2: 30000 + 12000},
+			[]int{9},
+		},
+		{
+			"semua paket sudah ditolak",
+			"gopay",
+			[]entity.PaketToko{
+				{ID: 1, IDTrx: 9, IDToko: 10, Ongkir: 9000, Status: entity.TrxStatusCancelled},
+			},
+			map[int]money.Rupiah{},
+			[]int{9},
+		},
+		{
+			"cod belum dibayar",
+			entity.MetodeBayarCOD,
+			[]entity.PaketToko{
+				{ID: 1, IDTrx: 9, IDToko: 10, Ongkir: 9000, Status: entity.TrxStatusPaid},
+			},
+			map[int]money.Rupiah{},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			returRepo := &fakeRefundRepo{}
+			paymentRepo := &fakeFlagPaymentRepo{}
+			r := newRefundRecorder(returRepo, &fakeBarangRepo{barang: map[int]money.Rupiah{10: 50000, 20: 30000}}, paymentRepo)
+
+			if err := r.cancelled(context.Background(), nil, &entity.Transaction{ID: 9, MetodeBayar: tt.metodeBayar}, tt.pakets, "dibatalkan"); err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[int]money.Rupiah, len(returRepo.refunds))
+			for _, rf := range returRepo.refunds {
+				if rf.IDPaket == nil || rf.IDTrx != 9 || rf.IDRetur != nil || rf.Status != entity.RefundStatusPending {
+					t.Fatalf("refund = %+v", rf)
+				}
+				got[*rf.IDPaket] = rf.Jumlah
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("refund per paket = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(paymentRepo.flagged, tt.wantFlag) {
+				t.Errorf("pembayaran ditandai = %v, want %v", paymentRepo.flagged, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestDeleteUploaded(t *testing.T) {
+	dir := t.TempDir()
+	terunggah := filepath.Join(dir, "1-bukti.jpg")
+	if err := os.WriteFile(terunggah, []byte("foto"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deleteUploaded([]string{terunggah, filepath.Join(dir, "belum-tersimpan.jpg")})
+
+	if _, err := os.Stat(terunggah); !os.IsNotExist(err) {
+		t.Errorf("file %s masih ada (err %v)", terunggah, err)
+	}
+}
@@ -0,0 +1,535 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pbi/internal/config"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTokoNotOwned               = errors.New("toko tidak ditemukan")
+	ErrRekeningNotFound           = errors.New("rekening tidak ditemukan")
+	ErrPenarikanNotFound          = errors.New("penarikan tidak ditemukan")
+	ErrInvalidPenarikanTransition = errors.New("perubahan status penarikan tidak diizinkan")
+	ErrSaldoTidakCukup            = errors.New("saldo toko tidak cukup")
+	ErrMutasiJenis                = errors.New("jenis mutasi tidak dikenal")
+	ErrPenarikanStatus            = errors.New("status penarikan tidak dikenal")
+)
+
+// penarikanTransitions status asal -> status tujuan yang boleh diputuskan admin
+var penarikanTransitions = map[string][]string{
+	entity.PenarikanStatusRequested: {entity.PenarikanStatusApproved, entity.PenarikanStatusRejected},
+	entity.PenarikanStatusApproved:  {entity.PenarikanStatusPaid, entity.PenarikanStatusRejected},
+}
+
+type SaldoUsecase interface {
+	ListSaldo(ctx context.Context, userID int) ([]models.SaldoTokoResponse, *helper.ErrorStruct)
+	Mutasi(ctx context.Context, userID int, filter *entity.MutasiFilter) (*models.MutasiListResponse, *helper.ErrorStruct)
+	AddRekening(ctx context.Context, userID int, req *models.RekeningRequest) (int, *helper.ErrorStruct)
+	ListRekening(ctx context.Context, userID int, tokoID int) ([]models.RekeningResponse, *helper.ErrorStruct)
+	RequestPenarikan(ctx context.Context, userID int, req *models.PenarikanRequest) (int, *helper.ErrorStruct)
+	ListPenarikan(ctx context.Context, filter *entity.PenarikanFilter) (*models.PenarikanListResponse, *helper.ErrorStruct)
+	ApprovePenarikan(ctx context.Context, penarikanID int, adminID int) *helper.ErrorStruct
+	RejectPenarikan(ctx context.Context, penarikanID int, adminID int, req *models.RejectPenarikanRequest) *helper.ErrorStruct
+	PayPenarikan(ctx context.Context, penarikanID int, adminID int, req *models.PayPenarikanRequest) *helper.ErrorStruct
+	Rekonsiliasi(ctx context.Context, tokoID int) (*models.RekonsiliasiSaldoResponse, *helper.ErrorStruct)
+}
+
+type saldoImpl struct {
+	db           *gorm.DB
+	repo         repository.SaldoRepository
+	tokoRepo     repository.TokoRepository
+	ledger       *saldoLedger
+	minPenarikan money.Rupiah
+}
+
+func NewSaldoUsecase(db *gorm.DB, repo repository.SaldoRepository, tokoRepo repository.TokoRepository, cfg config.SaldoConfig) SaldoUsecase {
+	return &saldoImpl{
+		db:           db,
+		repo:         repo,
+		tokoRepo:     tokoRepo,
+		ledger:       newSaldoLedger(repo, cfg),
+		minPenarikan: money.Rupiah(cfg.MinPenarikan),
+	}
+}
+
+// ownToko memastikan toko milik user
+func (s *saldoImpl) ownToko(ctx context.Context, userID int, tokoID int) error {
+	toko, err := s.tokoRepo.GetByID(ctx, tokoID)
+	if err != nil || toko.IDUser != userID {
+		return ErrTokoNotOwned
+	}
+	return nil
+}
+
+// ListSaldo saldo setiap toko milik user
+func (s *saldoImpl) ListSaldo(ctx context.Context, userID int) ([]models.SaldoTokoResponse, *helper.ErrorStruct) {
+	saldos, err := s.repo.ListSaldo(ctx, userID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.SaldoTokoResponse, 0, len(saldos))
+	for _, sd := range saldos {
+		res = append(res, models.SaldoTokoResponse{
+			IDToko:          sd.IDToko,
+			NamaToko:        sd.NamaToko,
+			Saldo:           sd.Saldo,
+			PenarikanProses: sd.PenarikanProses,
+		})
+	}
+
+	return res, nil
+}
+
+// Mutasi buku saldo satu toko, terbaru lebih dulu
+func (s *saldoImpl) Mutasi(ctx context.Context, userID int, filter *entity.MutasiFilter) (*models.MutasiListResponse, *helper.ErrorStruct) {
+	switch filter.Jenis {
+	case "", entity.MutasiJenisPenjualan, entity.MutasiJenisBiayaPlatform, entity.MutasiJenisRefund,
+		entity.MutasiJenisPenarikan, entity.MutasiJenisPenarikanBatal:
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrMutasiJenis, Code: 400}
+	}
+
+	if err := s.ownToko(ctx, userID, filter.IDToko); err != nil {
+		return nil, saldoError(err)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxTrxPageLimit {
+		filter.Limit = maxTrxPageLimit
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	mutasi, total, err := s.repo.ListMutasi(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := &models.MutasiListResponse{
+		Data:       make([]models.MutasiSaldoResponse, 0, len(mutasi)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+	}
+
+	for _, m := range mutasi {
+		res.Data = append(res.Data, models.MutasiSaldoResponse{
+			ID:          m.ID,
+			Jenis:       m.Jenis,
+			IDTrx:       m.IDTrx,
+			IDDetailTrx: m.IDDetailTrx,
+			IDRefund:    m.IDRefund,
+			IDPenarikan: m.IDPenarikan,
+			Jumlah:      m.Jumlah,
+			Saldo:       m.Saldo,
+			Keterangan:  m.Keterangan,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *saldoImpl) AddRekening(ctx context.Context, userID int, req *models.RekeningRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if err := s.ownToko(ctx, userID, req.IDToko); err != nil {
+		return 0, saldoError(err)
+	}
+
+	rekening := &entity.RekeningToko{
+		IDToko:        req.IDToko,
+		NamaBank:      req.NamaBank,
+		NomorRekening: req.NomorRekening,
+		AtasNama:      req.AtasNama,
+	}
+
+	if err := s.repo.CreateRekening(ctx, rekening); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return rekening.ID, nil
+}
+
+func (s *saldoImpl) ListRekening(ctx context.Context, userID int, tokoID int) ([]models.RekeningResponse, *helper.ErrorStruct) {
+	rekenings, err := s.repo.ListRekening(ctx, userID, tokoID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.RekeningResponse, 0, len(rekenings))
+	for _, r := range rekenings {
+		res = append(res, toRekeningResponse(r))
+	}
+
+	return res, nil
+}
+
+// RequestPenarikan memotong saldo toko saat diajukan supaya saldo tidak bisa ditarik dua kali
+func (s *saldoImpl) RequestPenarikan(ctx context.Context, userID int, req *models.PenarikanRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if req.Jumlah < s.minPenarikan {
+		return 0, &helper.ErrorStruct{Err: fmt.Errorf("penarikan minimal %s", s.minPenarikan), Code: 400}
+	}
+
+	if err := s.ownToko(ctx, userID, req.IDToko); err != nil {
+		return 0, saldoError(err)
+	}
+
+	var penarikanID int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		rekening, err := s.repo.FindRekening(ctx, tx, req.IDRekening)
+		if err != nil || rekening.IDToko != req.IDToko {
+			return ErrRekeningNotFound
+		}
+
+		saldos, err := s.repo.LockSaldo(ctx, tx, []int{req.IDToko})
+		if err != nil {
+			return err
+		}
+		if saldos[req.IDToko] < req.Jumlah {
+			return ErrSaldoTidakCukup
+		}
+
+		penarikan := &entity.Penarikan{
+			IDToko:     req.IDToko,
+			IDRekening: rekening.ID,
+			Jumlah:     req.Jumlah,
+			Status:     entity.PenarikanStatusRequested,
+		}
+		if err := s.repo.CreatePenarikan(ctx, tx, penarikan); err != nil {
+			return err
+		}
+		penarikanID = penarikan.ID
+
+		return s.ledger.post(ctx, tx, []entity.MutasiSaldo{{
+			IDToko:      req.IDToko,
+			Jenis:       entity.MutasiJenisPenarikan,
+			IDPenarikan: &penarikan.ID,
+			Jumlah:      -req.Jumlah,
+			Keterangan:  "penarikan ke " + rekening.NamaBank + " " + rekening.NomorRekening,
+		}})
+	})
+
+	if err != nil {
+		return 0, saldoError(err)
+	}
+
+	return penarikanID, nil
+}
+
+// ListPenarikan filter.IDUser diisi untuk penjual, kosong untuk admin
+func (s *saldoImpl) ListPenarikan(ctx context.Context, filter *entity.PenarikanFilter) (*models.PenarikanListResponse, *helper.ErrorStruct) {
+	switch filter.Status {
+	case "", entity.PenarikanStatusRequested, entity.PenarikanStatusApproved, entity.PenarikanStatusPaid, entity.PenarikanStatusRejected:
+	default:
+		return nil, &helper.ErrorStruct{Err: ErrPenarikanStatus, Code: 400}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxTrxPageLimit {
+		filter.Limit = maxTrxPageLimit
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	penarikans, total, err := s.repo.ListPenarikan(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := &models.PenarikanListResponse{
+		Data:       make([]models.PenarikanResponse, 0, len(penarikans)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+	}
+
+	for _, p := range penarikans {
+		res.Data = append(res.Data, models.PenarikanResponse{
+			ID:       p.ID,
+			IDToko:   p.IDToko,
+			NamaToko: p.NamaToko,
+			Rekening: models.RekeningResponse{
+				ID:            p.IDRekening,
+				IDToko:        p.IDToko,
+				NamaBank:      p.NamaBank,
+				NomorRekening: p.NomorRekening,
+				AtasNama:      p.AtasNama,
+			},
+			Jumlah:         p.Jumlah,
+			Status:         p.Status,
+			AlasanTolak:    p.AlasanTolak,
+			Referensi:      p.Referensi,
+			DiputuskanPada: p.DiputuskanPada,
+			DibayarPada:    p.DibayarPada,
+			CreatedAt:      p.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *saldoImpl) ApprovePenarikan(ctx context.Context, penarikanID int, adminID int) *helper.ErrorStruct {
+	return s.decide(ctx, penarikanID, adminID, entity.PenarikanStatusApproved, nil)
+}
+
+// RejectPenarikan mengembalikan saldo yang dipotong saat pengajuan
+func (s *saldoImpl) RejectPenarikan(ctx context.Context, penarikanID int, adminID int, req *models.RejectPenarikanRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+	return s.decide(ctx, penarikanID, adminID, entity.PenarikanStatusRejected, map[string]interface{}{"alasan_tolak": req.Alasan})
+}
+
+// PayPenarikan menandai dana sudah ditransfer, referensi adalah nomor bukti transfer
+func (s *saldoImpl) PayPenarikan(ctx context.Context, penarikanID int, adminID int, req *models.PayPenarikanRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+	return s.decide(ctx, penarikanID, adminID, entity.PenarikanStatusPaid, map[string]interface{}{
+		"referensi":    req.Referensi,
+		"dibayar_pada": time.Now(),
+	})
+}
+
+func (s *saldoImpl) decide(ctx context.Context, penarikanID int, adminID int, to string, fields map[string]interface{}) *helper.ErrorStruct {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		penarikan, err := s.repo.FindPenarikanForUpdate(ctx, tx, penarikanID)
+		if err != nil {
+			return err
+		}
+
+		if !canPenarikanTransition(penarikan.Status, to) {
+			return ErrInvalidPenarikanTransition
+		}
+
+		if fields == nil {
+			fields = map[string]interface{}{}
+		}
+		if to != entity.PenarikanStatusPaid {
+			fields["diputuskan_oleh"] = adminID
+			fields["diputuskan_pada"] = time.Now()
+		}
+
+		if err := s.repo.UpdatePenarikan(ctx, tx, penarikan.ID, penarikan.Status, to, fields); err != nil {
+			return err
+		}
+
+		if to != entity.PenarikanStatusRejected {
+			return nil
+		}
+
+		return s.ledger.post(ctx, tx, []entity.MutasiSaldo{{
+			IDToko:      penarikan.IDToko,
+			Jenis:       entity.MutasiJenisPenarikanBatal,
+			IDPenarikan: &penarikan.ID,
+			Jumlah:      penarikan.Jumlah,
+			Keterangan:  "penarikan " + strconv.Itoa(penarikan.ID) + " ditolak",
+		}})
+	})
+
+	if err != nil {
+		return saldoError(err)
+	}
+
+	return nil
+}
+
+// Rekonsiliasi mencocokkan buku saldo toko dengan saldo berjalan, detail_trx dan refund
+func (s *saldoImpl) Rekonsiliasi(ctx context.Context, tokoID int) (*models.RekonsiliasiSaldoResponse, *helper.ErrorStruct) {
+	if _, err := s.tokoRepo.GetByID(ctx, tokoID); err != nil {
+		return nil, saldoError(ErrTokoNotOwned)
+	}
+
+	r, err := s.repo.Rekonsiliasi(ctx, tokoID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return &models.RekonsiliasiSaldoResponse{
+		IDToko:             tokoID,
+		SaldoToko:          r.SaldoToko,
+		SaldoTerakhir:      r.SaldoTerakhir,
+		TotalMutasi:        r.TotalMutasi,
+		BarisTidakSesuai:   r.BarisTidakSesuai,
+		PenjualanBuku:      r.PenjualanBuku,
+		PenjualanDetailTrx: r.PenjualanDetailTrx,
+		RefundBuku:         r.RefundBuku,
+		RefundSumber:       r.RefundSumber,
+		Sesuai: r.SaldoToko == r.SaldoTerakhir && r.SaldoTerakhir == r.TotalMutasi && r.BarisTidakSesuai == 0 &&
+			r.PenjualanBuku == r.PenjualanDetailTrx && r.RefundBuku == r.RefundSumber,
+	}, nil
+}
+
+// saldoLedger menulis buku saldo toko. Setiap baris menyimpan saldo setelah mutasi,
+// baris saldo_toko dikunci selama penulisan sehingga saldo berjalan selalu berurutan.
+type saldoLedger struct {
+	repo        repository.SaldoRepository
+	biayaPersen float64
+}
+
+func newSaldoLedger(repo repository.SaldoRepository, cfg config.SaldoConfig) *saldoLedger {
+	return &saldoLedger{
+		repo:        repo,
+		biayaPersen: cfg.BiayaPlatformPersen,
+	}
+}
+
+// completed mengkreditkan baris pesanan yang selesai beserta biaya platformnya,
+// lalu mencatat refund yang sudah disetujui sebelum pesanan selesai
+func (l *saldoLedger) completed(ctx context.Context, tx *gorm.DB, trxID int) error {
+	lines, err := l.repo.UncreditedLines(ctx, tx, trxID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]entity.MutasiSaldo, 0, len(lines)*2)
+	for i := range lines {
+		line := &lines[i]
+		entries = append(entries, entity.MutasiSaldo{
+			IDToko:      line.IDToko,
+			Jenis:       entity.MutasiJenisPenjualan,
+			IDTrx:       &line.IDTrx,
+			IDDetailTrx: &line.IDDetailTrx,
+			Jumlah:      line.HargaTotal,
+			Keterangan:  "penjualan detail " + strconv.Itoa(line.IDDetailTrx),
+		})
+
+		if biaya := line.HargaTotal.Percent(l.biayaPersen); biaya > 0 {
+			entries = append(entries, entity.MutasiSaldo{
+				IDToko:      line.IDToko,
+				Jenis:       entity.MutasiJenisBiayaPlatform,
+				IDTrx:       &line.IDTrx,
+				IDDetailTrx: &line.IDDetailTrx,
+				Jumlah:      -biaya,
+				Keterangan:  "biaya platform " + strconv.FormatFloat(l.biayaPersen, 'f', -1, 64) + "%",
+			})
+		}
+	}
+
+	if err := l.post(ctx, tx, entries); err != nil {
+		return err
+	}
+
+	return l.refunded(ctx, tx, trxID)
+}
+
+// refunded mendebit refund trx yang belum dicatat. Refund untuk toko yang penjualannya
+// belum dikreditkan menunggu sampai pesanan completed.
+func (l *saldoLedger) refunded(ctx context.Context, tx *gorm.DB, trxID int) error {
+	refunds, err := l.repo.UnpostedRefunds(ctx, tx, trxID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]entity.MutasiSaldo, 0, len(refunds))
+	for i := range refunds {
+		rf := &refunds[i]
+		entries = append(entries, entity.MutasiSaldo{
+			IDToko:     rf.IDToko,
+			Jenis:      entity.MutasiJenisRefund,
+			IDTrx:      &rf.IDTrx,
+			IDRefund:   &rf.ID,
+			Jumlah:     -rf.Jumlah,
+			Keterangan: "refund retur " + strconv.Itoa(*rf.IDRetur),
+		})
+	}
+
+	return l.post(ctx, tx, entries)
+}
+
+// post mengunci saldo toko yang terlibat (urut id_toko), menulis mutasi sesuai urutan
+// entries dengan saldo berjalan, lalu menyimpan saldo akhir
+func (l *saldoLedger) post(ctx context.Context, tx *gorm.DB, entries []entity.MutasiSaldo) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tokoIDs := make([]int, 0, len(entries))
+	for _, e := range entries {
+		tokoIDs = append(tokoIDs, e.IDToko)
+	}
+	tokoIDs = uniqueInts(tokoIDs)
+	sort.Ints(tokoIDs)
+
+	saldos, err := l.repo.LockSaldo(ctx, tx, tokoIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		saldos[e.IDToko] += e.Jumlah
+		e.Saldo = saldos[e.IDToko]
+		if err := l.repo.CreateMutasi(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range tokoIDs {
+		if err := l.repo.SetSaldo(ctx, tx, id, saldos[id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func canPenarikanTransition(from string, to string) bool {
+	for _, s := range penarikanTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func toRekeningResponse(r entity.RekeningToko) models.RekeningResponse {
+	return models.RekeningResponse{
+		ID:            r.ID,
+		IDToko:        r.IDToko,
+		NamaBank:      r.NamaBank,
+		NomorRekening: r.NomorRekening,
+		AtasNama:      r.AtasNama,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+func saldoError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, ErrTokoNotOwned), errors.Is(err, ErrRekeningNotFound):
+		return &helper.ErrorStruct{Err: err, Code: 404}
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrPenarikanNotFound):
+		return &helper.ErrorStruct{Err: ErrPenarikanNotFound, Code: 404}
+	case errors.Is(err, ErrInvalidPenarikanTransition), errors.Is(err, ErrSaldoTidakCukup):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	}
+	return &helper.ErrorStruct{Err: err, Code: 500}
+}
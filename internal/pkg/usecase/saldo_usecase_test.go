@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"pbi/internal/config"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+)
+
+// fakeSaldoRepo menyimpan saldo toko di memori dan mencatat mutasi yang ditulis ledger
+type fakeSaldoRepo struct {
+	repository.SaldoRepository
+	saldo   map[int]money.Rupiah
+	lines   []entity.SaldoLine
+	refunds []entity.Refund
+	mutasi  []entity.MutasiSaldo
+	locked  [][]int
+}
+
+func (f *fakeSaldoRepo) LockSaldo(ctx context.Context, tx *gorm.DB, tokoIDs []int) (map[int]money.Rupiah, error) {
+	f.locked = append(f.locked, append([]int(nil), tokoIDs...))
+
+	res := make(map[int]money.Rupiah, len(tokoIDs))
+	for _, id := range tokoIDs {
+		res[id] = f.saldo[id]
+	}
+	return res, nil
+}
+
+func (f *fakeSaldoRepo) SetSaldo(ctx context.Context, tx *gorm.DB, tokoID int, saldo money.Rupiah) error {
+	f.saldo[tokoID] = saldo
+	return nil
+}
+
+func (f *fakeSaldoRepo) CreateMutasi(ctx context.Context, tx *gorm.DB, mutasi *entity.MutasiSaldo) error {
+	f.mutasi = append(f.mutasi, *mutasi)
+	return nil
+}
+
+func (f *fakeSaldoRepo) UncreditedLines(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.SaldoLine, error) {
+	return f.lines, nil
+}
+
+func (f *fakeSaldoRepo) UnpostedRefunds(ctx context.Context, tx *gorm.DB, trxID int) ([]entity.Refund, error) {
+	return f.refunds, nil
+}
+
+func TestSaldoLedgerCompleted(t *testing.T) {
+	idRetur := 9
+	lines := []entity.SaldoLine{
+		{IDTrx: 7, IDDetailTrx: 11, IDToko: 2, HargaTotal: 40000},
+		{IDTrx: 7, IDDetailTrx: 12, IDToko: 1, HargaTotal: 10001},
+		{IDTrx: 7, IDDetailTrx: 13, IDToko: 2, HargaTotal: 2000},
+	}
+	refunds := []entity.Refund{{ID: 3, IDRetur: &idRetur, IDTrx: 7, IDToko: 2, Jumlah: 5000}}
+
+	type mutasi struct {
+		toko   int
+		jenis  string
+		jumlah money.Rupiah
+		saldo  money.Rupiah
+	}
+
+	tests := []struct {
+		name      string
+		biaya     float64
+		refunds   []entity.Refund
+		want      []mutasi
+		wantSaldo map[int]money.Rupiah
+	}{
+		{
+			name:    "dengan biaya platform dan refund",
+			biaya:   2.5,
+			refunds: refunds,
+			want: []mutasi{
+				{2, entity.MutasiJenisPenjualan, 40000, 40000},
+				{2, entity.MutasiJenisBiayaPlatform, -1000, 39000},
+				{1, entity.MutasiJenisPenjualan, 10001, 15001},
+				{1, entity.MutasiJenisBiayaPlatform, -250, 14751},
+				{2, entity.MutasiJenisPenjualan, 2000, 41000},
+				{2, entity.MutasiJenisBiayaPlatform, -50, 40950},
+				{2, entity.MutasiJenisRefund, -5000, 35950},
+			},
+			wantSaldo: map[int]money.Rupiah{1: 14751, 2: 35950},
+		},
+		{
+			name: "tanpa biaya platform",
+			want: []mutasi{
+				{2, entity.MutasiJenisPenjualan, 40000, 40000},
+				{1, entity.MutasiJenisPenjualan, 10001, 15001},
+				{2, entity.MutasiJenisPenjualan, 2000, 42000},
+			},
+			wantSaldo: map[int]money.Rupiah{1: 15001, 2: 42000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSaldoRepo{
+				saldo:   map[int]money.Rupiah{1: 5000},
+				lines:   lines,
+				refunds: tt.refunds,
+			}
+			ledger := newSaldoLedger(repo, config.SaldoConfig{BiayaPlatformPersen: tt.biaya})
+
+			if err := ledger.completed(context.Background(), nil, 7); err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]mutasi, 0, len(repo.mutasi))
+			for _, m := range repo.mutasi {
+				got = append(got, mutasi{m.IDToko, m.Jenis, m.Jumlah, m.Saldo})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mutasi = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(repo.saldo, tt.wantSaldo) {
+				t.Errorf("saldo = %v, want %v", repo.saldo, tt.wantSaldo)
+			}
+			for _, ids := range repo.locked {
+				if !sort.IntsAreSorted(ids) {
+					t.Errorf("lock saldo tidak berurutan: %v", ids)
+				}
+			}
+		})
+	}
+}
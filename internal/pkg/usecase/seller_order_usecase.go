@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaketNotFound          = errors.New("pesanan toko tidak ditemukan")
+	ErrInvalidPaketTransition = errors.New("perubahan status pesanan toko tidak diizinkan")
+)
+
+// paketTransitions aksi penjual terhadap paket tokonya sendiri
+var paketTransitions = map[string][]string{
+	entity.TrxStatusPaid:       {entity.TrxStatusProcessing, entity.TrxStatusCancelled},
+	entity.TrxStatusProcessing: {entity.TrxStatusShipped, entity.TrxStatusCancelled},
+	entity.TrxStatusShipped:    {entity.TrxStatusDelivered},
+}
+
+// paketNext langkah status trx berikutnya saat diturunkan dari paket
+var paketNext = map[string]string{
+	entity.TrxStatusPaid:       entity.TrxStatusProcessing,
+	entity.TrxStatusProcessing: entity.TrxStatusShipped,
+	entity.TrxStatusShipped:    entity.TrxStatusDelivered,
+}
+
+// urutan status paket untuk menurunkan status trx
+var paketRank = map[string]int{
+	entity.TrxStatusPaid:       1,
+	entity.TrxStatusProcessing: 2,
+	entity.TrxStatusShipped:    3,
+	entity.TrxStatusDelivered:  4,
+	entity.TrxStatusCompleted:  5,
+}
+
+type SellerOrderUsecase interface {
+	List(ctx context.Context, filter *entity.SellerOrderFilter) (*models.SellerOrderListResponse, *helper.ErrorStruct)
+	Accept(ctx context.Context, paketID int, userID int) *helper.ErrorStruct
+	Reject(ctx context.Context, paketID int, userID int, req *models.RejectPaketRequest) *helper.ErrorStruct
+	Ship(ctx context.Context, paketID int, userID int, req *models.ShipPaketRequest) *helper.ErrorStruct
+	Deliver(ctx context.Context, paketID int, userID int) *helper.ErrorStruct
+	Label(ctx context.Context, paketID int, userID int, format string) (*models.InvoiceFile, *helper.ErrorStruct)
+}
+
+type sellerOrderImpl struct {
+	db        *gorm.DB
+	trxRepo   repository.TransactionRepository
+	paketRepo repository.PaketRepository
+	destRepo  repository.DestinationRepository
+	kirimRepo repository.PengirimanRepository
+	status    *TrxStatusMachine
+}
+
+func NewSellerOrderUsecase(db *gorm.DB, trxRepo repository.TransactionRepository, paketRepo repository.PaketRepository, destRepo repository.DestinationRepository, kirimRepo repository.PengirimanRepository, status *TrxStatusMachine) SellerOrderUsecase {
+	return &sellerOrderImpl{
+		db:        db,
+		trxRepo:   trxRepo,
+		paketRepo: paketRepo,
+		destRepo:  destRepo,
+		kirimRepo: kirimRepo,
+		status:    status,
+	}
+}
+
+// List inbox pesanan untuk semua toko milik user
+func (s *sellerOrderImpl) List(ctx context.Context, filter *entity.SellerOrderFilter) (*models.SellerOrderListResponse, *helper.ErrorStruct) {
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	orders, total, err := s.paketRepo.ListSellerOrders(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	trxIDs := make([]int, 0, len(orders))
+	destIDs := make([]int, 0, len(orders))
+	for _, o := range orders {
+		trxIDs = append(trxIDs, o.IDTrx)
+		destIDs = append(destIDs, o.AlamatPengiriman)
+	}
+
+	items, err := s.paketRepo.ListSellerOrderItems(ctx, filter.IDUser, trxIDs)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	dests, err := s.destRepo.FindByIDs(ctx, uniqueInts(destIDs))
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	itemsByPaket := make(map[[2]int][]models.SellerOrderItemResponse)
+	for _, it := range items {
+		key := [2]int{it.IDTrx, it.IDToko}
+		itemsByPaket[key] = append(itemsByPaket[key], models.SellerOrderItemResponse{
+			NamaProduk: it.NamaProduk,
+			Kuantitas:  it.Kuantitas,
+			HargaTotal: it.HargaTotal,
+		})
+	}
+
+	destByID := make(map[int]entity.Destination, len(dests))
+	for _, d := range dests {
+		destByID[d.ID] = d
+	}
+
+	res := &models.SellerOrderListResponse{
+		Data:       make([]models.SellerOrderResponse, 0, len(orders)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+	}
+
+	for _, o := range orders {
+		order := models.SellerOrderResponse{
+			ID:             o.ID,
+			IDTrx:          o.IDTrx,
+			IDToko:         o.IDToko,
+			KodeInvoice:    o.KodeInvoice,
+			KodeInvoiceTrx: o.KodeInvoiceTrx,
+			MethodBayar:    o.MetodeBayar,
+			Status:         o.Status,
+			Kurir:          o.Kurir,
+			Layanan:        o.Layanan,
+			Berat:          o.Berat,
+			Ongkir:         o.Ongkir,
+			AlasanTolak:    o.AlasanTolak,
+			NoResi:         o.NoResi,
+			Items:          itemsByPaket[[2]int{o.IDTrx, o.IDToko}],
+			DiterimaPada:   o.DiterimaPada,
+			DikirimPada:    o.DikirimPada,
+			CreatedAt:      o.CreatedAt,
+		}
+
+		// penjual tidak perlu tahu apakah harga reseller ditampilkan ke pelanggan
+		if o.Dropship {
+			order.Dropship = &models.DropshipResponse{
+				NamaPengirim: o.NamaPengirim,
+				TelpPengirim: o.TelpPengirim,
+			}
+		}
+
+		if d, ok := destByID[o.AlamatPengiriman]; ok {
+			order.AlamatKirim = models.DestinationResponse{
+				ID:           d.ID,
+				JudulAlamat:  d.JudulAlamat,
+				NamaPenerima: d.NamaPenerima,
+				NoTelp:       d.NoTelp,
+				DetailAlamat: d.DetailAlamat,
+				IDKota:       d.IDKota,
+			}
+		}
+
+		res.Data = append(res.Data, order)
+	}
+
+	return res, nil
+}
+
+func (s *sellerOrderImpl) Accept(ctx context.Context, paketID int, userID int) *helper.ErrorStruct {
+	return s.act(ctx, paketID, userID, entity.TrxStatusProcessing, map[string]interface{}{
+		"diterima_pada": time.Now(),
+	}, "diterima penjual", "", nil)
+}
+
+// Reject membatalkan paket toko, mengembalikan stok baris milik toko tersebut dan mencatat
+// refund pembeli sebesar barang dan ongkir paket. Paket hanya bisa ditolak setelah dibayar.
+func (s *sellerOrderImpl) Reject(ctx context.Context, paketID int, userID int, req *models.RejectPaketRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	return s.act(ctx, paketID, userID, entity.TrxStatusCancelled, map[string]interface{}{
+		"alasan_tolak": req.Alasan,
+	}, "ditolak penjual", req.Alasan, nil)
+}
+
+// Ship menandai paket dikirim dan mencatat resinya untuk dilacak. Kurir kosong memakai kurir pilihan pembeli.
+func (s *sellerOrderImpl) Ship(ctx context.Context, paketID int, userID int, req *models.ShipPaketRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	keterangan := "resi " + req.NoResi
+	if req.Catatan != "" {
+		keterangan += ", " + req.Catatan
+	}
+
+	return s.act(ctx, paketID, userID, entity.TrxStatusShipped, map[string]interface{}{
+		"dikirim_pada": time.Now(),
+	}, "dikirim", keterangan, &entity.Pengiriman{
+		Kurir:  req.Kurir,
+		NoResi: req.NoResi,
+		Status: entity.PengirimanStatusInTransit,
+	})
+}
+
+// Deliver menandai paket toko sampai di pembeli, misalnya untuk kurir yang tidak bisa dilacak.
+// Status trx menjadi delivered setelah semua paket yang tidak dibatalkan sampai.
+func (s *sellerOrderImpl) Deliver(ctx context.Context, paketID int, userID int) *helper.ErrorStruct {
+	return s.act(ctx, paketID, userID, entity.TrxStatusDelivered, nil, "sampai menurut penjual", "", nil)
+}
+
+// act memindahkan satu paket lalu menurunkan ulang status trx dari seluruh paketnya.
+// pengiriman diisi saat paket dikirim. Urutan lock: trx lebih dulu, lalu semua paket milik trx.
+func (s *sellerOrderImpl) act(ctx context.Context, paketID int, userID int, to string, fields map[string]interface{}, aksi string, keterangan string, pengiriman *entity.Pengiriman) *helper.ErrorStruct {
+	paket, err := s.paketRepo.FindForSeller(ctx, paketID, userID)
+	if err != nil {
+		return paketError(err)
+	}
+
+	catatan := fmt.Sprintf("paket %d %s", paket.ID, aksi)
+	if keterangan != "" {
+		catatan += ": " + keterangan
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		trx, err := s.trxRepo.GetTransactionForUpdate(ctx, tx, paket.IDTrx)
+		if err != nil {
+			return err
+		}
+
+		pakets, err := s.paketRepo.ListByTrxForUpdate(ctx, tx, trx.ID)
+		if err != nil {
+			return err
+		}
+
+		var current *entity.PaketToko
+		for i := range pakets {
+			if pakets[i].ID == paket.ID {
+				current = &pakets[i]
+			}
+		}
+		if current == nil {
+			return ErrPaketNotFound
+		}
+
+		if !canPaketTransition(current.Status, to) {
+			return ErrInvalidPaketTransition
+		}
+
+		if err := s.paketRepo.UpdateStatus(ctx, tx, current.ID, current.Status, to, fields); err != nil {
+			return err
+		}
+		current.Status = to
+
+		if pengiriman != nil {
+			pengiriman.IDPaket = current.ID
+			pengiriman.IDTrx = trx.ID
+			pengiriman.IDToko = current.IDToko
+			if pengiriman.Kurir == "" {
+				pengiriman.Kurir = current.Kurir
+			}
+			if err := s.kirimRepo.Create(ctx, tx, pengiriman); err != nil {
+				return err
+			}
+		}
+
+		if to == entity.TrxStatusCancelled {
+			items, err := s.trxRepo.GetStockItemsByToko(ctx, tx, trx.ID, current.IDToko)
+			if err != nil {
+				return err
+			}
+			if err := s.status.restoreItems(ctx, tx, trx.ID, items); err != nil {
+				return err
+			}
+			if err := s.status.sales.rejected(ctx, tx, trx.ID, current.IDToko); err != nil {
+				return err
+			}
+			if err := s.status.komisi.rejected(ctx, tx, trx.ID, current.IDToko); err != nil {
+				return err
+			}
+			if err := s.status.refund.paket(ctx, tx, trx, current); err != nil {
+				return err
+			}
+		}
+
+		return s.status.derive(ctx, tx, trx, pakets, userID, catatan)
+	})
+
+	if err != nil {
+		return paketError(err)
+	}
+
+	return nil
+}
+
+// derive menyesuaikan status trx dengan status paket yang belum dibatalkan: semua dibatalkan -> cancelled,
+// semua sudah sampai -> delivered, semua sudah dikirim -> shipped, ada yang diproses -> processing
+func (m *TrxStatusMachine) derive(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, pakets []entity.PaketToko, userID int, catatan string) error {
+	minRank, maxRank := 0, 0
+	active := 0
+	for _, p := range pakets {
+		if p.Status == entity.TrxStatusCancelled {
+			continue
+		}
+		r := paketRank[p.Status]
+		if active == 0 || r < minRank {
+			minRank = r
+		}
+		if r > maxRank {
+			maxRank = r
+		}
+		active++
+	}
+
+	if active == 0 {
+		if err := m.move(ctx, tx, trx, entity.TrxStatusCancelled, entity.TrxActorSystem, userID, catatan); err != nil {
+			return err
+		}
+		return m.repo.MarkCancelled(ctx, tx, trx.ID, userID, catatan)
+	}
+
+	target := trx.Status
+	switch {
+	case minRank >= paketRank[entity.TrxStatusDelivered]:
+		target = entity.TrxStatusDelivered
+	case minRank >= paketRank[entity.TrxStatusShipped]:
+		target = entity.TrxStatusShipped
+	case maxRank >= paketRank[entity.TrxStatusProcessing]:
+		target = entity.TrxStatusProcessing
+	}
+
+	for paketRank[trx.Status] < paketRank[target] {
+		if err := m.move(ctx, tx, trx, paketNext[trx.Status], entity.TrxActorSystem, userID, catatan); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func canPaketTransition(from string, to string) bool {
+	for _, s := range paketTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func paketError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrPaketNotFound):
+		return &helper.ErrorStruct{Err: ErrPaketNotFound, Code: 404}
+	case errors.Is(err, ErrInvalidPaketTransition):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	}
+	return trxError(err)
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/utils/pdf"
+	"strconv"
+	"strings"
+)
+
+var ErrLabelFormat = errors.New("format label harus pdf atau html")
+
+// Label membuat label pengiriman paket milik toko user. Pesanan dropship memakai
+// nama dan telepon reseller sebagai pengirim, harga tidak pernah dicetak.
+func (s *sellerOrderImpl) Label(ctx context.Context, paketID int, userID int, format string) (*models.InvoiceFile, *helper.ErrorStruct) {
+	if format == "" {
+		format = "pdf"
+	}
+	if format != "pdf" && format != "html" {
+		return nil, &helper.ErrorStruct{Err: ErrLabelFormat, Code: 400}
+	}
+
+	paket, err := s.paketRepo.FindForSeller(ctx, paketID, userID)
+	if err != nil {
+		return nil, paketError(err)
+	}
+
+	label, err := s.buildLabel(ctx, paket)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	name := "label-" + strings.ReplaceAll(label.KodeInvoice, "/", "-")
+	if label.KodeInvoice == "" {
+		name = "label-" + strconv.Itoa(paket.ID)
+	}
+
+	if format == "html" {
+		body, err := renderLabelHTML(label)
+		if err != nil {
+			return nil, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+		return &models.InvoiceFile{FileName: name + ".html", ContentType: "text/html; charset=utf-8", Body: body}, nil
+	}
+
+	return &models.InvoiceFile{FileName: name + ".pdf", ContentType: "application/pdf", Body: renderLabelPDF(label)}, nil
+}
+
+func (s *sellerOrderImpl) buildLabel(ctx context.Context, paket *entity.PaketToko) (*models.ShippingLabel, error) {
+	trx, err := s.trxRepo.FindTransactionByID(ctx, paket.IDTrx)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.trxRepo.GetTransactionDetails(ctx, trx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dests, err := s.destRepo.FindByIDs(ctx, []int{trx.AlamatPengiriman})
+	if err != nil {
+		return nil, err
+	}
+
+	pengirimans, err := s.kirimRepo.ListByTrx(ctx, trx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	label := &models.ShippingLabel{
+		KodeInvoice:  paket.KodeInvoice,
+		Kurir:        paket.Kurir,
+		Layanan:      paket.Layanan,
+		Berat:        paket.Berat,
+		NamaPengirim: trx.NamaPengirim,
+		TelpPengirim: trx.TelpPengirim,
+	}
+
+	if len(dests) > 0 {
+		label.NamaPenerima = dests[0].NamaPenerima
+		label.NoTelp = dests[0].NoTelp
+		label.DetailAlamat = dests[0].DetailAlamat
+	}
+
+	for _, k := range pengirimans {
+		if k.IDPaket == paket.ID {
+			label.Kurir = k.Kurir
+			label.NoResi = k.NoResi
+		}
+	}
+
+	for _, d := range details {
+		if d.IDToko != paket.IDToko {
+			continue
+		}
+		if !trx.Dropship {
+			label.NamaPengirim = d.NamaToko
+		}
+		label.Items = append(label.Items, models.ShippingLabelItem{
+			NamaProduk: d.NamaProduk,
+			Kuantitas:  d.Kuantitas,
+		})
+	}
+
+	return label, nil
+}
+
+// dropshipResponse identitas pengirim pesanan dropship, nil untuk pesanan biasa
+func dropshipResponse(trx *entity.Transaction) *models.DropshipResponse {
+	if !trx.Dropship {
+		return nil
+	}
+
+	return &models.DropshipResponse{
+		NamaPengirim:           trx.NamaPengirim,
+		TelpPengirim:           trx.TelpPengirim,
+		TampilkanHargaReseller: trx.TampilkanHargaReseller,
+	}
+}
+
+var labelTemplate = template.Must(template.New("label").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Label {{.KodeInvoice}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; max-width: 480px; margin: 32px auto; }
+.label { border: 2px solid #222; padding: 12px; }
+.kurir { font-size: 20px; font-weight: bold; }
+.resi { font-size: 16px; letter-spacing: 1px; margin-top: 4px; }
+.blok { border-top: 1px dashed #999; margin-top: 12px; padding-top: 8px; }
+.blok strong { display: block; font-size: 11px; text-transform: uppercase; color: #555; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 2px 0; }
+.num { text-align: right; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<div class="label">
+<div class="kurir">{{.Kurir}} {{.Layanan}}</div>
+{{if .NoResi}}<div class="resi">Resi: {{.NoResi}}</div>{{end}}
+<div>{{.KodeInvoice}} &middot; {{.Berat}} gram</div>
+<div class="blok"><strong>Penerima</strong>{{.NamaPenerima}} ({{.NoTelp}})<br>{{.DetailAlamat}}</div>
+<div class="blok"><strong>Pengirim</strong>{{.NamaPengirim}}{{if .TelpPengirim}} ({{.TelpPengirim}}){{end}}</div>
+<div class="blok"><strong>Isi paket</strong>
+<table>
+{{range .Items}}<tr><td>{{.NamaProduk}}</td><td class="num">x{{.Kuantitas}}</td></tr>
+{{end}}</table>
+</div>
+</div>
+</body>
+</html>
+`))
+
+func renderLabelHTML(label *models.ShippingLabel) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := labelTemplate.Execute(&buf, label); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderLabelPDF(label *models.ShippingLabel) []byte {
+	const (
+		left  = 40.0
+		right = 320.0
+		width = right - left
+	)
+
+	p := pdf.New()
+	y := 60.0
+
+	p.Text(left, y, 16, true, pdf.Fit(label.Kurir+" "+label.Layanan, width, 16, true))
+	y += 18
+	if label.NoResi != "" {
+		p.Text(left, y, 12, true, "Resi: "+label.NoResi)
+		y += 16
+	}
+	p.Text(left, y, 9, false, label.KodeInvoice+" - "+strconv.Itoa(label.Berat)+" gram")
+	y += 10
+
+	blok := func(judul string, lines ...string) {
+		y += 8
+		p.Line(left, y, right, y)
+		y += 14
+		p.Text(left, y, 8, true, judul)
+		for _, line := range lines {
+			y += 13
+			p.Text(left, y, 10, false, pdf.Fit(line, width, 10, false))
+		}
+		y += 6
+	}
+
+	blok("PENERIMA", label.NamaPenerima+" ("+label.NoTelp+")", label.DetailAlamat)
+
+	pengirim := label.NamaPengirim
+	if label.TelpPengirim != "" {
+		pengirim += " (" + label.TelpPengirim + ")"
+	}
+	blok("PENGIRIM", pengirim)
+
+	items := make([]string, 0, len(label.Items))
+	for _, item := range label.Items {
+		items = append(items, strconv.Itoa(item.Kuantitas)+"x "+item.NamaProduk)
+	}
+	blok("ISI PAKET", items...)
+
+	p.Line(left, y, right, y)
+
+	return p.Bytes()
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"pbi/internal/utils/money"
+	"sort"
+)
+
+// Zona tarif berdasarkan kode wilayah kota asal dan tujuan
+const (
+	ZonaDalamKota     = "dalam_kota"
+	ZonaDalamProvinsi = "dalam_provinsi"
+	ZonaSatuPulau     = "satu_pulau"
+	ZonaAntarPulau    = "antar_pulau"
+)
+
+var ErrShippingRoute = errors.New("kota asal atau tujuan tidak valid")
+
+// ShippingRate satu pilihan layanan kurir beserta tarifnya
+type ShippingRate struct {
+	Kurir    string
+	Layanan  string
+	Ongkir   money.Rupiah
+	Estimasi string
+}
+
+// ShippingRateProvider menghitung ongkir dari kota asal ke kota tujuan untuk berat (gram) tertentu
+type ShippingRateProvider interface {
+	Name() string
+	Rates(ctx context.Context, asalKota string, tujuanKota string, berat int) ([]ShippingRate, error)
+}
+
+type zoneRate struct {
+	PerKg    money.Rupiah
+	Estimasi string
+}
+
+// tarif per kg untuk tiap kurir, layanan dan zona
+var defaultRateTable = map[string]map[string]map[string]zoneRate{
+	"jne": {
+		"REG": {
+			ZonaDalamKota:     {PerKg: 9000, Estimasi: "1-2 hari"},
+			ZonaDalamProvinsi: {PerKg: 12000, Estimasi: "2-3 hari"},
+			ZonaSatuPulau:     {PerKg: 18000, Estimasi: "2-4 hari"},
+			ZonaAntarPulau:    {PerKg: 32000, Estimasi: "3-6 hari"},
+		},
+		"YES": {
+			ZonaDalamKota:     {PerKg: 18000, Estimasi: "1 hari"},
+			ZonaDalamProvinsi: {PerKg: 24000, Estimasi: "1 hari"},
+			ZonaSatuPulau:     {PerKg: 32000, Estimasi: "1-2 hari"},
+			ZonaAntarPulau:    {PerKg: 55000, Estimasi: "2-3 hari"},
+		},
+	},
+	"jnt": {
+		"EZ": {
+			ZonaDalamKota:     {PerKg: 8000, Estimasi: "1-2 hari"},
+			ZonaDalamProvinsi: {PerKg: 11000, Estimasi: "2-3 hari"},
+			ZonaSatuPulau:     {PerKg: 17000, Estimasi: "2-4 hari"},
+			ZonaAntarPulau:    {PerKg: 30000, Estimasi: "4-7 hari"},
+		},
+	},
+	"sicepat": {
+		"REG": {
+			ZonaDalamKota:     {PerKg: 8500, Estimasi: "1-2 hari"},
+			ZonaDalamProvinsi: {PerKg: 11500, Estimasi: "2-3 hari"},
+			ZonaSatuPulau:     {PerKg: 17500, Estimasi: "2-4 hari"},
+			ZonaAntarPulau:    {PerKg: 31000, Estimasi: "3-6 hari"},
+		},
+		"BEST": {
+			ZonaDalamKota:     {PerKg: 16000, Estimasi: "1 hari"},
+			ZonaDalamProvinsi: {PerKg: 22000, Estimasi: "1 hari"},
+			ZonaSatuPulau:     {PerKg: 30000, Estimasi: "1-2 hari"},
+			ZonaAntarPulau:    {PerKg: 50000, Estimasi: "2-3 hari"},
+		},
+	},
+}
+
+// tableRateProvider provider bawaan berbasis tabel tarif, tanpa API kurir
+type tableRateProvider struct {
+	table map[string]map[string]map[string]zoneRate
+}
+
+func NewTableShippingProvider() ShippingRateProvider {
+	return &tableRateProvider{table: defaultRateTable}
+}
+
+func (p *tableRateProvider) Name() string { return "table" }
+
+// Rates membulatkan berat ke atas per kg (minimal 1 kg)
+func (p *tableRateProvider) Rates(ctx context.Context, asalKota string, tujuanKota string, berat int) ([]ShippingRate, error) {
+	zona, err := shippingZone(asalKota, tujuanKota)
+	if err != nil {
+		return nil, err
+	}
+
+	kg := (berat + 999) / 1000
+	if kg < 1 {
+		kg = 1
+	}
+
+	var rates []ShippingRate
+	for kurir, services := range p.table {
+		for layanan, zones := range services {
+			rate, ok := zones[zona]
+			if !ok {
+				continue
+			}
+			rates = append(rates, ShippingRate{
+				Kurir:    kurir,
+				Layanan:  layanan,
+				Ongkir:   rate.PerKg.Times(kg),
+				Estimasi: rate.Estimasi,
+			})
+		}
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Ongkir != rates[j].Ongkir {
+			return rates[i].Ongkir < rates[j].Ongkir
+		}
+		return rates[i].Kurir+rates[i].Layanan < rates[j].Kurir+rates[j].Layanan
+	})
+
+	return rates, nil
+}
+
+// shippingZone memakai kode wilayah kemendagri: 2 digit pertama provinsi,
+// digit pertama kelompok pulau (1 Sumatera, 3 Jawa, 5 Bali-Nusa Tenggara, dst)
+func shippingZone(asalKota string, tujuanKota string) (string, error) {
+	if len(asalKota) < 4 || len(tujuanKota) < 4 {
+		return "", ErrShippingRoute
+	}
+
+	switch {
+	case asalKota == tujuanKota:
+		return ZonaDalamKota, nil
+	case asalKota[:2] == tujuanKota[:2]:
+		return ZonaDalamProvinsi, nil
+	case asalKota[:1] == tujuanKota[:1]:
+		return ZonaSatuPulau, nil
+	}
+	return ZonaAntarPulau, nil
+}
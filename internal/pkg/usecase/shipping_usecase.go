@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShippingOriginUnset      = errors.New("kota asal toko belum diatur")
+	ErrShippingDestinationUnset = errors.New("kota pada alamat pengiriman belum diatur")
+	ErrShippingNotSelected      = errors.New("kurir belum dipilih untuk toko")
+	ErrShippingUnavailable      = errors.New("layanan kurir tidak tersedia")
+)
+
+type ShippingUsecase interface {
+	Quote(ctx context.Context, userID int, req *models.ShippingQuoteRequest) ([]models.ShippingQuoteResponse, *helper.ErrorStruct)
+}
+
+type shippingImpl struct {
+	destRepo repository.DestinationRepository
+	prepo    repository.ProductRepository
+	provider ShippingRateProvider
+}
+
+func NewShippingUsecase(destRepo repository.DestinationRepository, prepo repository.ProductRepository, provider ShippingRateProvider) ShippingUsecase {
+	return &shippingImpl{
+		destRepo: destRepo,
+		prepo:    prepo,
+		provider: provider,
+	}
+}
+
+// Quote menghitung pilihan kurir per toko untuk item yang akan di-checkout
+func (s *shippingImpl) Quote(ctx context.Context, userID int, req *models.ShippingQuoteRequest) ([]models.ShippingQuoteResponse, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	idKota := req.IDKota
+	if req.AlamatKirim != 0 {
+		dest, err := s.destRepo.FindByID(ctx, req.AlamatKirim, userID)
+		if err != nil {
+			return nil, &helper.ErrorStruct{Err: errors.New("alamat pengiriman tidak valid"), Code: 404}
+		}
+		idKota = dest.IDKota
+	}
+
+	var res []models.ShippingQuoteResponse
+	index := make(map[int]int)
+	asal := make(map[int]string)
+
+	for _, item := range req.Items {
+		produk, err := s.prepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &helper.ErrorStruct{Err: errors.New("produk tidak ditemukan"), Code: 404}
+			}
+			return nil, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+
+		i, ok := index[produk.IDToko]
+		if !ok {
+			quote := models.ShippingQuoteResponse{IDToko: produk.IDToko}
+			if produk.Toko != nil {
+				quote.NamaToko = produk.Toko.NamaToko
+				asal[produk.IDToko] = produk.Toko.IDKota
+			}
+			res = append(res, quote)
+			i = len(res) - 1
+			index[produk.IDToko] = i
+		}
+		res[i].Berat += produk.Berat * item.Kuantitas
+	}
+
+	calc := newShippingCalculator(s.provider)
+	for i := range res {
+		rates, err := calc.rates(ctx, asal[res[i].IDToko], idKota, res[i].Berat)
+		if err != nil {
+			return nil, checkoutError(err)
+		}
+
+		res[i].Layanan = make([]models.ShippingRateResponse, 0, len(rates))
+		for _, r := range rates {
+			res[i].Layanan = append(res[i].Layanan, models.ShippingRateResponse{
+				Kurir:    r.Kurir,
+				Layanan:  r.Layanan,
+				Ongkir:   r.Ongkir,
+				Estimasi: r.Estimasi,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// shippingCalculator dipakai bersama oleh quote dan checkout supaya tarifnya selalu sama
+type shippingCalculator struct {
+	provider ShippingRateProvider
+}
+
+func newShippingCalculator(provider ShippingRateProvider) *shippingCalculator {
+	return &shippingCalculator{
+		provider: provider,
+	}
+}
+
+func (c *shippingCalculator) rates(ctx context.Context, asalKota string, tujuanKota string, berat int) ([]ShippingRate, error) {
+	if asalKota == "" {
+		return nil, ErrShippingOriginUnset
+	}
+	if tujuanKota == "" {
+		return nil, ErrShippingDestinationUnset
+	}
+
+	return c.provider.Rates(ctx, asalKota, tujuanKota, berat)
+}
+
+// pick menghitung ulang ongkir untuk layanan yang dipilih pembeli
+func (c *shippingCalculator) pick(ctx context.Context, asalKota string, tujuanKota string, berat int, kurir string, layanan string) (*ShippingRate, error) {
+	rates, err := c.rates(ctx, asalKota, tujuanKota, berat)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rates {
+		if r.Kurir == kurir && r.Layanan == layanan {
+			return &r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrShippingUnavailable, kurir, layanan)
+}
+
+// packages membuat paket_toko untuk setiap toko di pesanan dan mengembalikan total ongkir
+func (c *shippingCalculator) packages(ctx context.Context, trxID int, tujuanKota string, lines []*checkoutLine, selections []models.CreateTrxShippingRequest) ([]*entity.PaketToko, money.Rupiah, error) {
+	chosen := make(map[int]models.CreateTrxShippingRequest, len(selections))
+	for _, sel := range selections {
+		chosen[sel.IDToko] = sel
+	}
+
+	var (
+		pakets []*entity.PaketToko
+		asal   = make(map[int]string)
+		byToko = make(map[int]*entity.PaketToko)
+	)
+	for _, line := range lines {
+		paket, ok := byToko[line.Produk.IDToko]
+		if !ok {
+			paket = &entity.PaketToko{IDTrx: trxID, IDToko: line.Produk.IDToko, Status: entity.TrxStatusPendingPayment}
+			byToko[line.Produk.IDToko] = paket
+			asal[line.Produk.IDToko] = line.Produk.IDKotaToko
+			pakets = append(pakets, paket)
+		}
+		paket.Berat += line.Produk.Berat * line.Kuantitas
+	}
+
+	var total money.Rupiah
+	for _, paket := range pakets {
+		sel, ok := chosen[paket.IDToko]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w %d", ErrShippingNotSelected, paket.IDToko)
+		}
+
+		rate, err := c.pick(ctx, asal[paket.IDToko], tujuanKota, paket.Berat, sel.Kurir, sel.Layanan)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		paket.Kurir = rate.Kurir
+		paket.Layanan = rate.Layanan
+		paket.Ongkir = rate.Ongkir
+		paket.Estimasi = rate.Estimasi
+		total += rate.Ongkir
+	}
+
+	return pakets, total, nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// stockReserver menahan stok saat checkout. Stok fisik baru dipotong ketika pesanan
+// dibayar, reservasi dilepas bila pesanan dibatalkan atau melewati batas bayar.
+type stockReserver struct {
+	repo   repository.ReservasiRepository
+	events *eventOutbox
+}
+
+func newStockReserver(repo repository.ReservasiRepository, events *eventOutbox) *stockReserver {
+	return &stockReserver{
+		repo:   repo,
+		events: events,
+	}
+}
+
+// hold menahan qty untuk trx, baris produk harus sudah di-lock oleh pemanggil
+func (s *stockReserver) hold(ctx context.Context, tx *gorm.DB, trxID int, produk *entity.ProdukWithOwner, qty int, expiresAt time.Time) error {
+	if produk.StokTersedia() < qty {
+		return ErrStockNotEnough
+	}
+
+	reservasi := &entity.StokReservasi{
+		IDTrx:     trxID,
+		IDProduk:  produk.ID,
+		Kuantitas: qty,
+		Status:    entity.ReservasiStatusActive,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(ctx, tx, reservasi); err != nil {
+		return err
+	}
+
+	if err := s.repo.AdjustProduk(ctx, tx, produk.ID, 0, qty); err != nil {
+		return err
+	}
+
+	return s.events.stockChanged(ctx, tx, produk.ID, entity.StokSebabDitahan, trxID)
+}
+
+// commit memotong stok permanen untuk semua reservasi aktif trx
+func (s *stockReserver) commit(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return s.settle(ctx, tx, trxID, entity.ReservasiStatusCommitted)
+}
+
+// release mengembalikan stok yang ditahan trx tanpa menyentuh stok fisik
+func (s *stockReserver) release(ctx context.Context, tx *gorm.DB, trxID int) error {
+	return s.settle(ctx, tx, trxID, entity.ReservasiStatusReleased)
+}
+
+// settle dipanggil dengan baris trx sudah di-lock
+func (s *stockReserver) settle(ctx context.Context, tx *gorm.DB, trxID int, to string) error {
+	holds, err := s.repo.ListActiveByTrx(ctx, tx, trxID)
+	if err != nil {
+		return err
+	}
+
+	sebab := entity.StokSebabDilepas
+	if to == entity.ReservasiStatusCommitted {
+		sebab = entity.StokSebabDibayar
+	}
+
+	ids := make([]int, 0, len(holds))
+	for _, h := range holds {
+		stok := 0
+		if to == entity.ReservasiStatusCommitted {
+			stok = -h.Kuantitas
+		}
+		if err := s.repo.AdjustProduk(ctx, tx, h.IDProduk, stok, -h.Kuantitas); err != nil {
+			return err
+		}
+		if err := s.events.stockChanged(ctx, tx, h.IDProduk, sebab, trxID); err != nil {
+			return err
+		}
+		ids = append(ids, h.ID)
+	}
+
+	return s.repo.SetStatus(ctx, tx, ids, entity.ReservasiStatusActive, to)
+}
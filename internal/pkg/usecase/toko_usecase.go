@@ -9,6 +9,7 @@ import (
 	"pbi/internal/pkg/models"
 	"pbi/internal/pkg/repository"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -130,6 +131,16 @@ func (uc *tokoUsecaseImpl) Update(ctx context.Context, tokoID string, userID int
 	if req.UrlFoto != "" {
 		toko.UrlFoto = req.UrlFoto
 	}
+	if req.IDKota != "" {
+		toko.IDKota = req.IDKota
+	}
+	if req.PrefixInvoice != "" {
+		prefix := strings.ToUpper(req.PrefixInvoice)
+		if !invoiceCodePattern.MatchString(prefix) {
+			return nil, &helper.ErrorStruct{Code: fiber.StatusBadRequest, Err: errors.New("prefix invoice harus 2-16 huruf/angka")}
+		}
+		toko.PrefixInvoice = prefix
+	}
 
 	updated, errRepo := uc.tokoRepo.Update(ctx, id, toko)
 	if errRepo != nil {
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TrackingEvent satu riwayat pelacakan dari kurir. Provider menerjemahkan status kurir
+// ke entity.PengirimanEventDelivered saat paket sudah diterima.
+type TrackingEvent struct {
+	Status     string    `json:"status"`
+	Keterangan string    `json:"keterangan"`
+	Lokasi     string    `json:"lokasi"`
+	Waktu      time.Time `json:"waktu"`
+}
+
+// TrackingProvider mengambil seluruh riwayat pelacakan sebuah resi
+type TrackingProvider interface {
+	Name() string
+	Track(ctx context.Context, kurir string, noResi string) ([]TrackingEvent, error)
+}
+
+// fileTrackingProvider stub lokal yang membaca riwayat dari file JSON berbentuk
+// {"<no_resi>": [{"status": "...", "keterangan": "...", "lokasi": "...", "waktu": "RFC3339"}]}.
+// File dibaca ulang setiap kali melacak sehingga bisa diubah selama server berjalan.
+type fileTrackingProvider struct {
+	path string
+}
+
+func NewFileTrackingProvider(path string) TrackingProvider {
+	return &fileTrackingProvider{path: path}
+}
+
+func (p *fileTrackingProvider) Name() string { return "file" }
+
+// Track resi yang tidak ada di file dianggap belum punya riwayat
+func (p *fileTrackingProvider) Track(ctx context.Context, kurir string, noResi string) ([]TrackingEvent, error) {
+	raw, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string][]TrackingEvent
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("file tracking %s: %w", p.path, err)
+	}
+
+	return data[noResi], nil
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"errors"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/utils/money"
+)
+
+// maxCheckoutAttempts batas percobaan ulang checkout yang kalah deadlock
+const maxCheckoutAttempts = 3
+
+var ErrProductNotFound = errors.New("produk tidak ditemukan")
+
+// checkoutLine satu baris pesanan selama proses checkout, sebelum detail_trx disimpan
+type checkoutLine struct {
+	Produk    *entity.ProdukWithOwner
+	LogProduk *entity.LogProduk
+	Kuantitas int
+	Subtotal  money.Rupiah
+	Diskon    money.Rupiah
+}
+
+// Total harga baris setelah diskon
+func (l *checkoutLine) Total() money.Rupiah {
+	return l.Subtotal - l.Diskon
+}
+
+// mergeCheckoutItems menjumlahkan kuantitas product_id yang sama,
+// urutan kemunculan pertama dipertahankan untuk urutan detail_trx
+func mergeCheckoutItems(items []models.CreateTrxItemRequest) []models.CreateTrxItemRequest {
+	index := make(map[int]int, len(items))
+	merged := make([]models.CreateTrxItemRequest, 0, len(items))
+
+	for _, item := range items {
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Kuantitas += item.Kuantitas
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged
+}
@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"pbi/internal/config"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestMergeCheckoutItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []models.CreateTrxItemRequest
+		want  []models.CreateTrxItemRequest
+	}{
+		{
+			name:  "kosong",
+			items: nil,
+			want:  []models.CreateTrxItemRequest{},
+		},
+		{
+			name:  "tanpa duplikat",
+			items: []models.CreateTrxItemRequest{{ProductID: 2, Kuantitas: 1}, {ProductID: 1, Kuantitas: 3}},
+			want:  []models.CreateTrxItemRequest{{ProductID: 2, Kuantitas: 1}, {ProductID: 1, Kuantitas: 3}},
+		},
+		{
+			name:  "produk sama dijumlahkan",
+			items: []models.CreateTrxItemRequest{{ProductID: 5, Kuantitas: 1}, {ProductID: 5, Kuantitas: 2}},
+			want:  []models.CreateTrxItemRequest{{ProductID: 5, Kuantitas: 3}},
+		},
+		{
+			name: "urutan kemunculan pertama dipertahankan",
+			items: []models.CreateTrxItemRequest{
+				{ProductID: 3, Kuantitas: 1},
+				{ProductID: 1, Kuantitas: 1},
+				{ProductID: 3, Kuantitas: 4},
+				{ProductID: 2, Kuantitas: 1},
+				{ProductID: 1, Kuantitas: 2},
+			},
+			want: []models.CreateTrxItemRequest{
+				{ProductID: 3, Kuantitas: 5},
+				{ProductID: 1, Kuantitas: 3},
+				{ProductID: 2, Kuantitas: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input []models.CreateTrxItemRequest
+			if tt.items != nil {
+				input = append(input, tt.items...)
+			}
+
+			got := mergeCheckoutItems(input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeCheckoutItems = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(input, tt.items) && tt.items != nil {
+				t.Errorf("input ikut berubah: %v", input)
+			}
+		})
+	}
+}
+
+// TestCreateTransactionLastStock menjalankan checkout paralel dari banyak pembeli untuk unit
+// terakhir satu produk. Hanya pesanan yang muat di stok yang boleh berhasil, sisanya gagal
+// dengan ErrStockNotEnough, dan stok tidak pernah negatif setelah semua pesanan dibayar.
+func TestCreateTransactionLastStock(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name    string
+		stok    int
+		buyers  int
+		qty     int
+		success int
+	}{
+		{"satu unit per pembeli", 3, 10, 1, 3},
+		{"dua unit per pembeli", 5, 8, 2, 2},
+		{"stok habis tepat", 4, 4, 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			seed := newTestSeed(t, db)
+
+			sellerID := seed.user()
+			tokoID := seed.toko(sellerID, "3171")
+			produkID := seed.produk(tokoID, 15000, 15000, tt.stok)
+
+			buyers := make([]int, tt.buyers)
+			for i := range buyers {
+				buyers[i] = seed.user()
+			}
+
+			trxUsc, status := newTestTransactionUsecase(db)
+
+			var (
+				wg      sync.WaitGroup
+				start   = make(chan struct{})
+				mu      sync.Mutex
+				trxIDs  []int
+				failErr []error
+			)
+			for _, buyerID := range buyers {
+				wg.Add(1)
+				go func(buyerID int) {
+					defer wg.Done()
+					<-start
+
+					id, herr := trxUsc.CreateTransaction(ctx, buyerID, "", &models.CreateTrxRequest{
+						MethodBayar: "ovo",
+						Dropship: &models.DropshipRequest{
+							NamaPenerima: "penerima",
+							NoTelp:       "0811",
+							DetailAlamat: "jalan uji",
+							IDKota:       "3171",
+							NamaPengirim: "reseller",
+							TelpPengirim: "0812",
+						},
+						DetailTrx:  []models.CreateTrxItemRequest{{ProductID: produkID, Kuantitas: tt.qty}},
+						Pengiriman: []models.CreateTrxShippingRequest{{IDToko: tokoID, Kurir: "sicepat", Layanan: "REG"}},
+					})
+
+					mu.Lock()
+					defer mu.Unlock()
+					if herr != nil {
+						failErr = append(failErr, herr.Err)
+						return
+					}
+					trxIDs = append(trxIDs, id)
+				}(buyerID)
+			}
+			close(start)
+			wg.Wait()
+
+			if len(trxIDs) != tt.success {
+				t.Fatalf("checkout berhasil = %d, want %d (gagal: %v)", len(trxIDs), tt.success, failErr)
+			}
+			for _, err := range failErr {
+				if !errors.Is(err, ErrStockNotEnough) {
+					t.Errorf("checkout gagal dengan %v, want %v", err, ErrStockNotEnough)
+				}
+			}
+
+			produk := loadTestProduk(t, db, produkID)
+			if produk.Stok != tt.stok || produk.StokDitahan != tt.success*tt.qty {
+				t.Fatalf("setelah checkout stok = %d ditahan = %d, want %d dan %d", produk.Stok, produk.StokDitahan, tt.stok, tt.success*tt.qty)
+			}
+			if produk.Stok-produk.StokDitahan < 0 {
+				t.Fatalf("stok tersedia negatif: %d", produk.Stok-produk.StokDitahan)
+			}
+
+			// pembayaran memotong stok fisik dari reservasi
+			for _, id := range trxIDs {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					trx, err := status.repo.GetTransactionForUpdate(ctx, tx, id)
+					if err != nil {
+						return err
+					}
+					return status.change(ctx, tx, trx, entity.TrxStatusPaid, entity.TrxActorSystem, 0, "uji")
+				})
+				if err != nil {
+					t.Fatalf("bayar trx %d: %v", id, err)
+				}
+			}
+
+			produk = loadTestProduk(t, db, produkID)
+			want := tt.stok - tt.success*tt.qty
+			if produk.Stok != want || produk.StokDitahan != 0 {
+				t.Fatalf("setelah dibayar stok = %d ditahan = %d, want %d dan 0", produk.Stok, produk.StokDitahan, want)
+			}
+			if produk.Stok < 0 {
+				t.Fatalf("stok negatif: %d", produk.Stok)
+			}
+		})
+	}
+}
+
+func newTestTransactionUsecase(db *gorm.DB) (TransactionUsecase, *TrxStatusMachine) {
+	trxRepo := repository.NewTransactionRepo(db)
+	paketRepo := repository.NewPaketRepo(db)
+	reservasiRepo := repository.NewReservasiRepo(db)
+	voucherRepo := repository.NewVoucherRepo(db)
+
+	status := NewTrxStatusMachine(TrxStatusDeps{
+		TrxRepo:       trxRepo,
+		PaketRepo:     paketRepo,
+		ReservasiRepo: reservasiRepo,
+		AnalyticsRepo: repository.NewAnalyticsRepo(db),
+		KomisiRepo:    repository.NewKomisiRepo(db),
+		SaldoRepo:     repository.NewSaldoRepo(db),
+		OutboxRepo:    repository.NewOutboxRepo(db),
+		VoucherRepo:   voucherRepo,
+		ReturRepo:     repository.NewReturRepo(db),
+		PaymentRepo:   repository.NewPaymentRepo(db),
+	})
+
+	usc := NewTransactionUsecase(db, trxRepo, repository.NewDestinationRepo(db), repository.NewIdempotencyRepo(db), time.Hour,
+		voucherRepo, paketRepo, NewTableShippingProvider(), repository.NewInvoiceRepo(db),
+		config.InvoiceConfig{Prefix: "INV", PlatformCode: "TEST", Period: "daily"},
+		reservasiRepo, time.Hour, status)
+
+	return usc, status
+}
+
+func loadTestProduk(t *testing.T, db *gorm.DB, produkID int) *entity.Produk {
+	t.Helper()
+
+	var produk entity.Produk
+	if err := db.Where("id = ?", produkID).First(&produk).Error; err != nil {
+		t.Fatalf("load produk: %v", err)
+	}
+	return &produk
+}
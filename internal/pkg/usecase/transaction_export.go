@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/utils/xlsx"
+	"strconv"
+	"time"
+)
+
+var ErrExportFormat = errors.New("format export harus csv atau xlsx")
+
+// exportFlushRows jumlah baris sebelum data diteruskan ke client
+const exportFlushRows = 500
+
+var trxExportHeader = []string{
+	"id_trx", "kode_invoice", "tanggal", "status", "metode_bayar", "id_pembeli",
+	"id_toko", "nama_toko", "kode_invoice_paket", "kurir", "ongkir_paket",
+	"id_detail", "id_produk", "nama_produk", "slug", "kategori",
+	"harga_konsumen", "harga_reseller", "kuantitas", "diskon", "total",
+}
+
+// flusher writer tujuan yang menahan data, misalnya bufio.Writer milik response
+type flusher interface {
+	Flush() error
+}
+
+// Export menyiapkan export baris detail transaksi. Query baru dijalankan saat Write dipanggil,
+// baris ditulis satu per satu sehingga export besar tidak dimuat ke memori.
+func (t *transactionImpl) Export(ctx context.Context, filter *entity.TrxFilter, format string) (*models.ExportStream, *helper.ErrorStruct) {
+	if filter.Sort != "" && filter.Sort != "tanggal" && filter.Sort != "harga" {
+		return nil, &helper.ErrorStruct{Err: ErrTrxSort, Code: 400}
+	}
+	if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+		return nil, &helper.ErrorStruct{Err: ErrTrxOrder, Code: 400}
+	}
+
+	name := "transaksi-" + time.Now().Format("20060102-150405")
+
+	switch format {
+	case "", "csv":
+		return &models.ExportStream{
+			FileName:    name + ".csv",
+			ContentType: "text/csv; charset=utf-8",
+			Write: func(ctx context.Context, w io.Writer) error {
+				return t.exportCSV(ctx, filter, w)
+			},
+		}, nil
+	case "xlsx":
+		return &models.ExportStream{
+			FileName:    name + ".xlsx",
+			ContentType: xlsx.ContentType,
+			Write: func(ctx context.Context, w io.Writer) error {
+				return t.exportXLSX(ctx, filter, w)
+			},
+		}, nil
+	}
+
+	return nil, &helper.ErrorStruct{Err: ErrExportFormat, Code: 400}
+}
+
+func (t *transactionImpl) exportCSV(ctx context.Context, filter *entity.TrxFilter, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(trxExportHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := t.repo.StreamTransactionLines(ctx, filter, func(line *entity.TrxExportLine) error {
+		values := trxExportRow(line)
+		record := make([]string, len(values))
+		for i, v := range values {
+			switch n := v.(type) {
+			case string:
+				record[i] = n
+			case int:
+				record[i] = strconv.Itoa(n)
+			case int64:
+				record[i] = strconv.FormatInt(n, 10)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			return flushWriter(w)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (t *transactionImpl) exportXLSX(ctx context.Context, filter *entity.TrxFilter, w io.Writer) error {
+	xw, err := xlsx.NewWriter(w, "Transaksi")
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(trxExportHeader))
+	for i, h := range trxExportHeader {
+		header[i] = h
+	}
+	if err := xw.WriteRow(header); err != nil {
+		return err
+	}
+
+	rows := 0
+	err = t.repo.StreamTransactionLines(ctx, filter, func(line *entity.TrxExportLine) error {
+		if err := xw.WriteRow(trxExportRow(line)); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			if err := xw.Flush(); err != nil {
+				return err
+			}
+			return flushWriter(w)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return xw.Close()
+}
+
+// trxExportRow urutan kolom sama dengan trxExportHeader, nominal ditulis sebagai angka rupiah polos
+func trxExportRow(line *entity.TrxExportLine) []interface{} {
+	return []interface{}{
+		line.IDTrx, line.KodeInvoice, line.Tanggal.Format("2006-01-02 15:04:05"), line.Status, line.MetodeBayar, line.IDPembeli,
+		line.IDToko, line.NamaToko, line.KodeInvoicePaket, line.Kurir, int64(line.OngkirPaket),
+		line.IDDetail, line.IDProduk, line.NamaProduk, line.Slug, line.NamaCategory,
+		int64(line.HargaKonsumen), int64(line.HargaReseller), line.Kuantitas, int64(line.Diskon), int64(line.HargaTotal),
+	}
+}
+
+func flushWriter(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"pbi/internal/helper"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("Idempotency-Key sudah dipakai untuk request yang berbeda")
+	ErrIdempotencyInFlight   = errors.New("request dengan Idempotency-Key yang sama masih diproses")
+	ErrIdempotencyKeyTooLong = errors.New("Idempotency-Key terlalu panjang")
+)
+
+// hashCheckoutRequest sidik body checkout (CreateTrxRequest atau CartCheckoutRequest)
+// untuk mendeteksi Idempotency-Key yang dipakai ulang dengan body berbeda
+func hashCheckoutRequest(req interface{}) (string, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ReplayCheckout mengembalikan hasil checkout yang tersimpan untuk user + key.
+// found=false berarti checkout harus dijalankan (key belum ada atau sudah kedaluwarsa).
+func (t *transactionImpl) ReplayCheckout(ctx context.Context, userID int, idemKey string, requestHash string) (trxID int, herr *helper.ErrorStruct, found bool) {
+	if len(idemKey) > 255 {
+		return 0, &helper.ErrorStruct{Err: ErrIdempotencyKeyTooLong, Code: 400}, true
+	}
+
+	key, err := t.idemRepo.Find(ctx, userID, idemKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil, false
+		}
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}, true
+	}
+
+	now := time.Now()
+	if key.ExpiresAt.Before(now) {
+		if err := t.idemRepo.DeleteExpired(ctx, key.ID, now); err != nil {
+			return 0, &helper.ErrorStruct{Err: err, Code: 500}, true
+		}
+		return 0, nil, false
+	}
+
+	if key.RequestHash != requestHash {
+		return 0, &helper.ErrorStruct{Err: ErrIdempotencyKeyReused, Code: 409}, true
+	}
+
+	if key.IDTrx == nil {
+		return 0, &helper.ErrorStruct{Err: ErrIdempotencyInFlight, Code: 409}, true
+	}
+
+	return *key.IDTrx, nil, true
+}
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"pbi/internal/config"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTransactionNotFound     = errors.New("transaksi tidak ditemukan")
+	ErrInvalidStatusTransition = errors.New("perubahan status transaksi tidak diizinkan")
+	ErrCancelAfterShipped      = errors.New("transaksi yang sudah dikirim tidak dapat dibatalkan")
+	ErrCancelCatatanRequired   = errors.New("catatan wajib diisi untuk membatalkan transaksi")
+)
+
+// trxTransitions memetakan status asal -> status tujuan -> peran yang boleh melakukannya.
+// Admin boleh melakukan semua transisi yang terdaftar di sini. Penjual memproses pesanan
+// lewat paket tokonya; processing/shipped/delivered/cancelled di level trx diturunkan oleh system.
+var trxTransitions = map[string]map[string][]string{
+	entity.TrxStatusPendingPayment: {
+		entity.TrxStatusPaid:      {entity.TrxActorSystem},
+		entity.TrxStatusCancelled: {entity.TrxActorBuyer},
+		entity.TrxStatusExpired:   {entity.TrxActorSystem},
+	},
+	entity.TrxStatusPaid: {
+		entity.TrxStatusProcessing: {entity.TrxActorSystem},
+		entity.TrxStatusCancelled:  {entity.TrxActorBuyer, entity.TrxActorSystem},
+	},
+	entity.TrxStatusProcessing: {
+		entity.TrxStatusShipped:   {entity.TrxActorSystem},
+		entity.TrxStatusCancelled: {entity.TrxActorSystem},
+	},
+	entity.TrxStatusShipped: {
+		entity.TrxStatusDelivered: {entity.TrxActorSystem},
+	},
+	entity.TrxStatusDelivered: {
+		entity.TrxStatusCompleted: {entity.TrxActorBuyer, entity.TrxActorSystem},
+	},
+}
+
+func canTransition(from string, to string, actor string) bool {
+	actors, ok := trxTransitions[from][to]
+	if !ok {
+		return false
+	}
+
+	if actor == entity.TrxActorAdmin {
+		return true
+	}
+
+	for _, a := range actors {
+		if a == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveActor menentukan peran user terhadap transaksi
+func (t *transactionImpl) resolveActor(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, userID int, isAdmin bool) (string, error) {
+	if isAdmin {
+		return entity.TrxActorAdmin, nil
+	}
+
+	if trx.IDUser == userID {
+		return entity.TrxActorBuyer, nil
+	}
+
+	isSeller, err := t.repo.IsSellerOfTransaction(ctx, tx, trx.ID, userID)
+	if err != nil {
+		return "", err
+	}
+	if isSeller {
+		return entity.TrxActorSeller, nil
+	}
+
+	return "", ErrTransactionNotFound
+}
+
+// TrxStatusDeps mengelompokkan repository yang dibutuhkan mesin status transaksi
+type TrxStatusDeps struct {
+	TrxRepo       repository.TransactionRepository
+	PaketRepo     repository.PaketRepository
+	ReservasiRepo repository.ReservasiRepository
+	AnalyticsRepo repository.AnalyticsRepository
+	KomisiRepo    repository.KomisiRepository
+	SaldoRepo     repository.SaldoRepository
+	SaldoCfg      config.SaldoConfig
+	OutboxRepo    repository.OutboxRepository
+	VoucherRepo   repository.VoucherRepository
+	ReturRepo     repository.ReturRepository
+	PaymentRepo   repository.PaymentRepository
+}
+
+// TrxStatusMachine dibuat sekali di container lalu dibagikan ke usecase yang perlu memindahkan
+// status transaksi, supaya semua pencatatan ledger berjalan dari satu tempat
+type TrxStatusMachine struct {
+	repo      repository.TransactionRepository
+	paketRepo repository.PaketRepository
+	stock     *stockReserver
+	sales     *salesRecorder
+	komisi    *komisiLedger
+	saldo     *saldoLedger
+	voucher   *voucherEngine
+	refund    *refundRecorder
+	events    *eventOutbox
+}
+
+func NewTrxStatusMachine(deps TrxStatusDeps) *TrxStatusMachine {
+	events := newEventOutbox(deps.OutboxRepo)
+	return &TrxStatusMachine{
+		repo:      deps.TrxRepo,
+		paketRepo: deps.PaketRepo,
+		stock:     newStockReserver(deps.ReservasiRepo, events),
+		sales:     newSalesRecorder(deps.AnalyticsRepo),
+		komisi:    newKomisiLedger(deps.KomisiRepo),
+		saldo:     newSaldoLedger(deps.SaldoRepo, deps.SaldoCfg),
+		voucher:   newVoucherEngine(deps.VoucherRepo),
+		refund:    newRefundRecorder(deps.ReturRepo, deps.PaketRepo, deps.PaymentRepo),
+		events:    events,
+	}
+}
+
+// change memindahkan status transaksi, mencatat riwayatnya, lalu menyamakan status paket toko.
+// Keluar dari pending_payment, reservasi stok dipotong (paid) atau dilepas (cancelled/expired).
+// Rekap penjualan toko dan komisi reseller bertambah saat paid dan dibalik saat pesanan yang sudah
+// dibayar dibatalkan. Komisi baru bisa ditarik dan saldo toko baru dikreditkan setelah completed.
+// Harus dipanggil di dalam db transaction dengan baris trx sudah di-lock.
+func (m *TrxStatusMachine) change(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, to string, actor string, userID int, catatan string) error {
+	from := trx.Status
+	if err := m.move(ctx, tx, trx, to, actor, userID, catatan); err != nil {
+		return err
+	}
+
+	if from == entity.TrxStatusPendingPayment {
+		settle := m.stock.release
+		if to == entity.TrxStatusPaid {
+			settle = m.stock.commit
+		}
+		if err := settle(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case to == entity.TrxStatusPaid:
+		if err := m.sales.paid(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+		if err := m.komisi.paid(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+	case to == entity.TrxStatusCancelled && from != entity.TrxStatusPendingPayment:
+		if err := m.sales.cancelled(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+		if err := m.komisi.cancelled(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+	case to == entity.TrxStatusCompleted:
+		if err := m.komisi.completed(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+		if err := m.saldo.completed(ctx, tx, trx.ID); err != nil {
+			return err
+		}
+	}
+
+	return m.paketRepo.SyncStatus(ctx, tx, trx.ID, to)
+}
+
+// move sama seperti change tanpa menyentuh paket, dipakai saat status trx diturunkan dari paket.
+// Setiap perpindahan status ditulis ke outbox sebagai TransactionStatusChanged. Trx yang cancelled
+// atau expired mengembalikan kuota voucher dan jatah per user, termasuk saat semua paket ditolak.
+func (m *TrxStatusMachine) move(ctx context.Context, tx *gorm.DB, trx *entity.Transaction, to string, actor string, userID int, catatan string) error {
+	if !canTransition(trx.Status, to, actor) {
+		return ErrInvalidStatusTransition
+	}
+
+	if err := m.repo.UpdateStatus(ctx, tx, trx.ID, trx.Status, to); err != nil {
+		return err
+	}
+
+	history := &entity.TrxStatusHistory{
+		IDTrx:      trx.ID,
+		DariStatus: trx.Status,
+		KeStatus:   to,
+		DiubahOleh: userID,
+		Peran:      actor,
+		Catatan:    catatan,
+	}
+
+	if err := m.repo.CreateStatusHistory(ctx, tx, history); err != nil {
+		return err
+	}
+
+	if err := m.events.trxStatusChanged(ctx, tx, history); err != nil {
+		return err
+	}
+
+	if to == entity.TrxStatusCancelled || to == entity.TrxStatusExpired {
+		if err := m.voucher.release(ctx, tx, trx); err != nil {
+			return err
+		}
+	}
+
+	trx.Status = to
+	return nil
+}
+
+func (t *transactionImpl) UpdateStatus(ctx context.Context, trxID int, userID int, isAdmin bool, req *models.UpdateTrxStatusRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	// pembatalan selalu lewat Cancel supaya stok ikut dikembalikan, catatan menjadi alasan pembatalan
+	if req.Status == entity.TrxStatusCancelled {
+		if strings.TrimSpace(req.Catatan) == "" {
+			return &helper.ErrorStruct{Err: ErrCancelCatatanRequired, Code: 400}
+		}
+		return t.Cancel(ctx, trxID, userID, isAdmin, &models.CancelTrxRequest{Alasan: req.Catatan})
+	}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		trx, err := t.repo.GetTransactionForUpdate(ctx, tx, trxID)
+		if err != nil {
+			return err
+		}
+
+		actor, err := t.resolveActor(ctx, tx, trx, userID, isAdmin)
+		if err != nil {
+			return err
+		}
+
+		return t.status.change(ctx, tx, trx, req.Status, actor, userID, req.Catatan)
+	})
+
+	if err != nil {
+		return trxError(err)
+	}
+
+	return nil
+}
+
+// Cancel membatalkan transaksi dan mengembalikan stok produk dalam satu db transaction
+func (t *transactionImpl) Cancel(ctx context.Context, trxID int, userID int, isAdmin bool, req *models.CancelTrxRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		trx, err := t.repo.GetTransactionForUpdate(ctx, tx, trxID)
+		if err != nil {
+			return err
+		}
+
+		actor, err := t.resolveActor(ctx, tx, trx, userID, isAdmin)
+		if err != nil {
+			return err
+		}
+
+		pakets, err := t.status.paketRepo.ListByTrxForUpdate(ctx, tx, trx.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := cancellable(trx, pakets); err != nil {
+			return err
+		}
+
+		// stok dikembalikan sebelum paket ikut dibatalkan, paket yang sudah ditolak dilewati
+		if err := t.status.restoreStock(ctx, tx, trx); err != nil {
+			return err
+		}
+
+		// dana pesanan yang sudah dibayar dikembalikan per paket yang masih aktif
+		if trx.Status != entity.TrxStatusPendingPayment {
+			if err := t.status.refund.cancelled(ctx, tx, trx, pakets, "transaksi dibatalkan: "+req.Alasan); err != nil {
+				return err
+			}
+		}
+
+		if err := t.status.change(ctx, tx, trx, entity.TrxStatusCancelled, actor, userID, req.Alasan); err != nil {
+			return err
+		}
+
+		return t.repo.MarkCancelled(ctx, tx, trx.ID, userID, req.Alasan)
+	})
+
+	if err != nil {
+		return trxError(err)
+	}
+
+	return nil
+}
+
+// cancellable menolak pembatalan bila trx atau salah satu paketnya sudah dikirim. Trx bisa masih
+// processing saat sebagian paket sudah dikirim, jadi status setiap paket ikut dicek.
+func cancellable(trx *entity.Transaction, pakets []entity.PaketToko) error {
+	statuses := []string{trx.Status}
+	for _, p := range pakets {
+		statuses = append(statuses, p.Status)
+	}
+
+	for _, s := range statuses {
+		switch s {
+		case entity.TrxStatusShipped, entity.TrxStatusDelivered, entity.TrxStatusCompleted:
+			return ErrCancelAfterShipped
+		}
+	}
+	return nil
+}
+
+// ExpireUnpaid melepas reservasi stok yang melewati batas bayar dan menandai trx expired.
+// Dipanggil berkala oleh worker, mengembalikan jumlah trx yang berhasil diproses.
+// Trx yang gagal tidak menghentikan trx lain, error pertama dikembalikan.
+func (t *transactionImpl) ExpireUnpaid(ctx context.Context, limit int) (int, *helper.ErrorStruct) {
+	ids, err := t.reservasiRepo.ListExpiredTrxIDs(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	var (
+		done     int
+		firstErr error
+	)
+	for _, id := range ids {
+		err := t.db.Transaction(func(tx *gorm.DB) error {
+			trx, err := t.repo.GetTransactionForUpdate(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+
+			// sudah dibayar/dibatalkan di antara pencarian dan lock: cukup lepas sisa reservasinya
+			if trx.Status != entity.TrxStatusPendingPayment {
+				return t.status.stock.release(ctx, tx, trx.ID)
+			}
+
+			return t.status.change(ctx, tx, trx, entity.TrxStatusExpired, entity.TrxActorSystem, 0, "batas pembayaran terlewati")
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		done++
+	}
+
+	if firstErr != nil {
+		return done, trxError(firstErr)
+	}
+	return done, nil
+}
+
+// restoreStock mengembalikan kuantitas setiap detail_trx ke produk.stok.
+// Trx yang belum dibayar belum memotong stok, reservasinya dilepas oleh change.
+func (m *TrxStatusMachine) restoreStock(ctx context.Context, tx *gorm.DB, trx *entity.Transaction) error {
+	if trx.Status == entity.TrxStatusPendingPayment {
+		return nil
+	}
+
+	items, err := m.repo.GetStockItemsByTrx(ctx, tx, trx.ID)
+	if err != nil {
+		return err
+	}
+
+	return m.restoreItems(ctx, tx, trx.ID, items)
+}
+
+func (m *TrxStatusMachine) restoreItems(ctx context.Context, tx *gorm.DB, trxID int, items []entity.TrxStockItem) error {
+	for _, item := range items {
+		if err := m.repo.RestoreStokProduk(ctx, tx, item.IDProduk, item.Kuantitas); err != nil {
+			return err
+		}
+		if err := m.events.stockChanged(ctx, tx, item.IDProduk, entity.StokSebabDikembalikan, trxID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *transactionImpl) GetStatusHistory(ctx context.Context, trxID int, userID int, isAdmin bool) ([]models.TrxStatusHistoryResponse, *helper.ErrorStruct) {
+	trx, err := t.repo.FindTransactionByID(ctx, trxID)
+	if err != nil {
+		return nil, trxError(err)
+	}
+
+	if _, err := t.resolveActor(ctx, t.db, trx, userID, isAdmin); err != nil {
+		return nil, trxError(err)
+	}
+
+	histories, err := t.repo.GetStatusHistory(ctx, trx.ID)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.TrxStatusHistoryResponse, 0, len(histories))
+	for _, h := range histories {
+		res = append(res, models.TrxStatusHistoryResponse{
+			DariStatus: h.DariStatus,
+			KeStatus:   h.KeStatus,
+			DiubahOleh: h.DiubahOleh,
+			Peran:      h.Peran,
+			Catatan:    h.Catatan,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// trxError memetakan error domain transaksi ke kode HTTP
+func trxError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrTransactionNotFound):
+		return &helper.ErrorStruct{Err: ErrTransactionNotFound, Code: 404}
+	case errors.Is(err, ErrInvalidStatusTransition), errors.Is(err, ErrCancelAfterShipped):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	}
+	return &helper.ErrorStruct{Err: err, Code: 500}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestCanTransitionDelivered(t *testing.T) {
+	tests := []struct {
+		actor string
+		want  bool
+	}{
+		{entity.TrxActorSeller, false},
+		{entity.TrxActorBuyer, false},
+		{entity.TrxActorSystem, true},
+		{entity.TrxActorAdmin, true},
+	}
+
+	for _, tt := range tests {
+		if got := canTransition(entity.TrxStatusShipped, entity.TrxStatusDelivered, tt.actor); got != tt.want {
+			t.Errorf("shipped -> delivered oleh %s = %v, want %v", tt.actor, got, tt.want)
+		}
+	}
+
+	if !canPaketTransition(entity.TrxStatusShipped, entity.TrxStatusDelivered) {
+		t.Error("paket shipped -> delivered ditolak")
+	}
+	if canPaketTransition(entity.TrxStatusProcessing, entity.TrxStatusDelivered) {
+		t.Error("paket processing -> delivered diizinkan")
+	}
+}
+
+func TestCancellable(t *testing.T) {
+	paket := func(statuses ...string) []entity.PaketToko {
+		res := make([]entity.PaketToko, 0, len(statuses))
+		for i, s := range statuses {
+			res = append(res, entity.PaketToko{ID: i + 1, Status: s})
+		}
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		pakets []entity.PaketToko
+		want   error
+	}{
+		{"belum dibayar", entity.TrxStatusPendingPayment, paket(entity.TrxStatusPendingPayment), nil},
+		{"dibayar", entity.TrxStatusPaid, paket(entity.TrxStatusPaid, entity.TrxStatusPaid), nil},
+		{"diproses dengan paket ditolak", entity.TrxStatusProcessing, paket(entity.TrxStatusProcessing, entity.TrxStatusCancelled), nil},
+		{"diproses dengan paket dikirim", entity.TrxStatusProcessing, paket(entity.TrxStatusProcessing, entity.TrxStatusShipped), ErrCancelAfterShipped},
+		{"diproses dengan paket sampai", entity.TrxStatusProcessing, paket(entity.TrxStatusDelivered, entity.TrxStatusProcessing), ErrCancelAfterShipped},
+		{"trx dikirim", entity.TrxStatusShipped, paket(entity.TrxStatusShipped), ErrCancelAfterShipped},
+		{"trx selesai", entity.TrxStatusCompleted, nil, ErrCancelAfterShipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cancellable(&entity.Transaction{ID: 1, Status: tt.status}, tt.pakets)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("cancellable = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				if code := trxError(err).Code; code != 409 {
+					t.Errorf("code = %d, want 409", code)
+				}
+			}
+		})
+	}
+}
+
+// fakeStatusRepo mencatat perpindahan status trx
+type fakeStatusRepo struct {
+	repository.TransactionRepository
+	history []string
+	peran   []string
+}
+
+func (f *fakeStatusRepo) UpdateStatus(ctx context.Context, tx *gorm.DB, trxID int, from string, to string) error {
+	return nil
+}
+
+func (f *fakeStatusRepo) CreateStatusHistory(ctx context.Context, tx *gorm.DB, history *entity.TrxStatusHistory) error {
+	f.history = append(f.history, history.KeStatus)
+	f.peran = append(f.peran, history.Peran)
+	return nil
+}
+
+func TestDeriveDelivered(t *testing.T) {
+	tests := []struct {
+		name       string
+		pakets     []string
+		wantStatus string
+		wantMoves  []string
+	}{
+		{"sebagian paket sampai", []string{entity.TrxStatusDelivered, entity.TrxStatusShipped}, entity.TrxStatusShipped, nil},
+		{"semua paket sampai", []string{entity.TrxStatusDelivered, entity.TrxStatusDelivered}, entity.TrxStatusDelivered, []string{entity.TrxStatusDelivered}},
+		{"paket ditolak dilewati", []string{entity.TrxStatusDelivered, entity.TrxStatusCancelled}, entity.TrxStatusDelivered, []string{entity.TrxStatusDelivered}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStatusRepo{}
+			m := &TrxStatusMachine{repo: repo, events: newEventOutbox(&fakeOutboxRepo{})}
+
+			pakets := make([]entity.PaketToko, 0, len(tt.pakets))
+			for i, s := range tt.pakets {
+				pakets = append(pakets, entity.PaketToko{ID: i + 1, IDTrx: 1, Status: s})
+			}
+			trx := &entity.Transaction{ID: 1, Status: entity.TrxStatusShipped}
+
+			if err := m.derive(context.Background(), nil, trx, pakets, 7, "uji"); err != nil {
+				t.Fatal(err)
+			}
+
+			if trx.Status != tt.wantStatus {
+				t.Errorf("status trx = %s, want %s", trx.Status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(repo.history, tt.wantMoves) {
+				t.Errorf("riwayat = %v, want %v", repo.history, tt.wantMoves)
+			}
+			for _, p := range repo.peran {
+				if p != entity.TrxActorSystem {
+					t.Errorf("peran = %s, want %s", p, entity.TrxActorSystem)
+				}
+			}
+		})
+	}
+}
@@ -3,192 +3,410 @@ package usecase
 import (
 	"context"
 	"errors"
+	"pbi/internal/config"
 	"pbi/internal/helper"
 	"pbi/internal/pkg/entity"
 	"pbi/internal/pkg/models"
 	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+	"sort"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type TransactionUsecase interface {
-	CreateTransaction(ctx context.Context, userID int, req *models.CreateTrxRequest) (int, *helper.ErrorStruct)
-    GetAll(ctx context.Context, userID int) (*models.TransactionListResponseWrapper, *helper.ErrorStruct)
+	CreateTransaction(ctx context.Context, userID int, idemKey string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct)
+	CreateTransactionWithHash(ctx context.Context, userID int, idemKey string, requestHash string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct)
+	ReplayCheckout(ctx context.Context, userID int, idemKey string, requestHash string) (int, *helper.ErrorStruct, bool)
+    GetAll(ctx context.Context, filter *entity.TrxFilter) (*models.TransactionListResponseWrapper, *helper.ErrorStruct)
 	GetByID(ctx context.Context, trxID int, userID int) (*models.TransactionDetailByIDResponse, *helper.ErrorStruct) // Tambahkan ini
+	UpdateStatus(ctx context.Context, trxID int, userID int, isAdmin bool, req *models.UpdateTrxStatusRequest) *helper.ErrorStruct
+	GetStatusHistory(ctx context.Context, trxID int, userID int, isAdmin bool) ([]models.TrxStatusHistoryResponse, *helper.ErrorStruct)
+	Cancel(ctx context.Context, trxID int, userID int, isAdmin bool, req *models.CancelTrxRequest) *helper.ErrorStruct
+	GetInvoice(ctx context.Context, trxID int, userID int, isAdmin bool, format string) (*models.InvoiceFile, *helper.ErrorStruct)
+	ExpireUnpaid(ctx context.Context, limit int) (int, *helper.ErrorStruct)
+	Export(ctx context.Context, filter *entity.TrxFilter, format string) (*models.ExportStream, *helper.ErrorStruct)
 }
 
 type transactionImpl struct {
 	db        	*gorm.DB
 	repo 		repository.TransactionRepository
 	destRepo repository.DestinationRepository
+	idemRepo repository.IdempotencyRepository
+	idemTTL  time.Duration
+	paketRepo repository.PaketRepository
+	status   *TrxStatusMachine
+	voucher  *voucherEngine
+	shipping *shippingCalculator
+	invoice  *invoiceNumberer
+	reservasiRepo repository.ReservasiRepository
+	holdTTL  time.Duration
 }
 
-func NewTransactionUsecase(db *gorm.DB,trxRepo repository.TransactionRepository,destRepo repository.DestinationRepository,) TransactionUsecase {
+func NewTransactionUsecase(db *gorm.DB,trxRepo repository.TransactionRepository,destRepo repository.DestinationRepository,idemRepo repository.IdempotencyRepository,idemTTL time.Duration,voucherRepo repository.VoucherRepository,paketRepo repository.PaketRepository,shipping ShippingRateProvider,invoiceRepo repository.InvoiceRepository,invoiceCfg config.InvoiceConfig,reservasiRepo repository.ReservasiRepository,holdTTL time.Duration,status *TrxStatusMachine,) TransactionUsecase {
 	return &transactionImpl{
 		db:       db,
 		repo:  trxRepo,
 		destRepo: destRepo,
+		idemRepo: idemRepo,
+		idemTTL:  idemTTL,
+		status:   status,
+		paketRepo: paketRepo,
+		voucher:  newVoucherEngine(voucherRepo),
+		shipping: newShippingCalculator(shipping),
+		invoice:  newInvoiceNumberer(invoiceRepo, invoiceCfg),
+		reservasiRepo: reservasiRepo,
+		holdTTL:  holdTTL,
 	}
 }
 
-func (t *transactionImpl) CreateTransaction(ctx context.Context, userID int, req *models.CreateTrxRequest) (int, *helper.ErrorStruct) {
+// CreateTransaction membuat transaksi. Jika idemKey diisi, hasil pertama disimpan
+// per user + key dan dikembalikan lagi untuk retry dengan body yang sama.
+func (t *transactionImpl) CreateTransaction(ctx context.Context, userID int, idemKey string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct) {
 	if err := helper.Validate.Struct(req); err != nil {
 		return 0, &helper.ErrorStruct{Err: err, Code: 400}
 	}
 
-	if _, err := t.destRepo.FindByID(ctx, req.AlamatKirim, userID); err != nil {
-		return 0, &helper.ErrorStruct{
-			Err:  errors.New("alamat pengiriman tidak valid"),
-			Code: 404,
+	var requestHash string
+	if idemKey != "" {
+		hash, err := hashCheckoutRequest(req)
+		if err != nil {
+			return 0, &helper.ErrorStruct{Err: err, Code: 500}
+		}
+		requestHash = hash
+
+		if trxID, herr, found := t.ReplayCheckout(ctx, userID, idemKey, requestHash); found {
+			return trxID, herr
 		}
 	}
 
-	var trxID int
+	return t.createTransaction(ctx, userID, idemKey, requestHash, req)
+}
 
-	err := t.db.Transaction(func(tx *gorm.DB) error {
+// CreateTransactionWithHash membuat transaksi dengan hash Idempotency-Key yang dihitung pemanggil.
+// Dipakai checkout keranjang yang mencocokkan key dengan body request keranjang, replay sudah
+// dicek pemanggil lewat ReplayCheckout sebelum baris keranjang dibaca.
+func (t *transactionImpl) CreateTransactionWithHash(ctx context.Context, userID int, idemKey string, requestHash string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	return t.createTransaction(ctx, userID, idemKey, requestHash, req)
+}
 
-		trx := &entity.Transaction{
-			IDUser:           userID,
-			AlamatPengiriman: req.AlamatKirim,
-			MetodeBayar:      req.MethodBayar,
-			KodeInvoice:      helper.GenerateInvoice(),
+func (t *transactionImpl) createTransaction(ctx context.Context, userID int, idemKey string, requestHash string, req *models.CreateTrxRequest) (int, *helper.ErrorStruct) {
+	// pesanan dropship dikirim ke kota pelanggan akhir, alamatnya dibuat di dalam checkout
+	var idKota string
+	if req.Dropship != nil {
+		idKota = req.Dropship.IDKota
+	} else {
+		dest, err := t.destRepo.FindByID(ctx, req.AlamatKirim, userID)
+		if err != nil {
+			return 0, &helper.ErrorStruct{
+				Err:  errors.New("alamat pengiriman tidak valid"),
+				Code: 404,
+			}
 		}
+		idKota = dest.IDKota
+	}
+
+	var trxID int
+	items := mergeCheckoutItems(req.DetailTrx)
 
-		if err := t.repo.CreateTransaction(ctx, tx, trx); err != nil {
+	// deadlock yang tersisa (mis. dengan voucher/nomor invoice) diulang dari awal dengan tx baru
+	err := helper.RetryOnDeadlock(ctx, maxCheckoutAttempts, func() error {
+		return t.db.Transaction(func(tx *gorm.DB) error {
+			id, err := t.checkout(ctx, tx, userID, idKota, idemKey, requestHash, req, items)
+			trxID = id
 			return err
+		})
+	})
+
+	if err != nil {
+		if idemKey != "" && helper.IsDuplicateKeyError(err) {
+			if replayID, herr, found := t.ReplayCheckout(ctx, userID, idemKey, requestHash); found {
+				return replayID, herr
+			}
 		}
+		return 0, checkoutError(err)
+	}
 
-		totalHarga := float64(0)
+	return trxID, nil
+}
 
-		for _, item := range req.DetailTrx {
+// checkout isi db transaction CreateTransaction, items sudah digabung per produk
+func (t *transactionImpl) checkout(ctx context.Context, tx *gorm.DB, userID int, idKota string, idemKey string, requestHash string, req *models.CreateTrxRequest, items []models.CreateTrxItemRequest) (int, error) {
+
+	// insert key lebih dulu: request paralel dengan key sama akan menunggu di unique index
+	var idem *entity.IdempotencyKey
+	if idemKey != "" {
+		idem = &entity.IdempotencyKey{
+			IDUser:      userID,
+			IdemKey:     idemKey,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(t.idemTTL),
+		}
+		if err := t.idemRepo.Create(ctx, tx, idem); err != nil {
+			return 0, err
+		}
+	}
 
-			produk, err := t.repo.GetProdukByID(ctx, tx, item.ProductID)
-			if err != nil {
-				return err
-			}
+	trx := &entity.Transaction{
+		IDUser:           userID,
+		AlamatPengiriman: req.AlamatKirim,
+		MetodeBayar:      req.MethodBayar,
+		Status:           entity.TrxStatusPendingPayment,
+	}
 
-			if produk.IDUser == userID {
-				return errors.New("tidak boleh membeli produk milik sendiri")
-			}
+	if ds := req.Dropship; ds != nil {
+		dest := &entity.Destination{
+			UserID:       userID,
+			JudulAlamat:  "Dropship",
+			NamaPenerima: ds.NamaPenerima,
+			NoTelp:       ds.NoTelp,
+			DetailAlamat: ds.DetailAlamat,
+			IDKota:       ds.IDKota,
+		}
+		if err := t.destRepo.CreateDropship(ctx, tx, dest); err != nil {
+			return 0, err
+		}
 
-			if produk.Stok < item.Kuantitas {
-				return errors.New("stok produk tidak mencukupi")
-			}
+		trx.AlamatPengiriman = dest.ID
+		trx.Dropship = true
+		trx.NamaPengirim = ds.NamaPengirim
+		trx.TelpPengirim = ds.TelpPengirim
+		trx.TampilkanHargaReseller = ds.TampilkanHargaReseller
+	}
 
-			if err := t.repo.UpdateStokProduk(ctx, tx, produk.ID, item.Kuantitas); err != nil {
-				return err
-			}
+	if err := t.repo.CreateTransaction(ctx, tx, trx); err != nil {
+		return 0, err
+	}
 
-			logProduk := &entity.LogProduk{
-				IDProduk:      produk.ID,
-				IDToko:        produk.IDToko,
-				IDCategory:    produk.IDCategory,
-				NamaProduk:    produk.NamaProduk,
-				Slug:          produk.Slug,
-				HargaReseller: produk.HargaReseller,
-				HargaKonsumen: produk.HargaKonsumen,
-				Deskripsi:     produk.Deskripsi,
-			}
+	if err := t.repo.CreateStatusHistory(ctx, tx, &entity.TrxStatusHistory{
+		IDTrx:      trx.ID,
+		KeStatus:   entity.TrxStatusPendingPayment,
+		DiubahOleh: userID,
+		Peran:      entity.TrxActorBuyer,
+	}); err != nil {
+		return 0, err
+	}
 
-			if err := t.repo.CreateLogProduk(ctx, tx, logProduk); err != nil {
-				return err
-			}
+	produkIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		produkIDs = append(produkIDs, item.ProductID)
+	}
+	sort.Ints(produkIDs)
 
-			subTotal := produk.HargaKonsumen * float64(item.Kuantitas)
-			totalHarga += subTotal
+	produks, err := t.repo.GetProdukForUpdate(ctx, tx, produkIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(produks) != len(produkIDs) {
+		return 0, ErrProductNotFound
+	}
 
-			detail := &entity.DetailTransaction{
-				IDTrx: 			trx.ID,
-				IDLogProduk:   	logProduk.ID,
-				IDToko:        	produk.IDToko,
-				Kuantitas:     	item.Kuantitas,
-				HargaTotal:   	subTotal,
-			}
+	produkByID := make(map[int]*entity.ProdukWithOwner, len(produks))
+	for i := range produks {
+		produkByID[produks[i].ID] = &produks[i]
+	}
 
-			if err := t.repo.CreateDetailTransaction(ctx, tx, detail); err != nil {
-				return err
-			}
+	lines := make([]*checkoutLine, 0, len(items))
+	expiresAt := time.Now().Add(t.holdTTL)
+
+	for _, item := range items {
+
+		produk := produkByID[item.ProductID]
+
+		if produk.IDUser == userID {
+			return 0, ErrOwnProduct
 		}
 
-		if err := t.repo.UpdateTotalHarga(ctx, tx, trx.ID, totalHarga); err != nil {
-			return err
+		// stok hanya ditahan, dipotong permanen saat pesanan dibayar
+		if err := t.status.stock.hold(ctx, tx, trx.ID, produk, item.Kuantitas, expiresAt); err != nil {
+			return 0, err
 		}
 
-		trxID = trx.ID
-		return nil
-	})
+		logProduk := &entity.LogProduk{
+			IDProduk:      produk.ID,
+			IDToko:        produk.IDToko,
+			IDCategory:    produk.IDCategory,
+			NamaProduk:    produk.NamaProduk,
+			Slug:          produk.Slug,
+			HargaReseller: produk.HargaReseller,
+			HargaKonsumen: produk.HargaKonsumen,
+			Deskripsi:     produk.Deskripsi,
+		}
+
+		if err := t.repo.CreateLogProduk(ctx, tx, logProduk); err != nil {
+			return 0, err
+		}
+
+		lines = append(lines, &checkoutLine{
+			Produk:    produk,
+			LogProduk: logProduk,
+			Kuantitas: item.Kuantitas,
+			Subtotal:  produk.HargaKonsumen.Times(item.Kuantitas),
+		})
+	}
+
+	// voucher dicek setelah semua produk terkunci, kuota dikunci sampai commit
+	var (
+		voucher *entity.Voucher
+		diskon  money.Rupiah
+	)
+	if req.KodeVoucher != "" {
+		v, d, err := t.voucher.apply(ctx, tx, userID, req.KodeVoucher, lines)
+		if err != nil {
+			return 0, err
+		}
+		voucher, diskon = v, d
+	}
+
+	var totalHarga money.Rupiah
+	created := &entity.TransactionCreatedPayload{
+		IDTrx:       trx.ID,
+		IDUser:      userID,
+		MetodeBayar: trx.MetodeBayar,
+		Dropship:    trx.Dropship,
+	}
 
+	for _, line := range lines {
+		totalHarga += line.Total()
+		created.Items = append(created.Items, entity.TransactionCreatedItem{
+			IDProduk:   line.Produk.ID,
+			IDToko:     line.Produk.IDToko,
+			Kuantitas:  line.Kuantitas,
+			HargaTotal: line.Total(),
+		})
+
+		detail := &entity.DetailTransaction{
+			IDTrx: 			trx.ID,
+			IDLogProduk:   	line.LogProduk.ID,
+			IDToko:        	line.Produk.IDToko,
+			Kuantitas:     	line.Kuantitas,
+			HargaTotal:   	line.Total(),
+			Diskon:        	line.Diskon,
+		}
+
+		if err := t.repo.CreateDetailTransaction(ctx, tx, detail); err != nil {
+			return 0, err
+		}
+	}
+
+	// ongkir dihitung ulang di server per toko, harga_total = produk setelah diskon + ongkir
+	pakets, ongkir, err := t.shipping.packages(ctx, trx.ID, idKota, lines, req.Pengiriman)
 	if err != nil {
-		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+		return 0, err
 	}
 
-	return trxID, nil
+	// nomor invoice diambil setelah produk & voucher terkunci supaya lock urutan dipegang sesingkat mungkin
+	if err := t.invoice.assign(ctx, tx, trx, pakets, lines); err != nil {
+		return 0, err
+	}
+
+	if err := t.repo.SetKodeInvoice(ctx, tx, trx.ID, trx.KodeInvoice); err != nil {
+		return 0, err
+	}
+
+	for _, paket := range pakets {
+		if err := t.paketRepo.Create(ctx, tx, paket); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := t.repo.SetOngkosKirim(ctx, tx, trx.ID, ongkir); err != nil {
+		return 0, err
+	}
+
+	if err := t.repo.UpdateTotalHarga(ctx, tx, trx.ID, totalHarga+ongkir); err != nil {
+		return 0, err
+	}
+
+	if voucher != nil {
+		if err := t.repo.SetVoucher(ctx, tx, trx.ID, voucher.ID, diskon); err != nil {
+			return 0, err
+		}
+		if err := t.voucher.recordUsage(ctx, tx, voucher, userID, trx.ID, diskon); err != nil {
+			return 0, err
+		}
+	}
+
+	if idem != nil {
+		if err := t.idemRepo.SetTrx(ctx, tx, idem.ID, trx.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	created.KodeInvoice = trx.KodeInvoice
+	created.HargaTotal = totalHarga + ongkir
+	if err := t.status.events.trxCreated(ctx, tx, created); err != nil {
+		return 0, err
+	}
+
+	// COD tidak menunggu pembayaran online, pesanan langsung dikonfirmasi supaya bisa diproses toko
+	if trx.MetodeBayar == entity.MetodeBayarCOD {
+		if err := t.status.change(ctx, tx, trx, entity.TrxStatusPaid, entity.TrxActorSystem, userID, "pesanan cod dikonfirmasi"); err != nil {
+			return 0, err
+		}
+	}
+
+	return trx.ID, nil
 }
 
-func (u *transactionImpl) GetAll(ctx context.Context, userID int) (*models.TransactionListResponseWrapper, *helper.ErrorStruct) {
+// maxTrxPageLimit batas atas limit per halaman riwayat transaksi
+const maxTrxPageLimit = 100
+
+var (
+	ErrTrxSort  = errors.New("sort harus tanggal atau harga")
+	ErrTrxOrder = errors.New("order harus asc atau desc")
+)
+
+func (u *transactionImpl) GetAll(ctx context.Context, filter *entity.TrxFilter) (*models.TransactionListResponseWrapper, *helper.ErrorStruct) {
+    if filter.Sort != "" && filter.Sort != "tanggal" && filter.Sort != "harga" {
+        return nil, &helper.ErrorStruct{Err: ErrTrxSort, Code: 400}
+    }
+    if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+        return nil, &helper.ErrorStruct{Err: ErrTrxOrder, Code: 400}
+    }
+
+    if filter.Limit <= 0 {
+        filter.Limit = 10
+    }
+    if filter.Limit > maxTrxPageLimit {
+        filter.Limit = maxTrxPageLimit
+    }
+    if filter.Page <= 0 {
+        filter.Page = 1
+    }
 
-    trxs, err := u.repo.GetTransactionsByUser(ctx, userID)
+    trxs, total, err := u.repo.ListTransactions(ctx, filter)
     if err != nil {
         return nil, &helper.ErrorStruct{Err: err, Code: 500}
     }
 
-    var res []models.TransactionListResponse
-
+    trxIDs := make([]int, 0, len(trxs))
     for _, trx := range trxs {
-        details, err := u.repo.GetTransactionDetails(ctx, trx.ID)
-        if err != nil {
-            return nil, &helper.ErrorStruct{Err: err, Code: 500}
-        }
-
-        var detailRes []models.TransactionDetailResponse
-        for _, d := range details {
-            photos, _ := u.repo.GetProductPhotosByLogProdukID(ctx, d.IDLogProduk)
-            
-            var photoRes []models.PhotoInfo
-            for _, p := range photos {
-                photoRes = append(photoRes, models.PhotoInfo{
-                    ID:        p.ID,
-                    ProductID: p.ProductID,
-                    URL:       p.Url,
-                })
-            }
+        trxIDs = append(trxIDs, trx.ID)
+    }
 
-            item := models.TransactionDetailResponse{
-                Product: models.ProductDetailInTransaction{
-                    ID:            d.IDLogProduk,
-                    NamaProduk:    d.NamaProduk,
-                    Slug:          d.Slug,
-                    HargaReseller: d.HargaReseller,
-                    HargaKonsumen: d.HargaKonsumen,
-                    Deskripsi:     d.Deskripsi,
-                    Toko: models.TokoBasicInfo{
-                        NamaToko: d.NamaToko, 
-                        URLFoto:  d.URLFoto,  
-                    },
-                    Category: models.CategoryInfo{
-                        ID:           d.IDCategory,
-                        NamaCategory: d.NamaCategory,
-                    },
-                    Photos: photoRes,
-                },
-                Toko: models.TokoInfo{
-                    ID:       d.IDToko,
-                    NamaToko: d.NamaToko,
-                    URLFoto:  d.URLFoto,
-                },
-                Kuantitas:  d.Kuantitas,
-                HargaTotal: d.HargaTotal,
-            }
+    detailsByTrx, err := u.loadTrxDetails(ctx, trxIDs)
+    if err != nil {
+        return nil, &helper.ErrorStruct{Err: err, Code: 500}
+    }
 
-            detailRes = append(detailRes, item)
-        }
+    res := make([]models.TransactionListResponse, 0, len(trxs))
 
+    for _, trx := range trxs {
         trxResp := models.TransactionListResponse{
             ID:          trx.ID,
             HargaTotal:  trx.HargaTotal,
+            Diskon:      trx.Diskon,
+            OngkosKirim: trx.OngkosKirim,
             KodeInvoice: trx.KodeInvoice,
             MethodBayar: trx.MetodeBayar,
-            DetailTrx:   detailRes,
+            Status:      trx.Status,
+            DetailTrx:   detailsByTrx[trx.ID],
         }
 
         if trx.AlamatKirim != nil {
@@ -200,17 +418,81 @@ func (u *transactionImpl) GetAll(ctx context.Context, userID int) (*models.Trans
                 DetailAlamat: trx.AlamatKirim.DetailAlamat,
             }
         }
+        trxResp.Dropship = dropshipResponse(&trx)
 
         res = append(res, trxResp)
     }
 
     return &models.TransactionListResponseWrapper{
-        Data:  res,
-        Page:  0,
-        Limit: 0,
+        Data:       res,
+        Total:      total,
+        Page:       filter.Page,
+        Limit:      filter.Limit,
+        TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
     }, nil
 }
 
+// loadTrxDetails memuat detail dan foto produk untuk sekumpulan trx dengan dua query,
+// berapa pun jumlah trx dan baris detailnya, lalu mengelompokkannya per id trx
+func (u *transactionImpl) loadTrxDetails(ctx context.Context, trxIDs []int) (map[int][]models.TransactionDetailResponse, error) {
+	details, err := u.repo.GetTransactionDetailsByTrxIDs(ctx, trxIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	logIDs := make([]int, 0, len(details))
+	for _, d := range details {
+		logIDs = append(logIDs, d.IDLogProduk)
+	}
+
+	photos, err := u.repo.GetProductPhotosByLogProdukIDs(ctx, uniqueInts(logIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	photosByLog := make(map[int][]models.PhotoInfo)
+	for _, p := range photos {
+		photosByLog[p.IDLogProduk] = append(photosByLog[p.IDLogProduk], models.PhotoInfo{
+			ID:        p.ID,
+			ProductID: p.ProductID,
+			URL:       p.Url,
+		})
+	}
+
+	res := make(map[int][]models.TransactionDetailResponse, len(trxIDs))
+	for _, d := range details {
+		res[d.IDTrx] = append(res[d.IDTrx], models.TransactionDetailResponse{
+			Product: models.ProductDetailInTransaction{
+				ID:            d.IDLogProduk,
+				NamaProduk:    d.NamaProduk,
+				Slug:          d.Slug,
+				HargaReseller: d.HargaReseller,
+				HargaKonsumen: d.HargaKonsumen,
+				Deskripsi:     d.Deskripsi,
+				Toko: models.TokoBasicInfo{
+					NamaToko: d.NamaToko,
+					URLFoto:  d.URLFoto,
+				},
+				Category: models.CategoryInfo{
+					ID:           d.IDCategory,
+					NamaCategory: d.NamaCategory,
+				},
+				Photos: photosByLog[d.IDLogProduk],
+			},
+			Toko: models.TokoInfo{
+				ID:       d.IDToko,
+				NamaToko: d.NamaToko,
+				URLFoto:  d.URLFoto,
+			},
+			Kuantitas:  d.Kuantitas,
+			HargaTotal: d.HargaTotal,
+			Diskon:     d.Diskon,
+		})
+	}
+
+	return res, nil
+}
+
 func (u *transactionImpl) GetByID(ctx context.Context, trxID int, userID int) (*models.TransactionDetailByIDResponse, *helper.ErrorStruct) {
     
     // Get transaction
@@ -226,62 +508,42 @@ func (u *transactionImpl) GetByID(ctx context.Context, trxID int, userID int) (*
     }
     
     // Get transaction details
-    details, err := u.repo.GetTransactionDetails(ctx, trx.ID)
+    detailsByTrx, err := u.loadTrxDetails(ctx, []int{trx.ID})
     if err != nil {
         return nil, &helper.ErrorStruct{Err: err, Code: 500}
     }
     
-    var detailRes []models.TransactionDetailResponse
-    for _, d := range details {
-        photos, _ := u.repo.GetProductPhotosByLogProdukID(ctx, d.IDLogProduk)
-        
-        var photoRes []models.PhotoInfo
-        for _, p := range photos {
-            photoRes = append(photoRes, models.PhotoInfo{
-                ID:        p.ID,
-                ProductID: p.ProductID,
-                URL:       p.Url,
-            })
-        }
-        
-        item := models.TransactionDetailResponse{
-            Product: models.ProductDetailInTransaction{
-                ID:            d.IDLogProduk,
-                NamaProduk:    d.NamaProduk,
-                Slug:          d.Slug,
-                HargaReseller: d.HargaReseller,
-                HargaKonsumen: d.HargaKonsumen,
-                Deskripsi:     d.Deskripsi,
-                Toko: models.TokoBasicInfo{
-                    NamaToko: d.NamaToko,
-                    URLFoto:  d.URLFoto,
-                },
-                Category: models.CategoryInfo{
-                    ID:           d.IDCategory,
-                    NamaCategory: d.NamaCategory,
-                },
-                Photos: photoRes,
-            },
-            Toko: models.TokoInfo{
-                ID:       d.IDToko,
-                NamaToko: d.NamaToko,
-                URLFoto:  d.URLFoto,
-            },
-            Kuantitas:  d.Kuantitas,
-            HargaTotal: d.HargaTotal,
-        }
-        
-        detailRes = append(detailRes, item)
-    }
-    
     response := &models.TransactionDetailByIDResponse{
         ID:          trx.ID,
         HargaTotal:  trx.HargaTotal,
+        Diskon:      trx.Diskon,
+        OngkosKirim: trx.OngkosKirim,
         KodeInvoice: trx.KodeInvoice,
         MethodBayar: trx.MetodeBayar,
-        DetailTrx:   detailRes,
+        Status:      trx.Status,
+        DetailTrx:   detailsByTrx[trx.ID],
     }
     
+    pakets, err := u.paketRepo.ListByTrx(ctx, trx.ID)
+    if err != nil {
+        return nil, &helper.ErrorStruct{Err: err, Code: 500}
+    }
+
+    for _, p := range pakets {
+        response.Pengiriman = append(response.Pengiriman, models.PaketTokoResponse{
+            ID:          p.ID,
+            IDToko:      p.IDToko,
+            KodeInvoice: p.KodeInvoice,
+            Kurir:       p.Kurir,
+            Layanan:     p.Layanan,
+            Berat:       p.Berat,
+            Ongkir:      p.Ongkir,
+            TotalRefund: p.TotalRefund,
+            Estimasi:    p.Estimasi,
+            Status:      p.Status,
+        })
+    }
+
     if trx.AlamatKirim != nil {
         response.AlamatKirim = models.DestinationResponse{
             ID:           trx.AlamatKirim.ID,
@@ -291,6 +553,7 @@ func (u *transactionImpl) GetByID(ctx context.Context, trxID int, userID int) (*
             DetailAlamat: trx.AlamatKirim.DetailAlamat,
         }
     }
+    response.Dropship = dropshipResponse(trx)
     
     return response, nil
 }
\ No newline at end of file
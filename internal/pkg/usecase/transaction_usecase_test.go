@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestLoadTrxDetailsQueryCount memastikan detail dan foto satu halaman trx selalu dimuat
+// dengan dua query, tidak bertambah mengikuti jumlah trx maupun baris detailnya.
+func TestLoadTrxDetailsQueryCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		trx         int
+		detailPerTx int
+	}{
+		{"satu trx", 1, 1},
+		{"satu halaman", 10, 3},
+		{"halaman penuh", 50, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sqlDB.Close()
+
+			db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{
+				Logger: logger.Default.LogMode(logger.Silent),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var queries int32
+			err = db.Callback().Query().Before("gorm:query").Register("test:count", func(*gorm.DB) {
+				atomic.AddInt32(&queries, 1)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			trxIDs := make([]int, 0, tt.trx)
+			details := sqlmock.NewRows([]string{"id_trx", "kuantitas", "harga_total", "diskon", "id_log_produk", "nama_produk", "id_toko", "nama_toko"})
+			photos := sqlmock.NewRows([]string{"id", "id_produk", "url", "id_log_produk"})
+			logID := 0
+			for i := 1; i <= tt.trx; i++ {
+				trxIDs = append(trxIDs, i)
+				for j := 0; j < tt.detailPerTx; j++ {
+					logID++
+					details.AddRow(i, 1, 10000, 0, logID, "produk", 1, "toko")
+					photos.AddRow(logID, logID, "foto.jpg", logID)
+				}
+			}
+			mock.ExpectQuery("FROM `detail_trx`").WillReturnRows(details)
+			mock.ExpectQuery("FROM `foto_produk`").WillReturnRows(photos)
+
+			u := &transactionImpl{repo: repository.NewTransactionRepo(db)}
+			res, err := u.loadTrxDetails(context.Background(), trxIDs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := atomic.LoadInt32(&queries); got != 2 {
+				t.Errorf("query = %d, want 2", got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			for _, id := range trxIDs {
+				if len(res[id]) != tt.detailPerTx {
+					t.Fatalf("trx %d: detail = %d, want %d", id, len(res[id]), tt.detailPerTx)
+				}
+				for _, d := range res[id] {
+					if len(d.Product.Photos) != 1 {
+						t.Fatalf("trx %d log %d: foto = %d, want 1", id, d.Product.ID, len(d.Product.Photos))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDropshipRequestValidation(t *testing.T) {
+	dropship := func(edit func(d *models.DropshipRequest)) *models.DropshipRequest {
+		d := &models.DropshipRequest{
+			NamaPenerima: "Budi",
+			NoTelp:       "08111",
+			DetailAlamat: "Jl. Mawar 1",
+			IDKota:       "3171",
+			NamaPengirim: "Toko Reseller",
+			TelpPengirim: "08222",
+		}
+		if edit != nil {
+			edit(d)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name        string
+		alamatKirim int
+		dropship    *models.DropshipRequest
+		wantField   string
+	}{
+		{"alamat tersimpan", 5, nil, ""},
+		{"dropship", 0, dropship(nil), ""},
+		{"dropship tanpa telp pengirim", 0, dropship(func(d *models.DropshipRequest) { d.TelpPengirim = "" }), "TelpPengirim"},
+		{"dropship tanpa nama pengirim", 0, dropship(func(d *models.DropshipRequest) { d.NamaPengirim = "" }), "NamaPengirim"},
+		{"nama pengirim terlalu panjang", 0, dropship(func(d *models.DropshipRequest) { d.NamaPengirim = strings.Repeat("a", 256) }), "NamaPengirim"},
+		{"telp pengirim terlalu panjang", 0, dropship(func(d *models.DropshipRequest) { d.TelpPengirim = strings.Repeat("0", 33) }), "TelpPengirim"},
+		{"kota bukan angka", 0, dropship(func(d *models.DropshipRequest) { d.IDKota = "jakarta" }), "IDKota"},
+		{"alamat dan dropship sekaligus", 5, dropship(nil), "AlamatKirim"},
+		{"tanpa alamat maupun dropship", 0, nil, "AlamatKirim"},
+	}
+
+	shipping := []models.CreateTrxShippingRequest{{IDToko: 1, Kurir: "jne", Layanan: "REG"}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs := map[string]interface{}{
+				"checkout": &models.CreateTrxRequest{
+					MethodBayar: "cod",
+					AlamatKirim: tt.alamatKirim,
+					Dropship:    tt.dropship,
+					DetailTrx:   []models.CreateTrxItemRequest{{ProductID: 1, Kuantitas: 1}},
+					Pengiriman:  shipping,
+				},
+				"checkout keranjang": &models.CartCheckoutRequest{
+					CartIDs:     []int{1},
+					MethodBayar: "cod",
+					AlamatKirim: tt.alamatKirim,
+					Dropship:    tt.dropship,
+					Pengiriman:  shipping,
+				},
+			}
+
+			for kind, req := range reqs {
+				err := helper.Validate.Struct(req)
+				if tt.wantField == "" {
+					if err != nil {
+						t.Errorf("%s: error = %v, want nil", kind, err)
+					}
+					continue
+				}
+
+				var verrs validator.ValidationErrors
+				if !errors.As(err, &verrs) {
+					t.Fatalf("%s: error = %v, want validation error pada %s", kind, err, tt.wantField)
+				}
+				if len(verrs) != 1 || verrs[0].Field() != tt.wantField {
+					t.Errorf("%s: error = %v, want hanya %s", kind, err, tt.wantField)
+				}
+			}
+		})
+	}
+}
+
+func TestDropshipResponse(t *testing.T) {
+	if got := dropshipResponse(&entity.Transaction{NamaPengirim: "x"}); got != nil {
+		t.Errorf("pesanan biasa = %+v, want nil", got)
+	}
+
+	got := dropshipResponse(&entity.Transaction{Dropship: true, NamaPengirim: "Reseller", TelpPengirim: "0812", TampilkanHargaReseller: true})
+	want := &models.DropshipResponse{NamaPengirim: "Reseller", TelpPengirim: "0812", TampilkanHargaReseller: true}
+	if got == nil || *got != *want {
+		t.Errorf("dropship = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUlasanNotFound       = errors.New("ulasan tidak ditemukan")
+	ErrUlasanLineNotFound   = errors.New("produk yang diulas tidak ada di transaksi anda")
+	ErrUlasanNotCompleted   = errors.New("ulasan hanya bisa diberikan untuk pesanan yang sudah selesai")
+	ErrUlasanPaketCancelled = errors.New("produk dari paket yang dibatalkan tidak bisa diulas")
+	ErrUlasanExists         = errors.New("produk ini sudah diulas")
+	ErrUlasanAlreadyReplied = errors.New("ulasan sudah dibalas")
+)
+
+type UlasanUsecase interface {
+	Create(ctx context.Context, userID int, req *models.UlasanCreateRequest) (int, *helper.ErrorStruct)
+	Reply(ctx context.Context, ulasanID int, userID int, req *models.BalasUlasanRequest) *helper.ErrorStruct
+	List(ctx context.Context, filter *entity.UlasanFilter) (*models.UlasanListResponse, *helper.ErrorStruct)
+}
+
+type ulasanImpl struct {
+	db       *gorm.DB
+	repo     repository.UlasanRepository
+	tokoRepo repository.TokoRepository
+}
+
+func NewUlasanUsecase(db *gorm.DB, repo repository.UlasanRepository, tokoRepo repository.TokoRepository) UlasanUsecase {
+	return &ulasanImpl{
+		db:       db,
+		repo:     repo,
+		tokoRepo: tokoRepo,
+	}
+}
+
+// Create ulasan pembeli untuk satu baris detail_trx miliknya yang trx-nya sudah completed.
+// Rating rata-rata produk dan toko dihitung ulang di transaksi yang sama.
+func (u *ulasanImpl) Create(ctx context.Context, userID int, req *models.UlasanCreateRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	line, err := u.repo.FindLine(ctx, req.IDDetailTrx)
+	if err != nil {
+		return 0, ulasanError(ErrUlasanLineNotFound)
+	}
+	if err := ulasanEligible(line, userID); err != nil {
+		return 0, ulasanError(err)
+	}
+
+	var photos []entity.UlasanFoto
+	for _, file := range req.Photos {
+		url, err := uploadFile(file)
+		if err != nil {
+			return 0, &helper.ErrorStruct{Err: err, Code: 400}
+		}
+		photos = append(photos, entity.UlasanFoto{Url: url})
+	}
+
+	ulasan := &entity.Ulasan{
+		IDDetailTrx: line.IDDetailTrx,
+		IDTrx:       line.IDTrx,
+		IDProduk:    line.IDProduk,
+		IDToko:      line.IDToko,
+		IDUser:      userID,
+		Rating:      req.Rating,
+		Komentar:    req.Komentar,
+	}
+
+	err = u.db.Transaction(func(tx *gorm.DB) error {
+		if err := u.repo.Create(ctx, tx, ulasan); err != nil {
+			if helper.IsDuplicateKeyError(err) {
+				return ErrUlasanExists
+			}
+			return err
+		}
+
+		for i := range photos {
+			photos[i].IDUlasan = ulasan.ID
+		}
+		if err := u.repo.CreatePhotos(ctx, tx, photos); err != nil {
+			return err
+		}
+
+		return u.repo.RefreshRating(ctx, tx, ulasan.IDProduk, ulasan.IDToko)
+	})
+
+	if err != nil {
+		return 0, ulasanError(err)
+	}
+
+	return ulasan.ID, nil
+}
+
+// Reply balasan penjual pemilik toko, hanya sekali per ulasan
+func (u *ulasanImpl) Reply(ctx context.Context, ulasanID int, userID int, req *models.BalasUlasanRequest) *helper.ErrorStruct {
+	if err := helper.Validate.Struct(req); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		ulasan, err := u.repo.FindForUpdate(ctx, tx, ulasanID)
+		if err != nil {
+			return err
+		}
+
+		toko, err := u.tokoRepo.GetByID(ctx, ulasan.IDToko)
+		if err != nil || toko.IDUser != userID {
+			return ErrUlasanNotFound
+		}
+
+		if ulasan.Balasan != nil {
+			return ErrUlasanAlreadyReplied
+		}
+
+		return u.repo.Reply(ctx, tx, ulasan.ID, req.Balasan)
+	})
+
+	if err != nil {
+		return ulasanError(err)
+	}
+
+	return nil
+}
+
+// List ulasan produk atau toko, terbaru lebih dulu
+func (u *ulasanImpl) List(ctx context.Context, filter *entity.UlasanFilter) (*models.UlasanListResponse, *helper.ErrorStruct) {
+	if filter.Rating < 0 || filter.Rating > 5 {
+		return nil, &helper.ErrorStruct{Err: errors.New("rating harus 1 sampai 5"), Code: 400}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > maxTrxPageLimit {
+		filter.Limit = maxTrxPageLimit
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	ulasans, total, err := u.repo.List(ctx, filter)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	ids := make([]int, 0, len(ulasans))
+	for _, ul := range ulasans {
+		ids = append(ids, ul.ID)
+	}
+
+	photos, err := u.repo.ListPhotos(ctx, ids)
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	photosByUlasan := make(map[int][]string)
+	for _, p := range photos {
+		photosByUlasan[p.IDUlasan] = append(photosByUlasan[p.IDUlasan], p.Url)
+	}
+
+	res := &models.UlasanListResponse{
+		Data:       make([]models.UlasanResponse, 0, len(ulasans)),
+		Total:      total,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: int((total + int64(filter.Limit) - 1) / int64(filter.Limit)),
+	}
+
+	for _, ul := range ulasans {
+		resp := models.UlasanResponse{
+			ID:          ul.ID,
+			IDProduk:    ul.IDProduk,
+			NamaProduk:  ul.NamaProduk,
+			IDToko:      ul.IDToko,
+			NamaPembeli: ul.NamaPembeli,
+			Rating:      ul.Rating,
+			Komentar:    ul.Komentar,
+			Photos:      photosByUlasan[ul.ID],
+			Balasan:     ul.Balasan,
+			DibalasPada: ul.DibalasPada,
+			CreatedAt:   ul.CreatedAt,
+		}
+		if resp.Photos == nil {
+			resp.Photos = []string{}
+		}
+		res.Data = append(res.Data, resp)
+	}
+
+	return res, nil
+}
+
+// ulasanEligible baris detail_trx hanya bisa diulas pembelinya setelah trx completed,
+// kecuali paket tokonya dibatalkan sehingga barangnya tidak pernah diterima
+func ulasanEligible(line *entity.UlasanLine, userID int) error {
+	if line.IDUser != userID {
+		return ErrUlasanLineNotFound
+	}
+	if line.StatusTrx != entity.TrxStatusCompleted {
+		return ErrUlasanNotCompleted
+	}
+	if line.StatusPaket == entity.TrxStatusCancelled {
+		return ErrUlasanPaketCancelled
+	}
+	return nil
+}
+
+func ulasanError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrUlasanNotFound):
+		return &helper.ErrorStruct{Err: ErrUlasanNotFound, Code: 404}
+	case errors.Is(err, ErrUlasanLineNotFound):
+		return &helper.ErrorStruct{Err: err, Code: 404}
+	case errors.Is(err, ErrUlasanNotCompleted), errors.Is(err, ErrUlasanPaketCancelled), errors.Is(err, ErrUlasanExists), errors.Is(err, ErrUlasanAlreadyReplied):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	}
+	return &helper.ErrorStruct{Err: err, Code: 500}
+}
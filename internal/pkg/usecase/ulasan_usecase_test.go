@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"pbi/internal/pkg/entity"
+)
+
+func TestUlasanEligible(t *testing.T) {
+	tests := []struct {
+		name        string
+		idUser      int
+		statusTrx   string
+		statusPaket string
+		want        error
+		wantCode    int
+	}{
+		{"pesanan selesai", 7, entity.TrxStatusCompleted, entity.TrxStatusCompleted, nil, 0},
+		{"trx lama tanpa paket", 7, entity.TrxStatusCompleted, "", nil, 0},
+		{"bukan pembelinya", 8, entity.TrxStatusCompleted, entity.TrxStatusCompleted, ErrUlasanLineNotFound, 404},
+		{"baru dikirim", 7, entity.TrxStatusShipped, entity.TrxStatusShipped, ErrUlasanNotCompleted, 409},
+		{"sudah sampai belum selesai", 7, entity.TrxStatusDelivered, entity.TrxStatusDelivered, ErrUlasanNotCompleted, 409},
+		{"trx dibatalkan", 7, entity.TrxStatusCancelled, entity.TrxStatusCancelled, ErrUlasanNotCompleted, 409},
+		{"paket toko ditolak", 7, entity.TrxStatusCompleted, entity.TrxStatusCancelled, ErrUlasanPaketCancelled, 409},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := &entity.UlasanLine{IDDetailTrx: 1, IDTrx: 2, IDUser: tt.idUser, StatusTrx: tt.statusTrx, StatusPaket: tt.statusPaket}
+
+			err := ulasanEligible(line, 7)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ulasanEligible = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				if code := ulasanError(err).Code; code != tt.wantCode {
+					t.Errorf("code = %d, want %d", code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pbi/internal/helper"
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVoucherNotFound      = errors.New("voucher tidak ditemukan")
+	ErrVoucherInactive      = errors.New("voucher tidak berlaku")
+	ErrVoucherQuota         = errors.New("kuota voucher sudah habis")
+	ErrVoucherUserLimit     = errors.New("batas pemakaian voucher sudah tercapai")
+	ErrVoucherNotApplicable = errors.New("voucher tidak berlaku untuk produk di pesanan ini")
+	ErrVoucherMinBelanja    = errors.New("total belanja belum memenuhi minimal voucher")
+	ErrVoucherPeriode       = errors.New("berakhir voucher harus setelah mulai")
+	ErrVoucherNilai         = errors.New("voucher percent diisi persen, voucher fixed diisi potongan")
+)
+
+type VoucherUsecase interface {
+	Create(ctx context.Context, userID int, isAdmin bool, req *models.VoucherCreateRequest) (int, *helper.ErrorStruct)
+	List(ctx context.Context, userID int, isAdmin bool) ([]models.VoucherResponse, *helper.ErrorStruct)
+	Deactivate(ctx context.Context, voucherID int, userID int, isAdmin bool) *helper.ErrorStruct
+}
+
+type voucherImpl struct {
+	repo     repository.VoucherRepository
+	tokoRepo repository.TokoRepository
+}
+
+func NewVoucherUsecase(repo repository.VoucherRepository, tokoRepo repository.TokoRepository) VoucherUsecase {
+	return &voucherImpl{
+		repo:     repo,
+		tokoRepo: tokoRepo,
+	}
+}
+
+// Create voucher. Admin bebas memilih cakupan, pemilik toko hanya voucher untuk tokonya sendiri.
+func (v *voucherImpl) Create(ctx context.Context, userID int, isAdmin bool, req *models.VoucherCreateRequest) (int, *helper.ErrorStruct) {
+	if err := helper.Validate.Struct(req); err != nil {
+		return 0, &helper.ErrorStruct{Err: err, Code: 400}
+	}
+
+	if !req.Berakhir.After(req.Mulai) {
+		return 0, &helper.ErrorStruct{Err: ErrVoucherPeriode, Code: 400}
+	}
+
+	// hanya field milik tipenya yang boleh diisi supaya voucher tidak ambigu
+	if (req.Tipe == entity.VoucherTipePercent && req.Potongan != 0) || (req.Tipe == entity.VoucherTipeFixed && req.Persen != 0) {
+		return 0, &helper.ErrorStruct{Err: ErrVoucherNilai, Code: 400}
+	}
+
+	voucher := &entity.Voucher{
+		Kode:         strings.ToUpper(req.Kode),
+		Tipe:         req.Tipe,
+		Persen:       req.Persen,
+		Potongan:     req.Potongan,
+		MinBelanja:   req.MinBelanja,
+		MaksDiskon:   req.MaksDiskon,
+		Kuota:        req.Kuota,
+		LimitPerUser: req.LimitPerUser,
+		Cakupan:      req.Cakupan,
+		Mulai:        req.Mulai,
+		Berakhir:     req.Berakhir,
+		IsActive:     true,
+		DibuatOleh:   userID,
+	}
+
+	switch req.Cakupan {
+	case entity.VoucherCakupanToko:
+		if req.IDToko <= 0 {
+			return 0, &helper.ErrorStruct{Err: errors.New("id_toko wajib diisi untuk voucher toko"), Code: 400}
+		}
+		voucher.IDToko = &req.IDToko
+	case entity.VoucherCakupanCategory:
+		if req.IDCategory <= 0 {
+			return 0, &helper.ErrorStruct{Err: errors.New("id_category wajib diisi untuk voucher kategori"), Code: 400}
+		}
+		voucher.IDCategory = &req.IDCategory
+	}
+
+	if !isAdmin {
+		if req.Cakupan != entity.VoucherCakupanToko {
+			return 0, &helper.ErrorStruct{Err: errors.New("pemilik toko hanya dapat membuat voucher toko"), Code: 403}
+		}
+
+		toko, err := v.tokoRepo.GetByID(ctx, req.IDToko)
+		if err != nil || toko.IDUser != userID {
+			return 0, &helper.ErrorStruct{Err: errors.New("akses ditolak"), Code: 403}
+		}
+	}
+
+	if err := v.repo.Create(ctx, voucher); err != nil {
+		if helper.IsDuplicateKeyError(err) {
+			return 0, &helper.ErrorStruct{Err: errors.New("kode voucher sudah digunakan"), Code: 409}
+		}
+		return 0, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return voucher.ID, nil
+}
+
+// List voucher buatan user, admin melihat semua voucher
+func (v *voucherImpl) List(ctx context.Context, userID int, isAdmin bool) ([]models.VoucherResponse, *helper.ErrorStruct) {
+	var (
+		vouchers []entity.Voucher
+		err      error
+	)
+
+	if isAdmin {
+		vouchers, err = v.repo.ListAll(ctx)
+	} else {
+		vouchers, err = v.repo.ListByCreator(ctx, userID)
+	}
+	if err != nil {
+		return nil, &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	res := make([]models.VoucherResponse, 0, len(vouchers))
+	for _, vc := range vouchers {
+		res = append(res, models.VoucherResponse{
+			ID:           vc.ID,
+			Kode:         vc.Kode,
+			Tipe:         vc.Tipe,
+			Persen:       vc.Persen,
+			Potongan:     vc.Potongan,
+			MinBelanja:   vc.MinBelanja,
+			MaksDiskon:   vc.MaksDiskon,
+			Kuota:        vc.Kuota,
+			Terpakai:     vc.Terpakai,
+			LimitPerUser: vc.LimitPerUser,
+			Cakupan:      vc.Cakupan,
+			IDToko:       vc.IDToko,
+			IDCategory:   vc.IDCategory,
+			Mulai:        vc.Mulai,
+			Berakhir:     vc.Berakhir,
+			IsActive:     vc.IsActive,
+		})
+	}
+
+	return res, nil
+}
+
+func (v *voucherImpl) Deactivate(ctx context.Context, voucherID int, userID int, isAdmin bool) *helper.ErrorStruct {
+	voucher, err := v.repo.FindByID(ctx, voucherID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &helper.ErrorStruct{Err: ErrVoucherNotFound, Code: 404}
+		}
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	if !isAdmin && voucher.DibuatOleh != userID {
+		return &helper.ErrorStruct{Err: ErrVoucherNotFound, Code: 404}
+	}
+
+	if err := v.repo.Deactivate(ctx, voucher.ID); err != nil {
+		return &helper.ErrorStruct{Err: err, Code: 500}
+	}
+
+	return nil
+}
+
+// voucherEngine memvalidasi dan menerapkan voucher di dalam db transaction checkout
+type voucherEngine struct {
+	repo repository.VoucherRepository
+}
+
+func newVoucherEngine(repo repository.VoucherRepository) *voucherEngine {
+	return &voucherEngine{
+		repo: repo,
+	}
+}
+
+// apply mengunci voucher, memvalidasi syarat, lalu membagi diskon ke baris yang memenuhi cakupan.
+// Diskon dibulatkan ke bawah ke rupiah penuh; sisa pembulatan masuk ke baris terakhir.
+func (e *voucherEngine) apply(ctx context.Context, tx *gorm.DB, userID int, kode string, lines []*checkoutLine) (*entity.Voucher, money.Rupiah, error) {
+	voucher, err := e.repo.FindByKodeForUpdate(ctx, tx, strings.ToUpper(kode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrVoucherNotFound
+		}
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	if !voucher.IsActive || now.Before(voucher.Mulai) || now.After(voucher.Berakhir) {
+		return nil, 0, ErrVoucherInactive
+	}
+
+	if voucher.Terpakai >= voucher.Kuota {
+		return nil, 0, ErrVoucherQuota
+	}
+
+	if voucher.LimitPerUser > 0 {
+		used, err := e.repo.CountUsageByUser(ctx, tx, voucher.ID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= int64(voucher.LimitPerUser) {
+			return nil, 0, ErrVoucherUserLimit
+		}
+	}
+
+	var (
+		eligible      []*checkoutLine
+		eligibleTotal money.Rupiah
+	)
+	for _, line := range lines {
+		if voucherCovers(voucher, line) {
+			eligible = append(eligible, line)
+			eligibleTotal += line.Subtotal
+		}
+	}
+
+	if len(eligible) == 0 {
+		return nil, 0, ErrVoucherNotApplicable
+	}
+
+	if eligibleTotal < voucher.MinBelanja {
+		return nil, 0, fmt.Errorf("%w (minimal %s)", ErrVoucherMinBelanja, voucher.MinBelanja)
+	}
+
+	var diskon money.Rupiah
+	if voucher.Tipe == entity.VoucherTipePercent {
+		diskon = eligibleTotal.Percent(voucher.Persen)
+	} else {
+		diskon = voucher.Potongan
+	}
+	if voucher.MaksDiskon > 0 {
+		diskon = money.Min(diskon, voucher.MaksDiskon)
+	}
+	diskon = money.Min(diskon, eligibleTotal)
+
+	// bagi proporsional terhadap subtotal baris
+	weights := make([]money.Rupiah, len(eligible))
+	for i, line := range eligible {
+		weights[i] = line.Subtotal
+	}
+	for i, share := range money.Allocate(diskon, weights) {
+		eligible[i].Diskon = share
+	}
+
+	if err := e.repo.IncrementTerpakai(ctx, tx, voucher.ID); err != nil {
+		return nil, 0, err
+	}
+
+	return voucher, diskon, nil
+}
+
+func (e *voucherEngine) recordUsage(ctx context.Context, tx *gorm.DB, voucher *entity.Voucher, userID int, trxID int, diskon money.Rupiah) error {
+	return e.repo.CreateUsage(ctx, tx, &entity.VoucherUsage{
+		IDVoucher: voucher.ID,
+		IDUser:    userID,
+		IDTrx:     trxID,
+		Diskon:    diskon,
+	})
+}
+
+// release mengembalikan kuota voucher dan jatah per user milik trx yang batal atau expired.
+// Kuota hanya dikembalikan bila baris pemakaiannya masih ada, sehingga aman dipanggil ulang.
+func (e *voucherEngine) release(ctx context.Context, tx *gorm.DB, trx *entity.Transaction) error {
+	if trx.IDVoucher == nil {
+		return nil
+	}
+
+	n, err := e.repo.DeleteUsageByTrx(ctx, tx, trx.ID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	return e.repo.DecrementTerpakai(ctx, tx, *trx.IDVoucher)
+}
+
+func voucherCovers(voucher *entity.Voucher, line *checkoutLine) bool {
+	switch voucher.Cakupan {
+	case entity.VoucherCakupanToko:
+		return voucher.IDToko != nil && *voucher.IDToko == line.Produk.IDToko
+	case entity.VoucherCakupanCategory:
+		return voucher.IDCategory != nil && *voucher.IDCategory == line.Produk.IDCategory
+	}
+	return true
+}
+
+// checkoutError memetakan error dari db transaction checkout ke kode http
+func checkoutError(err error) *helper.ErrorStruct {
+	switch {
+	case errors.Is(err, ErrVoucherNotFound), errors.Is(err, ErrProductNotFound):
+		return &helper.ErrorStruct{Err: err, Code: 404}
+	case errors.Is(err, ErrVoucherInactive), errors.Is(err, ErrVoucherQuota), errors.Is(err, ErrVoucherUserLimit):
+		return &helper.ErrorStruct{Err: err, Code: 409}
+	case errors.Is(err, ErrVoucherNotApplicable), errors.Is(err, ErrVoucherMinBelanja):
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	case errors.Is(err, ErrOwnProduct), errors.Is(err, ErrStockNotEnough):
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	case errors.Is(err, ErrShippingOriginUnset), errors.Is(err, ErrShippingDestinationUnset),
+		errors.Is(err, ErrShippingNotSelected), errors.Is(err, ErrShippingUnavailable), errors.Is(err, ErrShippingRoute):
+		return &helper.ErrorStruct{Err: err, Code: 400}
+	}
+	return &helper.ErrorStruct{Err: err, Code: 500}
+}
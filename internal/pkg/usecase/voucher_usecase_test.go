@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pbi/internal/pkg/entity"
+	"pbi/internal/pkg/models"
+	"pbi/internal/pkg/repository"
+	"pbi/internal/utils/money"
+)
+
+// fakeVoucherRepo mencatat voucher yang disimpan
+type fakeVoucherRepo struct {
+	repository.VoucherRepository
+	created []entity.Voucher
+}
+
+func (f *fakeVoucherRepo) Create(ctx context.Context, voucher *entity.Voucher) error {
+	f.created = append(f.created, *voucher)
+	return nil
+}
+
+func TestVoucherCreatePeriode(t *testing.T) {
+	mulai := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		berakhir time.Time
+		want     error
+	}{
+		{"berakhir setelah mulai", mulai.Add(24 * time.Hour), nil},
+		{"berakhir sama dengan mulai", mulai, ErrVoucherPeriode},
+		{"berakhir sebelum mulai", mulai.Add(-time.Hour), ErrVoucherPeriode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeVoucherRepo{}
+			v := NewVoucherUsecase(repo, nil)
+
+			_, herr := v.Create(context.Background(), 1, true, &models.VoucherCreateRequest{
+				Kode:     "HEMAT10",
+				Tipe:     entity.VoucherTipePercent,
+				Persen:   10,
+				Kuota:    100,
+				Cakupan:  entity.VoucherCakupanPlatform,
+				Mulai:    mulai,
+				Berakhir: tt.berakhir,
+			})
+
+			if tt.want == nil {
+				if herr != nil {
+					t.Fatalf("Create = %v, want nil", herr.Err)
+				}
+				if len(repo.created) != 1 {
+					t.Errorf("voucher disimpan = %d, want 1", len(repo.created))
+				}
+				return
+			}
+
+			if herr == nil || !errors.Is(herr.Err, tt.want) || herr.Code != 400 {
+				t.Fatalf("Create = %+v, want %v (400)", herr, tt.want)
+			}
+			if len(repo.created) != 0 {
+				t.Errorf("voucher disimpan = %d, want 0", len(repo.created))
+			}
+		})
+	}
+}
+
+func TestVoucherCreateNilai(t *testing.T) {
+	tests := []struct {
+		name     string
+		tipe     string
+		persen   float64
+		potongan money.Rupiah
+		wantErr  bool
+	}{
+		{"percent dengan persen", entity.VoucherTipePercent, 12.5, 0, false},
+		{"fixed dengan potongan", entity.VoucherTipeFixed, 0, 15000, false},
+		{"percent tanpa persen", entity.VoucherTipePercent, 0, 0, true},
+		{"percent lebih dari 100", entity.VoucherTipePercent, 150, 0, true},
+		{"percent dengan potongan", entity.VoucherTipePercent, 10, 5000, true},
+		{"fixed tanpa potongan", entity.VoucherTipeFixed, 0, 0, true},
+		{"fixed dengan persen", entity.VoucherTipeFixed, 10, 5000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeVoucherRepo{}
+			v := NewVoucherUsecase(repo, nil)
+
+			mulai := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+			_, herr := v.Create(context.Background(), 1, true, &models.VoucherCreateRequest{
+				Kode:     "HEMAT",
+				Tipe:     tt.tipe,
+				Persen:   tt.persen,
+				Potongan: tt.potongan,
+				Kuota:    100,
+				Cakupan:  entity.VoucherCakupanPlatform,
+				Mulai:    mulai,
+				Berakhir: mulai.Add(24 * time.Hour),
+			})
+
+			if tt.wantErr {
+				if herr == nil || herr.Code != 400 {
+					t.Fatalf("Create = %+v, want 400", herr)
+				}
+				return
+			}
+			if herr != nil {
+				t.Fatalf("Create = %v, want nil", herr.Err)
+			}
+			if got := repo.created[0]; got.Persen != tt.persen || got.Potongan != tt.potongan {
+				t.Errorf("voucher = persen %v potongan %s, want %v %s", got.Persen, got.Potongan, tt.persen, tt.potongan)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AdminReportRoute(r fiber.Router, ReportUsc usecase.AdminReportUsecase) {
+	reportcontroller := controller.NewAdminReportController(ReportUsc)
+
+	rest := r.Group("/admin/reports")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Use(middleware.AdminChecker(true))
+	rest.Get("/gmv", reportcontroller.GMV)
+	rest.Get("/order-status", reportcontroller.OrderStatus)
+	rest.Get("/signups", reportcontroller.Signups)
+	rest.Get("/top-categories", reportcontroller.TopCategories)
+	rest.Get("/top-tokos", reportcontroller.TopTokos)
+	rest.Get("/payment-methods", reportcontroller.PaymentMethods)
+	rest.Get("/reseller-commissions", reportcontroller.ResellerCommissions)
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AnalyticsRoute(r fiber.Router, AnalyticsUsc usecase.AnalyticsUsecase) {
+	analyticscontroller := controller.NewAnalyticsController(AnalyticsUsc)
+
+	rest := r.Group("/toko/my/analytics")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Get("/summary", analyticscontroller.Summary)
+	rest.Get("/sales", analyticscontroller.Sales)
+	rest.Get("/top-products", analyticscontroller.TopProducts)
+	rest.Get("/payment-methods", analyticscontroller.PaymentMethods)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func CartRoute(r fiber.Router, CartUsc usecase.CartUsecase) {
+	cartcontroller := controller.NewCartController(CartUsc)
+
+	rest := r.Group("/cart")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Get("/", cartcontroller.List)
+	rest.Post("/", cartcontroller.Add)
+	rest.Post("/checkout", cartcontroller.Checkout)
+	rest.Put("/:id", cartcontroller.Update)
+	rest.Delete("/:id", cartcontroller.Remove)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func KomisiRoute(r fiber.Router, KomisiUsc usecase.KomisiUsecase) {
+	komisicontroller := controller.NewKomisiController(KomisiUsc)
+
+	rest := r.Group("/komisi")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Get("/", komisicontroller.Riwayat)
+	rest.Get("/saldo", komisicontroller.Saldo)
+}
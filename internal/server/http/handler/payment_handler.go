@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentRoute mendaftarkan route pembayaran. Simulasi wallet mock tidak berautentikasi,
+// jadi hanya didaftarkan saat PAYMENT_MODE=mock.
+func PaymentRoute(r fiber.Router, PayUsc usecase.PaymentUsecase, mockMode bool) {
+	paycontroller := controller.NewPaymentController(PayUsc)
+
+	r.Post("/trx/:id/pay", middleware.AuthChecker(true), paycontroller.Start)
+
+	rest := r.Group("/payments")
+	if mockMode {
+		rest.Post("/mock/:referensi/pay", paycontroller.SimulateMock)
+	}
+	rest.Post("/:provider/callback", paycontroller.Callback)
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func PengirimanRoute(r fiber.Router, KirimUsc usecase.PengirimanUsecase) {
+	kirimcontroller := controller.NewPengirimanController(KirimUsc)
+
+	rest := r.Group("/trx")
+	rest.Get("/:id/pengiriman", middleware.AuthChecker(true), kirimcontroller.ListByTrx)
+}
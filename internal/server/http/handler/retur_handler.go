@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ReturRoute(r fiber.Router, ReturUsc usecase.ReturUsecase) {
+	returcontroller := controller.NewReturController(ReturUsc)
+
+	rest := r.Group("/retur")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Post("/", returcontroller.Create)
+	rest.Get("/", middleware.AdminChecker(true), returcontroller.ListAll)
+	rest.Get("/my", returcontroller.ListMy)
+	rest.Get("/toko", returcontroller.ListToko)
+	rest.Get("/:id", returcontroller.GetByID)
+	rest.Put("/:id/approve", returcontroller.Approve)
+	rest.Put("/:id/reject", returcontroller.Reject)
+	rest.Put("/:id/dispute", returcontroller.Dispute)
+	rest.Put("/:id/resolve", middleware.AdminChecker(true), returcontroller.Resolve)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SaldoRoute(r fiber.Router, SaldoUsc usecase.SaldoUsecase) {
+	saldocontroller := controller.NewSaldoController(SaldoUsc)
+
+	seller := r.Group("/toko/my")
+	seller.Get("/saldo", middleware.AuthChecker(true), saldocontroller.ListSaldo)
+	seller.Get("/saldo/:id_toko/mutasi", middleware.AuthChecker(true), saldocontroller.Mutasi)
+	seller.Post("/rekening", middleware.AuthChecker(true), saldocontroller.AddRekening)
+	seller.Get("/rekening", middleware.AuthChecker(true), saldocontroller.ListRekening)
+	seller.Post("/penarikan", middleware.AuthChecker(true), saldocontroller.RequestPenarikan)
+	seller.Get("/penarikan", middleware.AuthChecker(true), saldocontroller.ListPenarikan)
+
+	admin := r.Group("/admin")
+	admin.Get("/penarikan", middleware.AuthChecker(true), middleware.AdminChecker(true), saldocontroller.ListPenarikanAll)
+	admin.Put("/penarikan/:id/approve", middleware.AuthChecker(true), middleware.AdminChecker(true), saldocontroller.ApprovePenarikan)
+	admin.Put("/penarikan/:id/reject", middleware.AuthChecker(true), middleware.AdminChecker(true), saldocontroller.RejectPenarikan)
+	admin.Put("/penarikan/:id/paid", middleware.AuthChecker(true), middleware.AdminChecker(true), saldocontroller.PayPenarikan)
+	admin.Get("/saldo-toko/:id_toko/rekonsiliasi", middleware.AuthChecker(true), middleware.AdminChecker(true), saldocontroller.Rekonsiliasi)
+}
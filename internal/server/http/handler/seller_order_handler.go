@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SellerOrderRoute(r fiber.Router, SellerUsc usecase.SellerOrderUsecase) {
+	sellercontroller := controller.NewSellerOrderController(SellerUsc)
+
+	rest := r.Group("/toko/my/orders")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Get("/", sellercontroller.List)
+	rest.Put("/:id/accept", sellercontroller.Accept)
+	rest.Put("/:id/reject", sellercontroller.Reject)
+	rest.Put("/:id/ship", sellercontroller.Ship)
+	rest.Put("/:id/deliver", sellercontroller.Deliver)
+	rest.Get("/:id/label", sellercontroller.Label)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ShippingRoute(r fiber.Router, ShippingUsc usecase.ShippingUsecase) {
+	shippingcontroller := controller.NewShippingController(ShippingUsc)
+
+	rest := r.Group("/shipping")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Post("/quote", shippingcontroller.Quote)
+}
@@ -13,6 +13,13 @@ func TransactionRoute(r fiber.Router, TrxUsc usecase.TransactionUsecase) {
 
 	rest :=r.Group("/trx")
 	rest.Get("/",middleware.AuthChecker(true), trxcontroller.GetAll)
+	rest.Get("/export",middleware.AuthChecker(true), trxcontroller.Export)
+	rest.Get("/export/toko",middleware.AuthChecker(true), trxcontroller.ExportToko)
+	rest.Get("/export/all",middleware.AuthChecker(true), middleware.AdminChecker(true), trxcontroller.ExportAll)
 	rest.Get("/:id",middleware.AuthChecker(true), trxcontroller.GetByID)
 	rest.Post("/",middleware.AuthChecker(true), trxcontroller.Create)
+	rest.Put("/:id/status",middleware.AuthChecker(true), trxcontroller.UpdateStatus)
+	rest.Get("/:id/history",middleware.AuthChecker(true), trxcontroller.GetStatusHistory)
+	rest.Post("/:id/cancel",middleware.AuthChecker(true), trxcontroller.Cancel)
+	rest.Get("/:id/invoice",middleware.AuthChecker(true), trxcontroller.GetInvoice)
 }
\ No newline at end of file
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func UlasanRoute(r fiber.Router, UlasanUsc usecase.UlasanUsecase) {
+	ulasancontroller := controller.NewUlasanController(UlasanUsc)
+
+	r.Get("/product/:id/ulasan", ulasancontroller.ListByProduct)
+	r.Get("/toko/:id/ulasan", ulasancontroller.ListByToko)
+
+	rest := r.Group("/ulasan")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Post("/", ulasancontroller.Create)
+	rest.Put("/:id/balasan", ulasancontroller.Reply)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"pbi/internal/config/middleware"
+	"pbi/internal/pkg/controller"
+	"pbi/internal/pkg/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func VoucherRoute(r fiber.Router, VoucherUsc usecase.VoucherUsecase) {
+	vouchercontroller := controller.NewVoucherController(VoucherUsc)
+
+	rest := r.Group("/voucher")
+	rest.Use(middleware.AuthChecker(true))
+	rest.Post("/", vouchercontroller.Create)
+	rest.Get("/my", vouchercontroller.List)
+	rest.Delete("/:id", vouchercontroller.Deactivate)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"pbi/internal/config"
 	"pbi/internal/config/container"
 	rest "pbi/internal/server/http/handler"
 
@@ -15,8 +16,20 @@ func HttpRouteInit(r *fiber.App, containerConf *container.Container) {
 	rest.UserRoute(api, containerConf.UserUsc)
 	rest.CategoryRoute(api, containerConf.CUsc)
 	rest.AddressRoute(api, containerConf.AddrUsc)
+	rest.SellerOrderRoute(api, containerConf.SellerUsc)
+	rest.AnalyticsRoute(api, containerConf.AnalyticsUsc)
+	rest.SaldoRoute(api, containerConf.SaldoUsc)
 	rest.TokoRoute(api, containerConf.TokoUsc)
 	rest.DestinationRoute(api, containerConf.DestUsc)
 	rest.ProductRoute(api, containerConf.PUsc)
 	rest.TransactionRoute(api, containerConf.TrxUsc)
+	rest.PengirimanRoute(api, containerConf.KirimUsc)
+	rest.PaymentRoute(api, containerConf.PayUsc, containerConf.Config.Payment.Mode == config.PaymentModeMock)
+	rest.CartRoute(api, containerConf.CartUsc)
+	rest.VoucherRoute(api, containerConf.VoucherUsc)
+	rest.ShippingRoute(api, containerConf.ShipUsc)
+	rest.ReturRoute(api, containerConf.ReturUsc)
+	rest.UlasanRoute(api, containerConf.UlasanUsc)
+	rest.AdminReportRoute(api, containerConf.ReportUsc)
+	rest.KomisiRoute(api, containerConf.KomisiUsc)
 }
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+)
+
+// outboxDispatchBatch jumlah event yang dikirim per putaran
+const outboxDispatchBatch = 100
+
+// OutboxDispatcher mengirim event outbox yang tertunda ke subscriber
+type OutboxDispatcher struct {
+	outboxUsc usecase.OutboxUsecase
+	interval  time.Duration
+}
+
+func NewOutboxDispatcher(outboxUsc usecase.OutboxUsecase, interval time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxUsc: outboxUsc,
+		interval:  interval,
+	}
+}
+
+// Run berjalan sampai ctx selesai
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch mengulang selama satu batch penuh, event yang sudah diambil disewa sehingga tidak terambil lagi
+func (d *OutboxDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, herr := d.outboxUsc.Dispatch(ctx, outboxDispatchBatch)
+		if herr != nil {
+			helper.LogError(fmt.Errorf("outbox dispatcher: %w", herr.Err))
+		}
+		if n < outboxDispatchBatch {
+			return
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+)
+
+// outboxPurgeInterval jeda penghapusan event delivered yang melewati masa retensi
+const outboxPurgeInterval = time.Hour
+
+// OutboxPurger menghapus event outbox delivered yang melewati OUTBOX_RETENTION_DAYS,
+// berjalan terpisah dari dispatcher supaya retensi tetap jalan walau pengiriman berhenti
+type OutboxPurger struct {
+	outboxUsc usecase.OutboxUsecase
+	interval  time.Duration
+}
+
+func NewOutboxPurger(outboxUsc usecase.OutboxUsecase) *OutboxPurger {
+	return &OutboxPurger{
+		outboxUsc: outboxUsc,
+		interval:  outboxPurgeInterval,
+	}
+}
+
+// Run berjalan sampai ctx selesai
+func (p *OutboxPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, herr := p.outboxUsc.Purge(ctx); herr != nil {
+			helper.LogError(fmt.Errorf("outbox purge: %w", herr.Err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
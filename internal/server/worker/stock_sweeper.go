@@ -0,0 +1,57 @@
+// Package worker berisi proses latar belakang yang berjalan bersama server http.
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+)
+
+// stockSweepBatch jumlah trx yang diproses per putaran
+const stockSweepBatch = 100
+
+// StockSweeper melepas reservasi stok pesanan yang melewati batas bayar
+type StockSweeper struct {
+	trxUsc   usecase.TransactionUsecase
+	interval time.Duration
+}
+
+func NewStockSweeper(trxUsc usecase.TransactionUsecase, interval time.Duration) *StockSweeper {
+	return &StockSweeper{
+		trxUsc:   trxUsc,
+		interval: interval,
+	}
+}
+
+// Run berjalan sampai ctx selesai
+func (s *StockSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep mengulang selama satu batch penuh supaya antrean panjang tidak menunggu tick berikutnya
+func (s *StockSweeper) sweep(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, herr := s.trxUsc.ExpireUnpaid(ctx, stockSweepBatch)
+		if herr != nil {
+			helper.LogError(fmt.Errorf("stock sweeper: %w", herr.Err))
+			return
+		}
+		if n < stockSweepBatch {
+			return
+		}
+	}
+}
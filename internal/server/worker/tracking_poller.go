@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pbi/internal/helper"
+	"pbi/internal/pkg/usecase"
+)
+
+// trackingPollBatch jumlah resi yang dilacak per putaran
+const trackingPollBatch = 50
+
+// TrackingPoller menarik riwayat resi dari kurir dan menandai paket yang sudah sampai
+type TrackingPoller struct {
+	kirimUsc usecase.PengirimanUsecase
+	interval time.Duration
+}
+
+func NewTrackingPoller(kirimUsc usecase.PengirimanUsecase, interval time.Duration) *TrackingPoller {
+	return &TrackingPoller{
+		kirimUsc: kirimUsc,
+		interval: interval,
+	}
+}
+
+// Run berjalan sampai ctx selesai
+func (p *TrackingPoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll mengulang selama satu batch penuh, resi yang sudah dilacak tidak muncul lagi sampai interval berikutnya
+func (p *TrackingPoller) poll(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, herr := p.kirimUsc.PollTracking(ctx, trackingPollBatch)
+		if herr != nil {
+			helper.LogError(fmt.Errorf("tracking poller: %w", herr.Err))
+		}
+		if n < trackingPollBatch {
+			return
+		}
+	}
+}
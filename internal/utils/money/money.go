@@ -0,0 +1,131 @@
+// Package money menyimpan nominal sebagai rupiah bulat (int64) supaya harga, diskon,
+// ongkir dan refund selalu dihitung eksak.
+//
+// Aturan pembulatan, berlaku di seluruh aplikasi:
+//   - Semua nominal disimpan dalam rupiah penuh, tidak ada sen.
+//   - Persentase (diskon voucher) dibaca sampai dua desimal lalu hasilnya dibulatkan ke bawah.
+//   - Pembagian proporsional (diskon per baris) dibulatkan ke bawah per bagian,
+//     sisa pembulatan masuk ke bagian terakhir sehingga jumlahnya selalu tepat total.
+//   - Refund sebagian dihitung kumulatif (Prorate) supaya retur bertahap atas satu baris
+//     tidak pernah melebihi atau kekurangan dari nilai baris tersebut.
+//   - Ongkir dihitung dari tarif rupiah per kg dikali berat yang dibulatkan ke atas per kg.
+//   - Data lama yang masih memiliki sen dibulatkan setengah ke atas (ROUND) saat migrasi ke BIGINT.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Rupiah nominal dalam rupiah penuh
+type Rupiah int64
+
+var ErrNotWholeRupiah = errors.New("nominal harus rupiah bulat tanpa desimal")
+
+// Times harga satuan dikali kuantitas
+func (r Rupiah) Times(qty int) Rupiah {
+	return r * Rupiah(qty)
+}
+
+// MulDiv menghitung r * a / b dibulatkan ke bawah tanpa overflow, b harus > 0
+func (r Rupiah) MulDiv(a, b int64) Rupiah {
+	n := new(big.Int).Mul(big.NewInt(int64(r)), big.NewInt(a))
+	q := new(big.Int)
+	m := new(big.Int)
+	q.DivMod(n, big.NewInt(b), m) // Euclidean: sisa selalu >= 0, jadi q adalah floor
+	return Rupiah(q.Int64())
+}
+
+// Percent persen dari r, persen dibaca sampai dua desimal (basis poin), hasil dibulatkan ke bawah
+func (r Rupiah) Percent(persen float64) Rupiah {
+	return r.MulDiv(int64(math.Round(persen*100)), 10000)
+}
+
+// Prorate bagian r untuk unit ke done+1 .. done+part dari whole unit, dihitung kumulatif
+// sehingga jumlah seluruh bagian sampai whole selalu sama dengan r
+func (r Rupiah) Prorate(done, part, whole int) Rupiah {
+	return r.MulDiv(int64(done+part), int64(whole)) - r.MulDiv(int64(done), int64(whole))
+}
+
+// Allocate membagi total proporsional terhadap weights. Tiap bagian dibulatkan ke bawah,
+// sisa pembulatan masuk ke bagian terakhir.
+func Allocate(total Rupiah, weights []Rupiah) []Rupiah {
+	shares := make([]Rupiah, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	var sum Rupiah
+	for _, w := range weights {
+		sum += w
+	}
+
+	remaining := total
+	for i, w := range weights[:len(weights)-1] {
+		if sum > 0 {
+			shares[i] = total.MulDiv(int64(w), int64(sum))
+		}
+		remaining -= shares[i]
+	}
+	shares[len(shares)-1] = remaining
+
+	return shares
+}
+
+// Min nominal terkecil dari a dan b
+func Min(a, b Rupiah) Rupiah {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// String format tampilan dengan pemisah ribuan titik, contoh Rp 1.250.000
+func (r Rupiah) String() string {
+	n := int64(r)
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+
+	return sign + "Rp " + b.String()
+}
+
+// UnmarshalJSON menerima angka bulat, termasuk penulisan 15000.00, dan menolak pecahan rupiah
+func (r *Rupiah) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+
+	if v, err := n.Int64(); err == nil {
+		*r = Rupiah(v)
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+		return ErrNotWholeRupiah
+	}
+
+	*r = Rupiah(f)
+	return nil
+}
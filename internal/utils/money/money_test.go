@@ -0,0 +1,185 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rupiah
+		a, b int64
+		want Rupiah
+	}{
+		{"habis dibagi", 900, 1, 3, 300},
+		{"dibulatkan ke bawah", 1000, 1, 3, 333},
+		{"dua pertiga", 1000, 2, 3, 666},
+		{"negatif dibulatkan ke bawah", -1000, 1, 3, -334},
+		{"tanpa overflow", 1 << 62, 4, 8, 1 << 61},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.MulDiv(tt.a, tt.b); got != tt.want {
+				t.Errorf("MulDiv(%d, %d) dari %d = %d, want %d", tt.a, tt.b, tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      Rupiah
+		persen float64
+		want   Rupiah
+	}{
+		{"bulat", 15000, 10, 1500},
+		{"hasil dibulatkan ke bawah", 999, 12.5, 124},
+		{"persen dibulatkan setengah ke atas ke basis poin", 10000, 0.125, 13},
+		{"persen di bawah setengah basis poin", 10000, 0.124, 12},
+		{"negatif", -999, 12.5, -125},
+		{"nol persen", 5000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Percent(tt.persen); got != tt.want {
+				t.Errorf("Percent(%v) dari %d = %d, want %d", tt.persen, tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     Rupiah
+		parts []int
+		want  []Rupiah
+	}{
+		{"satu per satu", 1000, []int{1, 1, 1}, []Rupiah{333, 333, 334}},
+		{"bertahap tidak rata", 1000, []int{2, 1}, []Rupiah{666, 334}},
+		{"sekaligus", 1000, []int{3}, []Rupiah{1000}},
+		{"negatif", -1000, []int{1, 1, 1}, []Rupiah{-334, -333, -333}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whole := 0
+			for _, p := range tt.parts {
+				whole += p
+			}
+
+			var sum Rupiah
+			done := 0
+			for i, p := range tt.parts {
+				got := tt.r.Prorate(done, p, whole)
+				if got != tt.want[i] {
+					t.Errorf("bagian %d = %d, want %d", i, got, tt.want[i])
+				}
+				sum += got
+				done += p
+			}
+
+			if sum != tt.r {
+				t.Errorf("jumlah bagian = %d, want %d", sum, tt.r)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   Rupiah
+		weights []Rupiah
+		want    []Rupiah
+	}{
+		{"sisa ke bagian terakhir", 100, []Rupiah{1, 1, 1}, []Rupiah{33, 33, 34}},
+		{"proporsional", 10, []Rupiah{3000, 7000}, []Rupiah{3, 7}},
+		{"bobot tidak rata", 1000, []Rupiah{15000, 25000, 10000}, []Rupiah{300, 500, 200}},
+		{"pecahan tiap bagian", 101, []Rupiah{1, 2, 4}, []Rupiah{14, 28, 59}},
+		{"bobot nol", 50, []Rupiah{0, 0}, []Rupiah{0, 50}},
+		{"satu bagian", 75, []Rupiah{10}, []Rupiah{75}},
+		{"kosong", 75, nil, []Rupiah{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Allocate(tt.total, tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate = %v, want %v", got, tt.want)
+			}
+
+			var sum Rupiah
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bagian %d = %d, want %d", i, got[i], tt.want[i])
+				}
+				sum += got[i]
+			}
+
+			if len(got) > 0 && sum != tt.total {
+				t.Errorf("jumlah bagian = %d, want %d", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		r    Rupiah
+		want string
+	}{
+		{0, "Rp 0"},
+		{999, "Rp 999"},
+		{1000, "Rp 1.000"},
+		{1250000, "Rp 1.250.000"},
+		{-1500, "-Rp 1.500"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("String(%d) = %q, want %q", int64(tt.r), got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Rupiah
+		wantErr error
+	}{
+		{"bulat", "15000", 15000, nil},
+		{"desimal nol", "15000.00", 15000, nil},
+		{"eksponen", "1e3", 1000, nil},
+		{"negatif", "-2500", -2500, nil},
+		{"pecahan ditolak", "15000.5", 0, ErrNotWholeRupiah},
+		{"pecahan kecil ditolak", "0.01", 0, ErrNotWholeRupiah},
+		{"null tidak mengubah nilai", "null", 7, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Rupiah(7)
+			err := r.UnmarshalJSON([]byte(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalJSON(%s) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && r != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %d, want %d", tt.input, r, tt.want)
+			}
+		})
+	}
+
+	t.Run("bukan angka", func(t *testing.T) {
+		var r Rupiah
+		if err := r.UnmarshalJSON([]byte(`"abc"`)); err == nil {
+			t.Error("UnmarshalJSON(\"abc\") tidak mengembalikan error")
+		}
+	})
+}